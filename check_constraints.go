@@ -0,0 +1,485 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// checkTokenKind classifies the lexical pieces of a source CHECK expression.
+type checkTokenKind int
+
+const (
+	checkTokenWord   checkTokenKind = iota // bare word: keyword, function, or column
+	checkTokenIdent                        // quoted identifier (`x`, [x], "x")
+	checkTokenString                       // string literal, already unescaped
+	checkTokenNumber                       // unsigned numeric literal
+	checkTokenOp                           // comparison or arithmetic operator
+	checkTokenLParen
+	checkTokenRParen
+	checkTokenComma
+)
+
+type checkToken struct {
+	kind checkTokenKind
+	text string
+}
+
+// checkKeywords are the bare words allowed in a translatable CHECK expression.
+var checkKeywords = map[string]bool{
+	"AND": true, "OR": true, "NOT": true, "IN": true,
+	"BETWEEN": true, "IS": true, "NULL": true,
+}
+
+// checkComparisonOps maps source comparison operators to PostgreSQL.
+var checkComparisonOps = map[string]string{
+	"=": "=", "==": "=", "<>": "<>", "!=": "<>",
+	"<": "<", ">": ">", "<=": "<=", ">=": ">=",
+}
+
+// checkDialect returns the source dialect key ("mysql", "mssql", "sqlite")
+// used to pick quoting and function rules.
+func checkDialect(src SourceDB) string {
+	return strings.ToLower(src.Name())
+}
+
+// translateCheckExpression rewrites a source CHECK expression into PostgreSQL.
+// Only a conservative subset is accepted: column references, literals,
+// comparisons, AND/OR/NOT, IN lists, BETWEEN, IS [NOT] NULL and the string
+// length functions. Anything else returns an error describing why the
+// constraint has to be recreated by hand.
+func translateCheckExpression(t Table, expr string, src SourceDB, typeMap TypeMappingConfig) (string, error) {
+	dialect := checkDialect(src)
+	tokens, err := tokenizeCheckExpression(expr, dialect)
+	if err != nil {
+		return "", err
+	}
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("empty expression")
+	}
+
+	var b strings.Builder
+	// closers holds the text emitted for each open parenthesis so that
+	// rewritten functions (MSSQL LEN) can close their extra wrapping.
+	var closers []string
+	glue := true
+	emit := func(s string, attach bool) {
+		if !glue && !attach {
+			b.WriteByte(' ')
+		}
+		b.WriteString(s)
+		glue = false
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		var next *checkToken
+		if i+1 < len(tokens) {
+			next = &tokens[i+1]
+		}
+
+		switch tok.kind {
+		case checkTokenWord:
+			upper := strings.ToUpper(tok.text)
+			if checkKeywords[upper] {
+				emit(upper, false)
+				continue
+			}
+			if next != nil && next.kind == checkTokenLParen {
+				fn, closer, ok := checkFunction(upper, dialect)
+				if !ok {
+					return "", fmt.Errorf("unsupported function %s()", strings.ToLower(tok.text))
+				}
+				emit(fn, false)
+				closers = append(closers, closer)
+				glue = true
+				i++
+				continue
+			}
+			ident, err := checkColumnRef(t, tok.text, src, typeMap)
+			if err != nil {
+				return "", err
+			}
+			emit(ident, false)
+		case checkTokenIdent:
+			ident, err := checkColumnRef(t, tok.text, src, typeMap)
+			if err != nil {
+				return "", err
+			}
+			emit(ident, false)
+		case checkTokenString:
+			emit(pgLiteral(tok.text), false)
+		case checkTokenNumber:
+			emit(tok.text, false)
+		case checkTokenOp:
+			if tok.text == "-" && next != nil && next.kind == checkTokenNumber && !checkTokenIsOperand(tokens, i-1) {
+				emit("-"+next.text, false)
+				i++
+				continue
+			}
+			op, ok := checkComparisonOps[tok.text]
+			if !ok {
+				return "", fmt.Errorf("unsupported operator %q", tok.text)
+			}
+			emit(op, false)
+		case checkTokenLParen:
+			emit("(", false)
+			closers = append(closers, ")")
+			glue = true
+		case checkTokenRParen:
+			if len(closers) == 0 {
+				return "", fmt.Errorf("unbalanced parentheses")
+			}
+			emit(closers[len(closers)-1], true)
+			closers = closers[:len(closers)-1]
+		case checkTokenComma:
+			emit(",", true)
+		}
+	}
+	if len(closers) != 0 {
+		return "", fmt.Errorf("unbalanced parentheses")
+	}
+	return b.String(), nil
+}
+
+// checkFunction maps a source function call to its PostgreSQL spelling,
+// including the opening parenthesis, and returns the matching closer.
+// MySQL LENGTH counts bytes; SQLite length and MSSQL LEN count characters,
+// with LEN ignoring trailing spaces.
+func checkFunction(name, dialect string) (string, string, bool) {
+	switch {
+	case name == "CHAR_LENGTH" || name == "CHARACTER_LENGTH":
+		return "char_length(", ")", dialect != "mssql"
+	case name == "LENGTH" && dialect == "mysql":
+		return "octet_length(", ")", true
+	case name == "LENGTH" && dialect == "sqlite":
+		return "char_length(", ")", true
+	case name == "LEN" && dialect == "mssql":
+		return "char_length(rtrim(", "))", true
+	default:
+		return "", "", false
+	}
+}
+
+// checkTokenIsOperand reports whether tokens[i] ends an operand, which makes a
+// following "-" binary subtraction rather than a negative-number sign.
+func checkTokenIsOperand(tokens []checkToken, i int) bool {
+	if i < 0 {
+		return false
+	}
+	switch tokens[i].kind {
+	case checkTokenIdent, checkTokenString, checkTokenNumber, checkTokenRParen:
+		return true
+	case checkTokenWord:
+		return !checkKeywords[strings.ToUpper(tokens[i].text)]
+	default:
+		return false
+	}
+}
+
+// checkColumnRef resolves a source column reference against t and returns the
+// quoted PostgreSQL column name. Columns whose mapped type would change the
+// meaning of numeric or string comparisons are rejected.
+func checkColumnRef(t Table, name string, src SourceDB, typeMap TypeMappingConfig) (string, error) {
+	for _, col := range t.Columns {
+		if !strings.EqualFold(col.SourceName, name) {
+			continue
+		}
+		pgType, err := src.MapType(col, typeMap)
+		if err != nil {
+			return "", fmt.Errorf("column %s: %w", col.SourceName, err)
+		}
+		switch {
+		case pgType == "boolean":
+			return "", fmt.Errorf("column %s maps to boolean", col.SourceName)
+		case pgType == "bytea", strings.HasSuffix(pgType, "[]"):
+			return "", fmt.Errorf("column %s maps to %s", col.SourceName, pgType)
+		}
+		return pgIdent(col.PGName), nil
+	}
+	return "", fmt.Errorf("unknown column or unsupported keyword %q", name)
+}
+
+// tokenizeCheckExpression splits a source CHECK expression into tokens using
+// the quoting rules of dialect.
+func tokenizeCheckExpression(expr, dialect string) ([]checkToken, error) {
+	var tokens []checkToken
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, checkToken{kind: checkTokenLParen, text: "("})
+			i++
+		case c == ')':
+			tokens = append(tokens, checkToken{kind: checkTokenRParen, text: ")"})
+			i++
+		case c == ',':
+			tokens = append(tokens, checkToken{kind: checkTokenComma, text: ","})
+			i++
+		case c == '`' && dialect == "mysql":
+			text, n, err := scanQuoted(expr[i:], '`', '`', false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenIdent, text: text})
+			i += n
+		case c == '[' && dialect == "mssql":
+			text, n, err := scanQuoted(expr[i:], '[', ']', false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenIdent, text: text})
+			i += n
+		case c == '"' && dialect != "mysql":
+			text, n, err := scanQuoted(expr[i:], '"', '"', false)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenIdent, text: text})
+			i += n
+		case c == '\'':
+			text, n, err := scanQuoted(expr[i:], '\'', '\'', dialect == "mysql")
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenString, text: text})
+			i += n
+		case c >= '0' && c <= '9' || c == '.' && i+1 < len(expr) && expr[i+1] >= '0' && expr[i+1] <= '9':
+			n := scanCheckNumber(expr[i:])
+			if n == 0 {
+				return nil, fmt.Errorf("invalid numeric literal at %q", expr[i:])
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenNumber, text: expr[i : i+n]})
+			i += n
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(expr) && (expr[j] == '_' || expr[j] == '$' || expr[j] >= 'a' && expr[j] <= 'z' || expr[j] >= 'A' && expr[j] <= 'Z' || expr[j] >= '0' && expr[j] <= '9') {
+				j++
+			}
+			word := expr[i:j]
+			// MySQL charset introducers (_utf8mb4'x') and MSSQL unicode
+			// literals (N'x') only prefix a string; drop them.
+			if j < len(expr) && expr[j] == '\'' &&
+				(dialect == "mysql" && word[0] == '_' || dialect == "mssql" && strings.EqualFold(word, "N")) {
+				i = j
+				continue
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenWord, text: word})
+			i = j
+		default:
+			op := ""
+			for _, candidate := range []string{"->>", "->", "||", "<=", ">=", "<>", "!=", "==", "=", "<", ">", "+", "-", "*", "/", "%", "."} {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q", c)
+			}
+			tokens = append(tokens, checkToken{kind: checkTokenOp, text: op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+// scanQuoted reads a quoted token starting at s[0] == open. A doubled close
+// character is an escaped literal one; with backslashEscapes, MySQL-style
+// \' \" and \\ are also unescaped. It returns the unquoted text and the
+// number of bytes consumed.
+func scanQuoted(s string, open, close byte, backslashEscapes bool) (string, int, error) {
+	var b strings.Builder
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		if backslashEscapes && c == '\\' && i+1 < len(s) {
+			switch s[i+1] {
+			case '\\', '\'', '"':
+				b.WriteByte(s[i+1])
+				i++
+				continue
+			default:
+				return "", 0, fmt.Errorf("unsupported escape sequence \\%c", s[i+1])
+			}
+		}
+		if c == close {
+			if i+1 < len(s) && s[i+1] == close {
+				b.WriteByte(close)
+				i++
+				continue
+			}
+			return b.String(), i + 1, nil
+		}
+		b.WriteByte(c)
+	}
+	return "", 0, fmt.Errorf("unterminated %c%c quoted text", open, close)
+}
+
+// scanCheckNumber returns the length of the decimal literal at the start of
+// s, or 0 when it is not a plain decimal number (for example 0x1F).
+func scanCheckNumber(s string) int {
+	i := 0
+	digits := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+		digits++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+			digits++
+		}
+	}
+	if digits == 0 {
+		return 0
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		start := j
+		for j < len(s) && s[j] >= '0' && s[j] <= '9' {
+			j++
+		}
+		if j == start {
+			return 0
+		}
+		i = j
+	}
+	if i < len(s) && (s[i] == '_' || s[i] >= 'a' && s[i] <= 'z' || s[i] >= 'A' && s[i] <= 'Z') {
+		return 0
+	}
+	return i
+}
+
+// parseSQLiteCheckConstraints extracts the CHECK clauses from a SQLite
+// CREATE TABLE statement. Constraint names come from a preceding
+// CONSTRAINT <name>; unnamed checks are numbered <table>_chk_<n> the way
+// MySQL names them.
+func parseSQLiteCheckConstraints(tableName, createSQL string) []CheckConstraint {
+	var checks []CheckConstraint
+	var pendingName string
+	var prevWord string
+	unnamed := 0
+
+	for i := 0; i < len(createSQL); {
+		c := createSQL[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			text, n, err := scanQuoted(createSQL[i:], c, c, false)
+			if err != nil {
+				return checks
+			}
+			if prevWord == "CONSTRAINT" {
+				pendingName = text
+			}
+			prevWord = ""
+			i += n
+		case c == '[':
+			text, n, err := scanQuoted(createSQL[i:], '[', ']', false)
+			if err != nil {
+				return checks
+			}
+			if prevWord == "CONSTRAINT" {
+				pendingName = text
+			}
+			prevWord = ""
+			i += n
+		case c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+			j := i + 1
+			for j < len(createSQL) && (createSQL[j] == '_' || createSQL[j] == '$' || createSQL[j] >= 'a' && createSQL[j] <= 'z' || createSQL[j] >= 'A' && createSQL[j] <= 'Z' || createSQL[j] >= '0' && createSQL[j] <= '9') {
+				j++
+			}
+			word := createSQL[i:j]
+			upper := strings.ToUpper(word)
+			i = j
+			if prevWord == "CONSTRAINT" {
+				pendingName = word
+				prevWord = ""
+				continue
+			}
+			if upper != "CHECK" {
+				if upper != "CONSTRAINT" {
+					pendingName = ""
+				}
+				prevWord = upper
+				continue
+			}
+			for i < len(createSQL) && strings.IndexByte(" \t\r\n", createSQL[i]) >= 0 {
+				i++
+			}
+			if i >= len(createSQL) || createSQL[i] != '(' {
+				prevWord = upper
+				continue
+			}
+			end := sqliteMatchingParen(createSQL, i)
+			if end < 0 {
+				return checks
+			}
+			expr := strings.TrimSpace(createSQL[i+1 : end])
+			name := pendingName
+			if name == "" {
+				unnamed++
+				name = fmt.Sprintf("%s_chk_%d", tableName, unnamed)
+			}
+			checks = append(checks, CheckConstraint{SourceName: name, Expression: expr})
+			pendingName = ""
+			prevWord = ""
+			i = end + 1
+		default:
+			if c != ' ' && c != '\t' && c != '\r' && c != '\n' {
+				prevWord = ""
+			}
+			i++
+		}
+	}
+	return checks
+}
+
+// sqliteMatchingParen returns the index of the parenthesis closing s[open],
+// skipping quoted text, or -1 when it is unbalanced.
+func sqliteMatchingParen(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		case '\'', '"', '`', '[':
+			close := c
+			if c == '[' {
+				close = ']'
+			}
+			_, n, err := scanQuoted(s[i:], c, close, false)
+			if err != nil {
+				return -1
+			}
+			i += n - 1
+		}
+	}
+	return -1
+}
+
+// collectCheckConstraintWarnings lists CHECK constraints that cannot be
+// translated and therefore will not be created in PostgreSQL.
+func collectCheckConstraintWarnings(schema *Schema, src SourceDB, typeMap TypeMappingConfig) []string {
+	var warnings []string
+	for _, t := range schema.Tables {
+		for _, ck := range t.CheckConstraints {
+			if _, err := translateCheckExpression(t, ck.Expression, src, typeMap); err != nil {
+				warnings = append(warnings, fmt.Sprintf(
+					"check constraint %s.%s (%s) cannot be translated: %v",
+					t.SourceName, ck.SourceName, ck.Expression, err,
+				))
+			}
+		}
+	}
+	return warnings
+}
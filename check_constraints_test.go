@@ -0,0 +1,259 @@
+package main
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTranslateCheckExpression(t *testing.T) {
+	table := Table{
+		SourceName: "Orders",
+		PGName:     "orders",
+		Columns: []Column{
+			{SourceName: "Qty", PGName: "qty", DataType: "int", ColumnType: "int"},
+			{SourceName: "Price", PGName: "price", DataType: "decimal", ColumnType: "decimal(10,2)", Precision: 10, Scale: 2},
+			{SourceName: "Status", PGName: "status", DataType: "varchar", ColumnType: "varchar(16)", CharMaxLen: 16},
+			{SourceName: "Code", PGName: "code", DataType: "varchar", ColumnType: "varchar(8)", CharMaxLen: 8},
+			{SourceName: "Active", PGName: "active", DataType: "tinyint", ColumnType: "tinyint(1)"},
+		},
+	}
+	mssqlTable := Table{
+		SourceName: "Orders",
+		PGName:     "orders",
+		Columns: []Column{
+			{SourceName: "Qty", PGName: "qty", DataType: "int"},
+			{SourceName: "Code", PGName: "code", DataType: "nvarchar", CharMaxLen: 8},
+			{SourceName: "Flag", PGName: "flag", DataType: "bit"},
+		},
+	}
+	sqliteTable := Table{
+		SourceName: "orders",
+		PGName:     "orders",
+		Columns: []Column{
+			{SourceName: "qty", PGName: "qty", DataType: "integer", ColumnType: "integer"},
+			{SourceName: "name", PGName: "name", DataType: "text", ColumnType: "text"},
+		},
+	}
+	boolMap := defaultTypeMappingConfig()
+	boolMap.TinyInt1AsBoolean = true
+
+	tests := []struct {
+		name    string
+		table   Table
+		src     SourceDB
+		typeMap TypeMappingConfig
+		expr    string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "mysql comparison",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: "(`Qty` > 0)",
+			want: `("qty" > 0)`,
+		},
+		{
+			name:  "mysql in list with charset introducers",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: "(`Status` in (_utf8mb4'new',_utf8mb4'it\\'s'))",
+			want: `("status" IN ('new', 'it''s'))`,
+		},
+		{
+			name:  "mysql between negative and not-equal",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: "((`Price` between -1.5 and 1e3) and (`Qty` != 7))",
+			want: `(("price" BETWEEN -1.5 AND 1e3) AND ("qty" <> 7))`,
+		},
+		{
+			name:  "mysql length counts bytes",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: "((length(`Code`) = 3) or (char_length(`Status`) > 0))",
+			want: `((octet_length("code") = 3) OR (char_length("status") > 0))`,
+		},
+		{
+			name:  "mysql is not null",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: "(`Code` is not null)",
+			want: `("code" IS NOT NULL)`,
+		},
+		{
+			name:  "mysql tinyint1 stays numeric without boolean mapping",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: "(`Active` in (0,1))",
+			want: `("active" IN (0, 1))`,
+		},
+		{
+			name:  "mysql boolean-mapped column rejected",
+			table: table, src: &mysqlSourceDB{}, typeMap: boolMap,
+			expr:    "(`Active` in (0,1))",
+			wantErr: "maps to boolean",
+		},
+		{
+			name:  "mysql unsupported function",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr:    "json_valid(`Code`)",
+			wantErr: "unsupported function json_valid()",
+		},
+		{
+			name:  "mysql like rejected",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr:    "(`Code` like _utf8mb4'A%')",
+			wantErr: `unsupported keyword "like"`,
+		},
+		{
+			name:  "mysql arithmetic rejected",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr:    "((`Qty` * `Price`) < 100)",
+			wantErr: `unsupported operator "*"`,
+		},
+		{
+			name:  "unknown column rejected",
+			table: table, src: &mysqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr:    "(`Missing` > 0)",
+			wantErr: `unknown column or unsupported keyword "Missing"`,
+		},
+		{
+			name:  "mssql bracket idents and parenthesized literals",
+			table: mssqlTable, src: &mssqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: "([Qty]>=(0) AND [Qty]<=(100))",
+			want: `("qty" >= (0) AND "qty" <= (100))`,
+		},
+		{
+			name:  "mssql len ignores trailing spaces",
+			table: mssqlTable, src: &mssqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: "(len([Code])=(3) OR [Code]=N'none')",
+			want: `(char_length(rtrim("code")) = (3) OR "code" = 'none')`,
+		},
+		{
+			name:  "mssql bit column rejected",
+			table: mssqlTable, src: &mssqlSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr:    "([Flag]=(1))",
+			wantErr: "maps to boolean",
+		},
+		{
+			name:  "sqlite bare and quoted identifiers",
+			table: sqliteTable, src: &sqliteSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: `qty >= 0 AND length("name") > 0 AND name NOT IN ('x', 'y')`,
+			want: `"qty" >= 0 AND char_length("name") > 0 AND "name" NOT IN ('x', 'y')`,
+		},
+		{
+			name:  "sqlite double equals",
+			table: sqliteTable, src: &sqliteSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr: `qty == 1`,
+			want: `"qty" = 1`,
+		},
+		{
+			name:  "unbalanced parentheses rejected",
+			table: sqliteTable, src: &sqliteSourceDB{}, typeMap: defaultTypeMappingConfig(),
+			expr:    `(qty > 0`,
+			wantErr: "unbalanced parentheses",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := translateCheckExpression(tt.table, tt.expr, tt.src, tt.typeMap)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("translateCheckExpression(%q) error = %v, want %q", tt.expr, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("translateCheckExpression(%q): %v", tt.expr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("translateCheckExpression(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSQLiteCheckConstraints(t *testing.T) {
+	createSQL := `CREATE TABLE "items" (
+		id INTEGER PRIMARY KEY,
+		qty INTEGER CHECK (qty >= 0),
+		name TEXT CONSTRAINT name_not_blank CHECK(length(name) > 0),
+		note TEXT DEFAULT 'CHECK (ignored)',
+		CONSTRAINT "pk_guard" UNIQUE (name),
+		CHECK (qty < 1000 OR name IN ('bulk', ')'))
+	)`
+
+	checks := parseSQLiteCheckConstraints("items", createSQL)
+	if len(checks) != 3 {
+		t.Fatalf("checks = %+v, want 3", checks)
+	}
+	want := []CheckConstraint{
+		{SourceName: "items_chk_1", Expression: "qty >= 0"},
+		{SourceName: "name_not_blank", Expression: "length(name) > 0"},
+		{SourceName: "items_chk_2", Expression: "qty < 1000 OR name IN ('bulk', ')')"},
+	}
+	for i := range want {
+		if checks[i] != want[i] {
+			t.Fatalf("check %d = %+v, want %+v", i, checks[i], want[i])
+		}
+	}
+}
+
+func TestSQLiteIntrospectCheckConstraints(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE StockItems (
+		ItemID INTEGER PRIMARY KEY,
+		Qty INTEGER NOT NULL,
+		CONSTRAINT QtyPositive CHECK (Qty > 0)
+	)`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	src := &sqliteSourceDB{snakeCaseIDs: true}
+	schema, err := src.IntrospectSchema(db, "")
+	if err != nil {
+		t.Fatalf("IntrospectSchema: %v", err)
+	}
+	items := findSchemaTable(t, schema, "StockItems")
+	if len(items.CheckConstraints) != 1 {
+		t.Fatalf("check constraints = %+v, want 1", items.CheckConstraints)
+	}
+	ck := items.CheckConstraints[0]
+	if ck.Name != "qty_positive" || ck.SourceName != "QtyPositive" || ck.Expression != "Qty > 0" {
+		t.Fatalf("check = %+v", ck)
+	}
+	got, err := translateCheckExpression(*items, ck.Expression, src, defaultTypeMappingConfig())
+	if err != nil {
+		t.Fatalf("translateCheckExpression: %v", err)
+	}
+	if got != `"qty" > 0` {
+		t.Fatalf("translated = %q, want %q", got, `"qty" > 0`)
+	}
+}
+
+func TestCollectCheckConstraintWarnings(t *testing.T) {
+	schema := &Schema{
+		Tables: []Table{
+			{
+				SourceName: "orders",
+				PGName:     "orders",
+				Columns:    []Column{{SourceName: "qty", PGName: "qty", DataType: "int", ColumnType: "int"}},
+				CheckConstraints: []CheckConstraint{
+					{Name: "orders_chk_1", SourceName: "orders_chk_1", Expression: "(`qty` > 0)"},
+					{Name: "orders_chk_2", SourceName: "orders_chk_2", Expression: "(`qty` % 2 = 0)"},
+				},
+			},
+		},
+	}
+
+	warnings := collectCheckConstraintWarnings(schema, mysqlSrc, defaultTypeMappingConfig())
+	if len(warnings) != 1 {
+		t.Fatalf("warnings = %v, want 1", warnings)
+	}
+	if !strings.Contains(warnings[0], "orders.orders_chk_2") || !strings.Contains(warnings[0], `unsupported operator "%"`) {
+		t.Fatalf("warning = %q", warnings[0])
+	}
+}
@@ -71,8 +71,9 @@ func planChunks(min, max, chunkSize int64) []Chunk {
 
 // buildChunkedSelectQuery builds a SELECT query for a single chunk of a table.
 func buildChunkedSelectQuery(src SourceDB, table Table, key ChunkKey, chunk Chunk, typeMap TypeMappingConfig) string {
-	cols := make([]string, len(table.Columns))
-	for i, col := range table.Columns {
+	columns := copyColumns(table)
+	cols := make([]string, len(columns))
+	for i, col := range columns {
 		cols[i] = columnSelectExpr(src, col, typeMap)
 	}
 
@@ -12,27 +12,35 @@ import (
 
 // MigrationConfig holds the full TOML-driven migration configuration.
 type MigrationConfig struct {
-	Source                            SourceConfig      `toml:"source"`
-	Target                            TargetConfig      `toml:"target"`
-	PostGIS                           PostGISConfig     `toml:"postgis"`
-	Schema                            string            `toml:"schema"`
-	OnSchemaExists                    string            `toml:"on_schema_exists"`
-	SchemaOnly                        bool              `toml:"schema_only"`
-	DataOnly                          bool              `toml:"data_only"`
-	SourceSnapshotMode                string            `toml:"source_snapshot_mode"` // none|single_tx
-	UnloggedTables                    bool              `toml:"unlogged_tables"`
-	PreserveDefaults                  bool              `toml:"preserve_defaults"`
-	AddUnsignedChecks                 bool              `toml:"add_unsigned_checks"`
-	CleanOrphans                      bool              `toml:"clean_orphans"`
-	SnakeCaseIdentifiers              bool              `toml:"snake_case_identifiers"`
-	ReplicateOnUpdateCurrentTimestamp bool              `toml:"replicate_on_update_current_timestamp"`
-	Workers                           int               `toml:"workers"`
-	IndexWorkers                      int               `toml:"index_workers"`
-	ChunkSize                         int64             `toml:"chunk_size"`
-	Resume                            bool              `toml:"resume"`
-	Validation                        string            `toml:"validation"` // none|row_count
-	Hooks                             HooksConfig       `toml:"hooks"`
-	TypeMapping                       TypeMappingConfig `toml:"type_mapping"`
+	Source                            SourceConfig         `toml:"source"`
+	Target                            TargetConfig         `toml:"target"`
+	PostGIS                           PostGISConfig        `toml:"postgis"`
+	Schema                            string               `toml:"schema"`
+	OnSchemaExists                    string               `toml:"on_schema_exists"`
+	SchemaOnly                        bool                 `toml:"schema_only"`
+	DataOnly                          bool                 `toml:"data_only"`
+	SourceSnapshotMode                string               `toml:"source_snapshot_mode"` // none|single_tx
+	UnloggedTables                    bool                 `toml:"unlogged_tables"`
+	PreserveDefaults                  bool                 `toml:"preserve_defaults"`
+	AddUnsignedChecks                 bool                 `toml:"add_unsigned_checks"`
+	CleanOrphans                      bool                 `toml:"clean_orphans"`
+	SnakeCaseIdentifiers              bool                 `toml:"snake_case_identifiers"`
+	ReplicateOnUpdateCurrentTimestamp bool                 `toml:"replicate_on_update_current_timestamp"`
+	FullTextMode                      string               `toml:"fulltext_mode"`           // off|expression|generated_column
+	FullTextConfig                    string               `toml:"fulltext_config"`         // text search configuration for to_tsvector
+	IdentityMode                      string               `toml:"identity_mode"`           // sequence|identity
+	FKValidation                      string               `toml:"fk_validation"`           // immediate|parallel
+	DeferrableForeignKeys             string               `toml:"deferrable_foreign_keys"` // none|cycles|all
+	UniqueMode                        string               `toml:"unique_mode"`             // index|constraint
+	Workers                           int                  `toml:"workers"`
+	IndexWorkers                      int                  `toml:"index_workers"`
+	ChunkSize                         int64                `toml:"chunk_size"`
+	Resume                            bool                 `toml:"resume"`
+	Validation                        string               `toml:"validation"` // none|row_count|checksum|sample|aggregate
+	ValidationSampleSize              int                  `toml:"validation_sample_size"`
+	Hooks                             HooksConfig          `toml:"hooks"`
+	TypeMapping                       TypeMappingConfig    `toml:"type_mapping"`
+	Partitioning                      []PartitioningConfig `toml:"partitioning"`
 
 	// configDir is the directory containing the TOML file, used to resolve relative SQL paths.
 	configDir string
@@ -89,6 +97,30 @@ type TypeMappingConfig struct {
 
 	// UsePostGIS is derived from the top-level [postgis] feature config.
 	UsePostGIS bool `toml:"-"`
+	// FullTextMode and FullTextConfig are derived from the top-level
+	// fulltext_mode and fulltext_config settings.
+	FullTextMode   string `toml:"-"`
+	FullTextConfig string `toml:"-"`
+	// IdentityMode is derived from the top-level identity_mode setting.
+	IdentityMode string `toml:"-"`
+}
+
+// PartitioningConfig partitions one target table, whether or not the source
+// table is partitioned.
+type PartitioningConfig struct {
+	Table      string                `toml:"table"`      // source or PostgreSQL table name
+	Method     string                `toml:"method"`     // range|list|hash
+	Key        string                `toml:"key"`        // partition key column
+	Interval   string                `toml:"interval"`   // range: day|week|month|year or an integer step
+	Bounds     []string              `toml:"bounds"`     // range: ascending boundaries instead of interval
+	Lists      []PartitionListConfig `toml:"list"`       // list: one entry per partition
+	Partitions int                   `toml:"partitions"` // hash: number of partitions
+}
+
+// PartitionListConfig is one partition of a list-partitioned table.
+type PartitionListConfig struct {
+	Name   string   `toml:"name"`
+	Values []string `toml:"values"`
 }
 
 // loadConfig reads a TOML config file and returns a MigrationConfig with defaults applied.
@@ -130,6 +162,9 @@ func defaultMigrationConfig() MigrationConfig {
 		PreserveDefaults:     true,
 		CleanOrphans:         true,
 		SnakeCaseIdentifiers: true,
+		FullTextMode:         "off",
+		FullTextConfig:       "simple",
+		IdentityMode:         "sequence",
 		TypeMapping:          defaultTypeMappingConfig(),
 	}
 }
@@ -154,6 +189,9 @@ func finalizeConfig(cfg *MigrationConfig, configDir string) error {
 	if cfg.Validation == "" {
 		cfg.Validation = "none"
 	}
+	if cfg.ValidationSampleSize <= 0 {
+		cfg.ValidationSampleSize = 100
+	}
 	if cfg.Source.Type == "" {
 		return fmt.Errorf("source.type is required (must be mysql, sqlite, or mssql)")
 	}
@@ -236,10 +274,66 @@ func finalizeConfig(cfg *MigrationConfig, configDir string) error {
 		return fmt.Errorf("type_mapping.spatial_mode must be one of: off, wkb_bytea, wkt_text")
 	}
 
+	if cfg.FullTextMode == "" {
+		cfg.FullTextMode = "off"
+	}
+	switch cfg.FullTextMode {
+	case "off", "expression", "generated_column":
+	default:
+		return fmt.Errorf("fulltext_mode must be one of: off, expression, generated_column")
+	}
+	cfg.FullTextConfig = strings.TrimSpace(cfg.FullTextConfig)
+	if cfg.FullTextConfig == "" {
+		cfg.FullTextConfig = "simple"
+	}
+
+	if cfg.IdentityMode == "" {
+		cfg.IdentityMode = "sequence"
+	}
+	switch cfg.IdentityMode {
+	case "sequence", "identity":
+	default:
+		return fmt.Errorf("identity_mode must be one of: sequence, identity")
+	}
+
+	if cfg.FKValidation == "" {
+		cfg.FKValidation = "immediate"
+	}
+	switch cfg.FKValidation {
+	case "immediate", "parallel":
+	default:
+		return fmt.Errorf("fk_validation must be one of: immediate, parallel")
+	}
+
+	if cfg.DeferrableForeignKeys == "" {
+		cfg.DeferrableForeignKeys = "none"
+	}
+	switch cfg.DeferrableForeignKeys {
+	case "none", "cycles", "all":
+	default:
+		return fmt.Errorf("deferrable_foreign_keys must be one of: none, cycles, all")
+	}
+
+	if cfg.UniqueMode == "" {
+		cfg.UniqueMode = "index"
+	}
+	switch cfg.UniqueMode {
+	case "index", "constraint":
+	default:
+		return fmt.Errorf("unique_mode must be one of: index, constraint")
+	}
+
+	if err := validatePartitioningConfig(cfg.Partitioning); err != nil {
+		return err
+	}
+
 	switch cfg.Validation {
-	case "none", "row_count":
+	case "none", "row_count", "checksum", "sample", "aggregate":
 	default:
-		return fmt.Errorf("validation must be one of: none, row_count")
+		return fmt.Errorf("validation must be one of: none, row_count, checksum, sample, aggregate")
+	}
+	if cfg.ValidationSampleSize > 10000 {
+		return fmt.Errorf("validation_sample_size must be at most 10000")
 	}
 
 	if cfg.SchemaOnly && cfg.DataOnly {
@@ -318,6 +412,57 @@ func finalizeConfig(cfg *MigrationConfig, configDir string) error {
 	return nil
 }
 
+// validatePartitioningConfig checks every [[partitioning]] entry and
+// normalizes its method to lower case.
+func validatePartitioningConfig(configs []PartitioningConfig) error {
+	seen := make(map[string]bool)
+	for i := range configs {
+		pc := &configs[i]
+		pc.Table = strings.TrimSpace(pc.Table)
+		pc.Key = strings.TrimSpace(pc.Key)
+		pc.Method = strings.ToLower(strings.TrimSpace(pc.Method))
+		pc.Interval = strings.ToLower(strings.TrimSpace(pc.Interval))
+		if pc.Table == "" {
+			return fmt.Errorf("partitioning[%d].table is required", i)
+		}
+		if seen[pc.Table] {
+			return fmt.Errorf("partitioning: table %q is configured more than once", pc.Table)
+		}
+		seen[pc.Table] = true
+		if pc.Key == "" {
+			return fmt.Errorf("partitioning %s: key is required", pc.Table)
+		}
+		switch pc.Method {
+		case "range":
+			if (pc.Interval == "") == (len(pc.Bounds) == 0) {
+				return fmt.Errorf("partitioning %s: range partitioning needs exactly one of interval or bounds", pc.Table)
+			}
+			if pc.Interval != "" && !isPartitionInterval(pc.Interval) {
+				return fmt.Errorf("partitioning %s: interval must be one of: day, week, month, year, or a positive integer", pc.Table)
+			}
+			if len(pc.Bounds) == 1 {
+				return fmt.Errorf("partitioning %s: bounds needs at least two values", pc.Table)
+			}
+		case "list":
+			if len(pc.Lists) == 0 {
+				return fmt.Errorf("partitioning %s: list partitioning needs at least one [[partitioning.list]] entry", pc.Table)
+			}
+			for _, l := range pc.Lists {
+				if strings.TrimSpace(l.Name) == "" || len(l.Values) == 0 {
+					return fmt.Errorf("partitioning %s: every list entry needs a name and values", pc.Table)
+				}
+			}
+		case "hash":
+			if pc.Partitions < 2 {
+				return fmt.Errorf("partitioning %s: hash partitioning needs partitions >= 2", pc.Table)
+			}
+		default:
+			return fmt.Errorf("partitioning %s: method must be one of: range, list, hash", pc.Table)
+		}
+	}
+	return nil
+}
+
 // resolvePath resolves a path relative to the config file directory.
 func (c *MigrationConfig) resolvePath(p string) string {
 	if filepath.IsAbs(p) {
@@ -378,5 +523,8 @@ func effectiveTypeMappingForSource(typeMap TypeMappingConfig, sourceType string)
 func effectiveTypeMapping(cfg *MigrationConfig) TypeMappingConfig {
 	tm := effectiveTypeMappingForSource(cfg.TypeMapping, cfg.Source.Type)
 	tm.UsePostGIS = cfg.PostGIS.Enabled
+	tm.FullTextMode = cfg.FullTextMode
+	tm.FullTextConfig = cfg.FullTextConfig
+	tm.IdentityMode = cfg.IdentityMode
 	return tm
 }
@@ -0,0 +1,528 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SchemaIssue is one difference between the schema pgferry would generate from
+// the source and what the PostgreSQL catalog currently contains.
+type SchemaIssue struct {
+	Table    string `json:"table"`
+	Kind     string `json:"kind"`
+	Object   string `json:"object,omitempty"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+}
+
+func (i SchemaIssue) describe() string {
+	switch i.Kind {
+	case "missing_table":
+		return fmt.Sprintf("%s: table missing", i.Table)
+	case "missing_column":
+		return fmt.Sprintf("%s.%s: column missing (expected %s)", i.Table, i.Object, i.Expected)
+	case "extra_column":
+		return fmt.Sprintf("%s.%s: extra column (%s)", i.Table, i.Object, i.Actual)
+	case "type":
+		return fmt.Sprintf("%s.%s: type %s expected, found %s", i.Table, i.Object, i.Expected, i.Actual)
+	case "nullability":
+		return fmt.Sprintf("%s.%s: %s expected, found %s", i.Table, i.Object, i.Expected, i.Actual)
+	case "collation":
+		return fmt.Sprintf("%s.%s: collation %s expected, found %s", i.Table, i.Object, i.Expected, i.Actual)
+	case "missing_primary_key":
+		return fmt.Sprintf("%s: primary key (%s) missing", i.Table, i.Expected)
+	case "missing_index":
+		return fmt.Sprintf("%s: index %s (%s) missing", i.Table, i.Object, i.Expected)
+	case "missing_foreign_key":
+		return fmt.Sprintf("%s: foreign key %s %s missing", i.Table, i.Object, i.Expected)
+	case "missing_sequence":
+		if i.Table == "" {
+			return fmt.Sprintf("sequence %s missing", i.Object)
+		}
+		return fmt.Sprintf("%s: sequence %s missing", i.Table, i.Object)
+	case "missing_identity":
+		return fmt.Sprintf("%s: column %s is not an identity column", i.Table, i.Object)
+	default:
+		return fmt.Sprintf("%s: %s %s", i.Table, i.Kind, i.Object)
+	}
+}
+
+type pgCatalogColumn struct {
+	Name      string
+	Type      string
+	Collation string
+	NotNull   bool
+	Identity  bool
+}
+
+type pgCatalogIndex struct {
+	Name    string
+	Columns []string
+	Unique  bool
+	Primary bool
+	// Plain is false for expression and partial indexes, which never match a
+	// source index pgferry creates.
+	Plain bool
+}
+
+type pgCatalogForeignKey struct {
+	Name       string
+	Columns    []string
+	RefTable   string
+	RefColumns []string
+}
+
+type pgCatalogTable struct {
+	Columns     []pgCatalogColumn
+	Indexes     []pgCatalogIndex
+	ForeignKeys []pgCatalogForeignKey
+}
+
+// pgCatalogSchema is the subset of pg_catalog for one schema that the
+// conformance check compares against.
+type pgCatalogSchema struct {
+	Tables    map[string]*pgCatalogTable
+	Sequences map[string]bool
+}
+
+func (c *pgCatalogSchema) table(name string) *pgCatalogTable {
+	t, ok := c.Tables[name]
+	if !ok {
+		t = &pgCatalogTable{}
+		c.Tables[name] = t
+	}
+	return t
+}
+
+// checkSchemaConformance compares the tables, columns, keys, indexes, foreign
+// keys, and sequences pgferry would create for schema against the catalog of
+// pgSchema.
+func checkSchemaConformance(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string, src SourceDB, typeMap TypeMappingConfig) ([]SchemaIssue, error) {
+	catalog, err := loadPGCatalogSchema(ctx, pool, pgSchema)
+	if err != nil {
+		return nil, err
+	}
+	return compareSchemaConformance(schema, catalog, src, typeMap)
+}
+
+func loadPGCatalogSchema(ctx context.Context, pool *pgxpool.Pool, pgSchema string) (*pgCatalogSchema, error) {
+	catalog := &pgCatalogSchema{
+		Tables:    make(map[string]*pgCatalogTable),
+		Sequences: make(map[string]bool),
+	}
+
+	rows, err := pool.Query(ctx, `
+		SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), a.attnotnull,
+		       CASE WHEN a.attcollation <> ty.typcollation THEN COALESCE(co.collname, '') ELSE '' END,
+		       a.attidentity <> ''
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_type ty ON ty.oid = a.atttypid
+		LEFT JOIN pg_collation co ON co.oid = a.attcollation
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY c.relname, a.attnum`, pgSchema)
+	if err != nil {
+		return nil, fmt.Errorf("query columns for schema %s: %w", pgSchema, err)
+	}
+	for rows.Next() {
+		var table string
+		var col pgCatalogColumn
+		if err := rows.Scan(&table, &col.Name, &col.Type, &col.NotNull, &col.Collation, &col.Identity); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan column: %w", err)
+		}
+		t := catalog.table(table)
+		t.Columns = append(t.Columns, col)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query columns for schema %s: %w", pgSchema, err)
+	}
+
+	rows, err = pool.Query(ctx, `
+		SELECT t.relname, i.relname, ix.indisunique, ix.indisprimary,
+		       ix.indexprs IS NULL AND ix.indpred IS NULL,
+		       ARRAY(SELECT a.attname::text
+		             FROM unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		             JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		             ORDER BY k.ord)
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE n.nspname = $1
+		ORDER BY t.relname, i.relname`, pgSchema)
+	if err != nil {
+		return nil, fmt.Errorf("query indexes for schema %s: %w", pgSchema, err)
+	}
+	for rows.Next() {
+		var table string
+		var idx pgCatalogIndex
+		if err := rows.Scan(&table, &idx.Name, &idx.Unique, &idx.Primary, &idx.Plain, &idx.Columns); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan index: %w", err)
+		}
+		t := catalog.table(table)
+		t.Indexes = append(t.Indexes, idx)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query indexes for schema %s: %w", pgSchema, err)
+	}
+
+	rows, err = pool.Query(ctx, `
+		SELECT t.relname, con.conname, rt.relname,
+		       ARRAY(SELECT a.attname::text
+		             FROM unnest(con.conkey) WITH ORDINALITY AS k(attnum, ord)
+		             JOIN pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		             ORDER BY k.ord),
+		       ARRAY(SELECT a.attname::text
+		             FROM unnest(con.confkey) WITH ORDINALITY AS k(attnum, ord)
+		             JOIN pg_attribute a ON a.attrelid = con.confrelid AND a.attnum = k.attnum
+		             ORDER BY k.ord)
+		FROM pg_constraint con
+		JOIN pg_class t ON t.oid = con.conrelid
+		JOIN pg_class rt ON rt.oid = con.confrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		WHERE con.contype = 'f' AND n.nspname = $1
+		ORDER BY t.relname, con.conname`, pgSchema)
+	if err != nil {
+		return nil, fmt.Errorf("query foreign keys for schema %s: %w", pgSchema, err)
+	}
+	for rows.Next() {
+		var table string
+		var fk pgCatalogForeignKey
+		if err := rows.Scan(&table, &fk.Name, &fk.RefTable, &fk.Columns, &fk.RefColumns); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan foreign key: %w", err)
+		}
+		t := catalog.table(table)
+		t.ForeignKeys = append(t.ForeignKeys, fk)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query foreign keys for schema %s: %w", pgSchema, err)
+	}
+
+	rows, err = pool.Query(ctx, `
+		SELECT c.relname
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind = 'S' AND n.nspname = $1`, pgSchema)
+	if err != nil {
+		return nil, fmt.Errorf("query sequences for schema %s: %w", pgSchema, err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan sequence: %w", err)
+		}
+		catalog.Sequences[name] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query sequences for schema %s: %w", pgSchema, err)
+	}
+
+	return catalog, nil
+}
+
+// compareSchemaConformance reports every difference between the DDL pgferry
+// would generate for schema and catalog. Column types go through the same
+// MapType/pgTypeForCollation path as generateCreateTable; only the indexes
+// pgferry actually creates are expected.
+func compareSchemaConformance(schema *Schema, catalog *pgCatalogSchema, src SourceDB, typeMap TypeMappingConfig) ([]SchemaIssue, error) {
+	typeMap = effectiveTypeMappingForSource(typeMap, "mysql")
+
+	var issues []SchemaIssue
+	for _, t := range schema.Tables {
+		actual, ok := catalog.Tables[t.PGName]
+		if !ok || len(actual.Columns) == 0 {
+			issues = append(issues, SchemaIssue{Table: t.PGName, Kind: "missing_table"})
+			continue
+		}
+
+		colIssues, err := compareTableColumns(t, actual, src, typeMap)
+		if err != nil {
+			return nil, fmt.Errorf("table %s: %w", t.PGName, err)
+		}
+		issues = append(issues, colIssues...)
+
+		if t.PrimaryKey != nil && !hasCatalogIndex(actual, withPartitionKeyColumns(t, t.PrimaryKey.Columns), true, true) {
+			issues = append(issues, SchemaIssue{
+				Table:    t.PGName,
+				Kind:     "missing_primary_key",
+				Expected: strings.Join(withPartitionKeyColumns(t, t.PrimaryKey.Columns), ", "),
+			})
+		}
+
+		for _, idx := range t.Indexes {
+			if _, unsupported := indexUnsupportedReason(t, idx, typeMap); unsupported {
+				continue
+			}
+			columns := idx.Columns
+			if idx.Unique {
+				columns = withPartitionKeyColumns(t, columns)
+			}
+			found := hasCatalogIndex(actual, columns, idx.Unique, false)
+			if idx.Type == "FULLTEXT" || indexHasExpressionKeys(t, idx, typeMap) || idx.PGFilter != "" || len(idx.Include) > 0 {
+				// Expression and tsvector keys are not the source columns,
+				// and partial or INCLUDE indexes are never plain; match by
+				// name.
+				found = hasCatalogIndexNamed(actual, generatedIndexName(t, idx))
+			}
+			if !found {
+				issues = append(issues, SchemaIssue{
+					Table:    t.PGName,
+					Kind:     "missing_index",
+					Object:   generatedIndexName(t, idx),
+					Expected: strings.Join(idx.Columns, ", "),
+				})
+			}
+		}
+
+		for _, fk := range t.ForeignKeys {
+			if !hasCatalogForeignKey(actual, fk) {
+				issues = append(issues, SchemaIssue{
+					Table:    t.PGName,
+					Kind:     "missing_foreign_key",
+					Object:   generatedForeignKeyName(fk),
+					Expected: fmt.Sprintf("(%s) → %s(%s)", strings.Join(fk.Columns, ", "), fk.RefPGTable, strings.Join(fk.RefColumns, ", ")),
+				})
+			}
+		}
+
+		for _, col := range t.Columns {
+			if !strings.Contains(col.Extra, "auto_increment") {
+				continue
+			}
+			if typeMap.IdentityMode == "identity" {
+				if got, ok := findCatalogColumn(actual, col.PGName); ok && !got.Identity {
+					issues = append(issues, SchemaIssue{Table: t.PGName, Kind: "missing_identity", Object: col.PGName})
+				}
+				continue
+			}
+			if seq := generatedSequenceName(t, col); !catalog.Sequences[seq] {
+				issues = append(issues, SchemaIssue{Table: t.PGName, Kind: "missing_sequence", Object: seq})
+			}
+		}
+	}
+	for _, seq := range schema.Sequences {
+		if !catalog.Sequences[seq.PGName] {
+			issues = append(issues, SchemaIssue{Kind: "missing_sequence", Object: seq.PGName})
+		}
+	}
+	return issues, nil
+}
+
+func compareTableColumns(t Table, actual *pgCatalogTable, src SourceDB, typeMap TypeMappingConfig) ([]SchemaIssue, error) {
+	pkCols := make(map[string]bool)
+	if t.PrimaryKey != nil {
+		for _, c := range t.PrimaryKey.Columns {
+			pkCols[c] = true
+		}
+	}
+
+	byName := make(map[string]pgCatalogColumn, len(actual.Columns))
+	for _, c := range actual.Columns {
+		byName[c.Name] = c
+	}
+
+	var issues []SchemaIssue
+	expected := make(map[string]bool, len(t.Columns))
+	for _, col := range t.Columns {
+		expected[col.PGName] = true
+
+		pgType, err := src.MapType(col, typeMap)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.PGName, err)
+		}
+		pgType = pgTypeForCollation(col, pgType, typeMap)
+
+		got, ok := byName[col.PGName]
+		if !ok {
+			issues = append(issues, SchemaIssue{Table: t.PGName, Kind: "missing_column", Object: col.PGName, Expected: pgType})
+			continue
+		}
+
+		if canonicalPGType(pgType) != canonicalPGType(got.Type) {
+			issues = append(issues, SchemaIssue{Table: t.PGName, Kind: "type", Object: col.PGName, Expected: pgType, Actual: got.Type})
+		}
+
+		// PRIMARY KEY forces NOT NULL in PostgreSQL even when the source
+		// allowed NULLs in a key column (SQLite does).
+		// Auto-increment columns converted to identity columns are made NOT
+		// NULL; a nullable one that held NULLs keeps its sequence instead.
+		wantNotNull := !col.Nullable || pkCols[col.PGName] ||
+			(typeMap.IdentityMode == "identity" && strings.Contains(col.Extra, "auto_increment") && got.Identity)
+		if wantNotNull != got.NotNull {
+			issues = append(issues, SchemaIssue{
+				Table:    t.PGName,
+				Kind:     "nullability",
+				Object:   col.PGName,
+				Expected: nullabilityLabel(wantNotNull),
+				Actual:   nullabilityLabel(got.NotNull),
+			})
+		}
+
+		wantCollation := ""
+		if isTextLikePGType(pgType) {
+			wantCollation = collationNameFromClause(pgCollationClause(col, typeMap))
+		}
+		if wantCollation != got.Collation {
+			issues = append(issues, SchemaIssue{
+				Table:    t.PGName,
+				Kind:     "collation",
+				Object:   col.PGName,
+				Expected: collationLabel(wantCollation),
+				Actual:   collationLabel(got.Collation),
+			})
+		}
+	}
+
+	if typeMap.FullTextMode == "generated_column" {
+		for _, idx := range t.Indexes {
+			if idx.Type == "FULLTEXT" {
+				expected[fullTextColumnName(idx)] = true
+			}
+		}
+	}
+	for _, c := range actual.Columns {
+		if !expected[c.Name] {
+			issues = append(issues, SchemaIssue{Table: t.PGName, Kind: "extra_column", Object: c.Name, Actual: c.Type})
+		}
+	}
+	return issues, nil
+}
+
+func hasCatalogIndex(t *pgCatalogTable, columns []string, unique, primary bool) bool {
+	for _, idx := range t.Indexes {
+		if !idx.Plain || idx.Primary != primary || idx.Unique != unique {
+			continue
+		}
+		if equalStrings(idx.Columns, columns) {
+			return true
+		}
+	}
+	return false
+}
+
+func findCatalogColumn(t *pgCatalogTable, name string) (pgCatalogColumn, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return pgCatalogColumn{}, false
+}
+
+func hasCatalogIndexNamed(t *pgCatalogTable, name string) bool {
+	for _, idx := range t.Indexes {
+		if idx.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func hasCatalogForeignKey(t *pgCatalogTable, fk ForeignKey) bool {
+	for _, got := range t.ForeignKeys {
+		if got.RefTable == fk.RefPGTable && equalStrings(got.Columns, fk.Columns) && equalStrings(got.RefColumns, fk.RefColumns) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func nullabilityLabel(notNull bool) string {
+	if notNull {
+		return "NOT NULL"
+	}
+	return "nullable"
+}
+
+func collationLabel(name string) string {
+	if name == "" {
+		return "default"
+	}
+	return fmt.Sprintf("%q", name)
+}
+
+// collationNameFromClause extracts the collation name from a COLLATE "x"
+// clause produced by pgCollationClause.
+func collationNameFromClause(clause string) string {
+	name := strings.TrimSpace(strings.TrimPrefix(clause, "COLLATE"))
+	return strings.Trim(name, `"`)
+}
+
+var pgTypeModifierRe = regexp.MustCompile(`\([^)]*\)`)
+
+// pgTypeAliases maps format_type spellings and SQL-standard aliases onto the
+// short names used by the type mappers.
+var pgTypeAliases = map[string]string{
+	"character varying":           "varchar",
+	"character":                   "char",
+	"bpchar":                      "char",
+	"timestamp without time zone": "timestamp",
+	"timestamp with time zone":    "timestamptz",
+	"time without time zone":      "time",
+	"time with time zone":         "timetz",
+	"bit varying":                 "varbit",
+	"double precision":            "float8",
+	"real":                        "float4",
+	"integer":                     "int4",
+	"int":                         "int4",
+	"bigint":                      "int8",
+	"smallint":                    "int2",
+	"boolean":                     "bool",
+	"decimal":                     "numeric",
+}
+
+// canonicalPGType normalizes a PostgreSQL type name so that the spelling a
+// type mapper emits ("varchar(255)", "timestamptz") compares equal to the one
+// format_type reports ("character varying(255)", "timestamp with time zone").
+// Schema qualifiers and identifier quotes are dropped so native enum and
+// extension types compare by name.
+func canonicalPGType(t string) string {
+	t = strings.ToLower(strings.TrimSpace(t))
+
+	array := ""
+	for strings.HasSuffix(t, "[]") {
+		array += "[]"
+		t = strings.TrimSpace(strings.TrimSuffix(t, "[]"))
+	}
+
+	modifier := pgTypeModifierRe.FindString(t)
+	name := pgTypeModifierRe.ReplaceAllString(t, " ")
+	modifier = strings.ReplaceAll(modifier, " ", "")
+
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	name = strings.Join(strings.Fields(strings.ReplaceAll(name, `"`, "")), " ")
+	if alias, ok := pgTypeAliases[name]; ok {
+		name = alias
+	}
+	if name == "char" && modifier == "" {
+		modifier = "(1)"
+	}
+	return name + modifier + array
+}
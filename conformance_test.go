@@ -0,0 +1,245 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCanonicalPGType(t *testing.T) {
+	tests := []struct {
+		mapped, catalog string
+	}{
+		{"varchar(255)", "character varying(255)"},
+		{"char(2)", "character(2)"},
+		{"char", "character(1)"},
+		{"timestamp", "timestamp without time zone"},
+		{"timestamptz", "timestamp with time zone"},
+		{"timestamp(3)", "timestamp(3) without time zone"},
+		{"integer", "integer"},
+		{"numeric(10, 2)", "numeric(10,2)"},
+		{"varbit", "bit varying"},
+		{"text[]", "text[]"},
+		{`"app"."status_enum"`, "status_enum"},
+		{"citext", "public.citext"},
+	}
+	for _, tt := range tests {
+		if got, want := canonicalPGType(tt.mapped), canonicalPGType(tt.catalog); got != want {
+			t.Errorf("canonicalPGType(%q) = %q, canonicalPGType(%q) = %q; want equal", tt.mapped, got, tt.catalog, want)
+		}
+	}
+	if canonicalPGType("varchar(255)") == canonicalPGType("text") {
+		t.Error("varchar(255) and text should not compare equal")
+	}
+}
+
+func conformanceTestSchema() *Schema {
+	return &Schema{Tables: []Table{
+		{
+			SourceName: "users",
+			PGName:     "users",
+			Columns: []Column{
+				{SourceName: "id", PGName: "id", DataType: "int", ColumnType: "int", Extra: "auto_increment"},
+				{SourceName: "email", PGName: "email", DataType: "varchar", ColumnType: "varchar(255)", CharMaxLen: 255, Nullable: true},
+			},
+			PrimaryKey: &Index{Columns: []string{"id"}, IsPrimary: true},
+			Indexes:    []Index{{Name: "idx_email", Columns: []string{"email"}, Unique: true}},
+		},
+		{
+			SourceName: "orders",
+			PGName:     "orders",
+			Columns: []Column{
+				{SourceName: "id", PGName: "id", DataType: "int", ColumnType: "int"},
+				{SourceName: "user_id", PGName: "user_id", DataType: "int", ColumnType: "int"},
+			},
+			PrimaryKey:  &Index{Columns: []string{"id"}, IsPrimary: true},
+			ForeignKeys: []ForeignKey{{Name: "fk_orders_user", Columns: []string{"user_id"}, RefPGTable: "users", RefColumns: []string{"id"}}},
+		},
+	}}
+}
+
+func conformanceTestCatalog() *pgCatalogSchema {
+	return &pgCatalogSchema{
+		Tables: map[string]*pgCatalogTable{
+			"users": {
+				Columns: []pgCatalogColumn{
+					{Name: "id", Type: "integer", NotNull: true},
+					{Name: "email", Type: "character varying(255)"},
+				},
+				Indexes: []pgCatalogIndex{
+					{Name: "users_pkey", Columns: []string{"id"}, Unique: true, Primary: true, Plain: true},
+					{Name: "users_idx_email", Columns: []string{"email"}, Unique: true, Plain: true},
+				},
+			},
+			"orders": {
+				Columns: []pgCatalogColumn{
+					{Name: "id", Type: "integer", NotNull: true},
+					{Name: "user_id", Type: "integer", NotNull: true},
+				},
+				Indexes:     []pgCatalogIndex{{Name: "orders_pkey", Columns: []string{"id"}, Unique: true, Primary: true, Plain: true}},
+				ForeignKeys: []pgCatalogForeignKey{{Name: "fk_orders_user", Columns: []string{"user_id"}, RefTable: "users", RefColumns: []string{"id"}}},
+			},
+		},
+		Sequences: map[string]bool{"users_id_seq": true},
+	}
+}
+
+func TestCompareSchemaConformance_Match(t *testing.T) {
+	issues, err := compareSchemaConformance(conformanceTestSchema(), conformanceTestCatalog(), &mysqlSourceDB{}, defaultTypeMappingConfig())
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestCompareSchemaConformance_Drift(t *testing.T) {
+	catalog := conformanceTestCatalog()
+	users := catalog.Tables["users"]
+	users.Columns[1] = pgCatalogColumn{Name: "email", Type: "text", NotNull: true, Collation: "C"}
+	users.Columns = append(users.Columns, pgCatalogColumn{Name: "legacy", Type: "text"})
+	users.Indexes = users.Indexes[:1]
+	catalog.Tables["orders"].ForeignKeys = nil
+	catalog.Tables["orders"].Indexes = nil
+	delete(catalog.Sequences, "users_id_seq")
+
+	issues, err := compareSchemaConformance(conformanceTestSchema(), catalog, &mysqlSourceDB{}, defaultTypeMappingConfig())
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.describe())
+	}
+	for _, want := range []string{
+		"users.email: type varchar(255) expected, found text",
+		"users.email: nullable expected, found NOT NULL",
+		`users.email: collation default expected, found "C"`,
+		"users.legacy: extra column (text)",
+		"users: index users_idx_email (email) missing",
+		"users: sequence users_id_seq missing",
+		"orders: primary key (id) missing",
+		"orders: foreign key fk_orders_user (user_id) → users(id) missing",
+	} {
+		if !slices.Contains(got, want) {
+			t.Errorf("missing issue %q in:\n%s", want, strings.Join(got, "\n"))
+		}
+	}
+	if len(issues) != 8 {
+		t.Errorf("got %d issues, want 8:\n%s", len(issues), strings.Join(got, "\n"))
+	}
+}
+
+func TestCompareSchemaConformance_ExpressionIndexMatchedByName(t *testing.T) {
+	schema := conformanceTestSchema()
+	schema.Tables[0].Indexes = append(schema.Tables[0].Indexes, Index{
+		Name: "idx_email_prefix", Columns: []string{"email"}, Unique: true, HasPrefix: true, PrefixLengths: []int{32},
+	})
+	catalog := conformanceTestCatalog()
+
+	issues, err := compareSchemaConformance(schema, catalog, &mysqlSourceDB{}, defaultTypeMappingConfig())
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].describe() != "users: index users_idx_email_prefix (email) missing" {
+		t.Fatalf("issues = %+v, want missing users_idx_email_prefix", issues)
+	}
+
+	users := catalog.Tables["users"]
+	users.Indexes = append(users.Indexes, pgCatalogIndex{Name: "users_idx_email_prefix", Unique: true})
+	issues, err = compareSchemaConformance(schema, catalog, &mysqlSourceDB{}, defaultTypeMappingConfig())
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestCompareSchemaConformance_MissingTableAndColumn(t *testing.T) {
+	catalog := conformanceTestCatalog()
+	delete(catalog.Tables, "orders")
+	catalog.Tables["users"].Columns = catalog.Tables["users"].Columns[:1]
+
+	issues, err := compareSchemaConformance(conformanceTestSchema(), catalog, &mysqlSourceDB{}, defaultTypeMappingConfig())
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+	if len(issues) != 2 {
+		t.Fatalf("got %+v, want missing column and missing table", issues)
+	}
+	if issues[0].Kind != "missing_column" || issues[0].Object != "email" {
+		t.Errorf("issues[0] = %+v, want missing email column", issues[0])
+	}
+	if issues[1].Kind != "missing_table" || issues[1].Table != "orders" {
+		t.Errorf("issues[1] = %+v, want missing orders table", issues[1])
+	}
+}
+
+func TestValidationReport_SchemaIssuesFailReport(t *testing.T) {
+	report := buildValidationReport("row_count", "app", []ValidationResult{{Table: "users", SourceCount: 1, TargetCount: 1, CountMatch: true}})
+	report.addSchemaIssues([]SchemaIssue{{Table: "users", Kind: "extra_column", Object: "legacy", Actual: "text"}})
+	if report.Passed {
+		t.Fatal("schema issues should fail the report")
+	}
+}
+
+func TestCompareSchemaConformance_FullTextGeneratedColumnExpected(t *testing.T) {
+	schema := conformanceTestSchema()
+	schema.Tables[0].Indexes = append(schema.Tables[0].Indexes, Index{
+		Name: "ft_email", Columns: []string{"email"}, Type: "FULLTEXT",
+	})
+	catalog := conformanceTestCatalog()
+	users := catalog.Tables["users"]
+	users.Columns = append(users.Columns, pgCatalogColumn{Name: "ft_email_tsv", Type: "tsvector"})
+	users.Indexes = append(users.Indexes, pgCatalogIndex{Name: "users_ft_email"})
+
+	typeMap := defaultTypeMappingConfig()
+	typeMap.FullTextMode = "generated_column"
+	issues, err := compareSchemaConformance(schema, catalog, &mysqlSourceDB{}, typeMap)
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestCompareSchemaConformance_IdentityMode(t *testing.T) {
+	typeMap := defaultTypeMappingConfig()
+	typeMap.IdentityMode = "identity"
+	catalog := conformanceTestCatalog()
+	delete(catalog.Sequences, "users_id_seq")
+
+	issues, err := compareSchemaConformance(conformanceTestSchema(), catalog, &mysqlSourceDB{}, typeMap)
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].describe() != "users: column id is not an identity column" {
+		t.Fatalf("issues = %+v, want missing identity on users.id", issues)
+	}
+
+	catalog.Tables["users"].Columns[0].Identity = true
+	issues, err = compareSchemaConformance(conformanceTestSchema(), catalog, &mysqlSourceDB{}, typeMap)
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("expected no issues, got %+v", issues)
+	}
+}
+
+func TestCompareSchemaConformance_MissingStandaloneSequence(t *testing.T) {
+	schema := conformanceTestSchema()
+	schema.Sequences = []Sequence{{SourceName: "TicketSeq", PGName: "ticket_seq", Start: 1, Increment: 1}}
+
+	issues, err := compareSchemaConformance(schema, conformanceTestCatalog(), &mysqlSourceDB{}, defaultTypeMappingConfig())
+	if err != nil {
+		t.Fatalf("compareSchemaConformance() error: %v", err)
+	}
+	if len(issues) != 1 || issues[0].describe() != "sequence ticket_seq missing" {
+		t.Fatalf("issues = %+v, want missing ticket_seq", issues)
+	}
+}
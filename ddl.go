@@ -23,20 +23,51 @@ func createTables(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSch
 		if _, err := pool.Exec(ctx, ddl); err != nil {
 			return fmt.Errorf("create table %s: %w\nDDL: %s", t.PGName, err, ddl)
 		}
+		for i, stmt := range generateCreatePartitions(t, pgSchema, unlogged) {
+			if _, err := pool.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("create partition %s: %w\nDDL: %s", t.Partitioning.Partitions[i].Name, err, stmt)
+			}
+		}
+		for _, stmt := range generateCommentStatements(t, pgSchema) {
+			if _, err := pool.Exec(ctx, stmt); err != nil {
+				return fmt.Errorf("comment on %s: %w\nSQL: %s", t.PGName, err, stmt)
+			}
+		}
 	}
 	return nil
 }
 
+// generateCommentStatements produces COMMENT ON statements carrying the source
+// table and column documentation over to PostgreSQL.
+func generateCommentStatements(t Table, pgSchema string) []string {
+	var stmts []string
+	if t.Comment != "" {
+		stmts = append(stmts, fmt.Sprintf("COMMENT ON TABLE %s.%s IS %s",
+			pgIdent(pgSchema), pgIdent(t.PGName), pgLiteral(t.Comment)))
+	}
+	for _, col := range t.Columns {
+		if col.Comment == "" {
+			continue
+		}
+		stmts = append(stmts, fmt.Sprintf("COMMENT ON COLUMN %s.%s.%s IS %s",
+			pgIdent(pgSchema), pgIdent(t.PGName), pgIdent(col.PGName), pgLiteral(col.Comment)))
+	}
+	return stmts
+}
+
 // generateCreateTable produces a CREATE TABLE statement.
 func generateCreateTable(t Table, pgSchema string, unlogged bool, preserveDefaults bool, typeMap TypeMappingConfig, src SourceDB) (string, error) {
 	var b strings.Builder
 	typeMap = effectiveTypeMappingForSource(typeMap, "mysql")
 	tableKind := "TABLE"
-	if unlogged {
+	// A partitioned parent holds no storage of its own; only its partitions
+	// are UNLOGGED.
+	if unlogged && !isPartitionedTable(t) {
 		tableKind = "UNLOGGED TABLE"
 	}
 	fmt.Fprintf(&b, "CREATE %s %s.%s (\n", tableKind, pgIdent(pgSchema), pgIdent(t.PGName))
 
+	fullTextIdxs := fullTextColumnIndexes(t, typeMap)
 	for i, col := range t.Columns {
 		pgType, err := src.MapType(col, typeMap)
 		if err != nil {
@@ -55,7 +86,11 @@ func generateCreateTable(t Table, pgSchema string, unlogged bool, preserveDefaul
 			}
 		}
 
-		if preserveDefaults && col.Default != nil {
+		if col.PGGeneration != "" {
+			fmt.Fprintf(&b, " GENERATED ALWAYS AS (%s) STORED", col.PGGeneration)
+		} else if preserveDefaults && col.DefaultSequence != "" {
+			fmt.Fprintf(&b, " DEFAULT nextval(%s)", pgQualifiedRegclassLiteral(pgSchema, col.DefaultSequence))
+		} else if preserveDefaults && col.Default != nil {
 			dflt, err := src.MapDefault(col, pgType, typeMap)
 			if err != nil {
 				return "", fmt.Errorf("column %s default: %w", col.PGName, err)
@@ -87,13 +122,24 @@ func generateCreateTable(t Table, pgSchema string, unlogged bool, preserveDefaul
 			b.WriteString(" NOT NULL")
 		}
 
-		if i < len(t.Columns)-1 {
+		if i < len(t.Columns)-1 || len(fullTextIdxs) > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+	}
+	for i, idx := range fullTextIdxs {
+		fmt.Fprintf(&b, "  %s tsvector GENERATED ALWAYS AS (%s) STORED",
+			pgIdent(fullTextColumnName(idx)), fullTextVector(idx, typeMap))
+		if i < len(fullTextIdxs)-1 {
 			b.WriteByte(',')
 		}
 		b.WriteByte('\n')
 	}
 
 	b.WriteString(")")
+	if isPartitionedTable(t) {
+		fmt.Fprintf(&b, " PARTITION BY %s (%s)", t.Partitioning.Method, t.Partitioning.PGKey)
+	}
 	return b.String(), nil
 }
 
@@ -127,6 +173,46 @@ func pgEnumTypeName(values []string) string {
 	return fmt.Sprintf("pgferry_enum_%016x", h.Sum64())
 }
 
+// createSequences creates the standalone source sequences. They must exist
+// before the tables whose defaults call nextval() on them; their current value
+// is set after the data load by resetSequences.
+func createSequences(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string) error {
+	for _, seq := range schema.Sequences {
+		q := generateCreateSequence(seq, pgSchema)
+		if _, err := pool.Exec(ctx, q); err != nil {
+			return fmt.Errorf("create sequence %s: %w\nDDL: %s", seq.PGName, err, q)
+		}
+		log.Printf("  creating sequence %s.%s", pgSchema, seq.PGName)
+	}
+	return nil
+}
+
+// generateCreateSequence produces a CREATE SEQUENCE statement. SQL Server
+// tinyint sequences become smallint, and decimal/numeric ones bigint.
+func generateCreateSequence(seq Sequence, pgSchema string) string {
+	pgType := "bigint"
+	switch seq.DataType {
+	case "tinyint", "smallint":
+		pgType = "smallint"
+	case "int":
+		pgType = "integer"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE SEQUENCE IF NOT EXISTS %s AS %s INCREMENT BY %d",
+		pgQualifiedIdent(pgSchema, seq.PGName), pgType, seq.Increment)
+	if seq.MinValue != nil {
+		fmt.Fprintf(&b, " MINVALUE %d", *seq.MinValue)
+	}
+	if seq.MaxValue != nil {
+		fmt.Fprintf(&b, " MAXVALUE %d", *seq.MaxValue)
+	}
+	fmt.Fprintf(&b, " START WITH %d", seq.Start)
+	if seq.Cycle {
+		b.WriteString(" CYCLE")
+	}
+	return b.String()
+}
+
 // createEnumTypes creates PostgreSQL enum types for all enum columns in the schema.
 // Identical enum definitions (same value sets) share the same PG type.
 func createEnumTypes(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string, typeMap TypeMappingConfig) error {
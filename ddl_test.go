@@ -49,6 +49,62 @@ func TestGenerateCreateTable(t *testing.T) {
 	}
 }
 
+func TestGenerateCreateTable_GeneratedColumn(t *testing.T) {
+	dflt := "0"
+	table := Table{
+		PGName: "orders",
+		Columns: []Column{
+			{PGName: "qty", DataType: "int", ColumnType: "int"},
+			{PGName: "total", DataType: "int", ColumnType: "int", Extra: "STORED GENERATED", Default: &dflt,
+				Nullable: true, PGGeneration: `"qty" * 2`},
+		},
+	}
+
+	ddl, err := generateCreateTable(table, "app", false, true, defaultTypeMappingConfig(), mysqlSrc)
+	if err != nil {
+		t.Fatalf("generateCreateTable() error: %v", err)
+	}
+	if !strings.Contains(ddl, `"total" integer GENERATED ALWAYS AS ("qty" * 2) STORED`+"\n") {
+		t.Errorf("DDL should declare a stored generated column without default, got:\n%s", ddl)
+	}
+}
+
+func TestGenerateCreateTable_FullTextColumn(t *testing.T) {
+	table := Table{
+		PGName: "posts",
+		Columns: []Column{
+			{PGName: "id", DataType: "int", ColumnType: "int"},
+			{PGName: "body", DataType: "text", ColumnType: "text", Nullable: true},
+		},
+		Indexes: []Index{{Name: "ft_body", Type: "FULLTEXT", Columns: []string{"body"}}},
+	}
+	typeMap := defaultTypeMappingConfig()
+	typeMap.FullTextMode = "generated_column"
+
+	ddl, err := generateCreateTable(table, "app", false, true, typeMap, mysqlSrc)
+	if err != nil {
+		t.Fatalf("generateCreateTable() error: %v", err)
+	}
+	want := `  "body" text,
+  "ft_body_tsv" tsvector GENERATED ALWAYS AS (to_tsvector('simple', coalesce("body", ''))) STORED
+)`
+	if !strings.Contains(ddl, want) {
+		t.Errorf("DDL should declare the tsvector column with the table, got:\n%s", ddl)
+	}
+	if got := copyColumns(table); len(got) != 2 {
+		t.Errorf("copyColumns() = %d columns, want 2", len(got))
+	}
+
+	typeMap.FullTextMode = "expression"
+	ddl, err = generateCreateTable(table, "app", false, true, typeMap, mysqlSrc)
+	if err != nil {
+		t.Fatalf("generateCreateTable() error: %v", err)
+	}
+	if strings.Contains(ddl, "tsvector") {
+		t.Errorf("expression mode should not declare a tsvector column, got:\n%s", ddl)
+	}
+}
+
 func TestGenerateCreateTable_Unlogged(t *testing.T) {
 	table := Table{
 		PGName: "users",
@@ -187,6 +243,37 @@ func TestGenerateCreateTable_PreserveDefaults(t *testing.T) {
 	}
 }
 
+func TestGenerateCreateTable_SequenceDefault(t *testing.T) {
+	table := Table{
+		PGName: "tickets",
+		Columns: []Column{
+			{PGName: "ticket_no", DataType: "bigint", ColumnType: "bigint", Default: strPtr("NEXT VALUE FOR [dbo].[TicketSeq]"), DefaultSequence: "ticket_seq"},
+		},
+	}
+	ddl, err := generateCreateTable(table, "app", false, true, defaultTypeMappingConfig(), &mssqlSourceDB{})
+	if err != nil {
+		t.Fatalf("generateCreateTable() error: %v", err)
+	}
+	if !strings.Contains(ddl, `"ticket_no" bigint DEFAULT nextval('"app"."ticket_seq"'::regclass) NOT NULL`) {
+		t.Fatalf("expected nextval default in DDL, got:\n%s", ddl)
+	}
+}
+
+func TestGenerateCreateSequence(t *testing.T) {
+	minValue, maxValue := int64(0), int64(255)
+	got := generateCreateSequence(Sequence{PGName: "slot_seq", DataType: "tinyint", Start: 5, Increment: 5, MinValue: &minValue, MaxValue: &maxValue, Cycle: true}, "app")
+	want := `CREATE SEQUENCE IF NOT EXISTS "app"."slot_seq" AS smallint INCREMENT BY 5 MINVALUE 0 MAXVALUE 255 START WITH 5 CYCLE`
+	if got != want {
+		t.Errorf("generateCreateSequence() = %s, want %s", got, want)
+	}
+
+	got = generateCreateSequence(Sequence{PGName: "big_seq", DataType: "decimal", Start: 1, Increment: 1}, "app")
+	want = `CREATE SEQUENCE IF NOT EXISTS "app"."big_seq" AS bigint INCREMENT BY 1 START WITH 1`
+	if got != want {
+		t.Errorf("generateCreateSequence() = %s, want %s", got, want)
+	}
+}
+
 func TestGenerateCreateTable_PreserveDefaultsUnsupported(t *testing.T) {
 	table := Table{
 		PGName: "bad_defaults",
@@ -593,3 +680,27 @@ func TestGenerateCreateTable_EnumNativeDefault(t *testing.T) {
 func strPtr(s string) *string {
 	return &s
 }
+
+func TestGenerateCommentStatements(t *testing.T) {
+	table := Table{
+		PGName:  "users",
+		Comment: "Registered users",
+		Columns: []Column{
+			{PGName: "id"},
+			{PGName: "email", Comment: "Login e-mail, owner's primary address"},
+		},
+	}
+
+	got := generateCommentStatements(table, "app")
+	want := []string{
+		`COMMENT ON TABLE "app"."users" IS 'Registered users'`,
+		`COMMENT ON COLUMN "app"."users"."email" IS 'Login e-mail, owner''s primary address'`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("generateCommentStatements() = %q, want %q", got, want)
+	}
+
+	if got := generateCommentStatements(Table{PGName: "plain", Columns: []Column{{PGName: "id"}}}, "app"); len(got) != 0 {
+		t.Fatalf("generateCommentStatements() without comments = %q, want none", got)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import "sort"
+
+// ForeignKeyCycle is a set of tables whose foreign keys reference each other,
+// directly or through other tables, so no insert order satisfies them all.
+// A self-referencing table forms a cycle on its own.
+type ForeignKeyCycle struct {
+	Tables      []string // PG table names in schema order
+	ForeignKeys []string // "table.constraint" of the FKs inside the cycle
+}
+
+// findForeignKeyCycles returns the foreign key cycles of schema: the strongly
+// connected components of the table reference graph with more than one table,
+// and tables that reference themselves.
+func findForeignKeyCycles(schema *Schema) []ForeignKeyCycle {
+	order := make(map[string]int, len(schema.Tables))
+	for i, t := range schema.Tables {
+		order[t.PGName] = i
+	}
+
+	// Tarjan's algorithm over table indexes.
+	index := make([]int, len(schema.Tables))
+	low := make([]int, len(schema.Tables))
+	onStack := make([]bool, len(schema.Tables))
+	for i := range index {
+		index[i] = -1
+	}
+	var stack []int
+	var components [][]int
+	next := 0
+	var visit func(v int)
+	visit = func(v int) {
+		index[v], low[v] = next, next
+		next++
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, fk := range schema.Tables[v].ForeignKeys {
+			w, ok := order[fk.RefPGTable]
+			if !ok {
+				continue
+			}
+			if index[w] < 0 {
+				visit(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], index[w])
+			}
+		}
+		if low[v] != index[v] {
+			return
+		}
+		var comp []int
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			comp = append(comp, w)
+			if w == v {
+				break
+			}
+		}
+		components = append(components, comp)
+	}
+	for v := range schema.Tables {
+		if index[v] < 0 {
+			visit(v)
+		}
+	}
+
+	var cycles []ForeignKeyCycle
+	for _, comp := range components {
+		sort.Ints(comp)
+		members := make(map[string]bool, len(comp))
+		for _, v := range comp {
+			members[schema.Tables[v].PGName] = true
+		}
+		var cycle ForeignKeyCycle
+		for _, v := range comp {
+			t := schema.Tables[v]
+			cycle.Tables = append(cycle.Tables, t.PGName)
+			for _, fk := range t.ForeignKeys {
+				if members[fk.RefPGTable] {
+					cycle.ForeignKeys = append(cycle.ForeignKeys, t.PGName+"."+fk.Name)
+				}
+			}
+		}
+		// A single table is only a cycle when it references itself.
+		if len(cycle.ForeignKeys) > 0 {
+			cycles = append(cycles, cycle)
+		}
+	}
+	sort.Slice(cycles, func(i, j int) bool {
+		return order[cycles[i].Tables[0]] < order[cycles[j].Tables[0]]
+	})
+	return cycles
+}
+
+// deferrableForeignKeys returns the set of "table.constraint" foreign keys to
+// create DEFERRABLE INITIALLY DEFERRED under mode ("none", "cycles", "all").
+func deferrableForeignKeys(schema *Schema, mode string) map[string]bool {
+	deferred := make(map[string]bool)
+	switch mode {
+	case "all":
+		for _, t := range schema.Tables {
+			for _, fk := range t.ForeignKeys {
+				deferred[t.PGName+"."+fk.Name] = true
+			}
+		}
+	case "cycles":
+		for _, c := range findForeignKeyCycles(schema) {
+			for _, name := range c.ForeignKeys {
+				deferred[name] = true
+			}
+		}
+	}
+	return deferred
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindForeignKeyCycles(t *testing.T) {
+	schema := &Schema{
+		Tables: []Table{
+			{PGName: "users", ForeignKeys: []ForeignKey{{Name: "fk_users_org", RefPGTable: "orgs"}}},
+			{PGName: "orgs", ForeignKeys: []ForeignKey{
+				{Name: "fk_orgs_owner", RefPGTable: "users"},
+				{Name: "fk_orgs_plan", RefPGTable: "plans"},
+			}},
+			{PGName: "plans"},
+			{PGName: "employees", ForeignKeys: []ForeignKey{{Name: "fk_employees_manager", RefPGTable: "employees"}}},
+			{PGName: "orders", ForeignKeys: []ForeignKey{
+				{Name: "fk_orders_user", RefPGTable: "users"},
+				{Name: "fk_orders_missing", RefPGTable: "missing"},
+			}},
+		},
+	}
+
+	cycles := findForeignKeyCycles(schema)
+	if len(cycles) != 2 {
+		t.Fatalf("cycles = %+v, want 2", cycles)
+	}
+	if got := strings.Join(cycles[0].Tables, ","); got != "users,orgs" {
+		t.Errorf("cycle[0].Tables = %s, want users,orgs", got)
+	}
+	if got := strings.Join(cycles[0].ForeignKeys, ","); got != "users.fk_users_org,orgs.fk_orgs_owner" {
+		t.Errorf("cycle[0].ForeignKeys = %s", got)
+	}
+	if got := strings.Join(cycles[1].ForeignKeys, ","); got != "employees.fk_employees_manager" {
+		t.Errorf("cycle[1].ForeignKeys = %s", got)
+	}
+
+	if got := deferrableForeignKeys(schema, "none"); len(got) != 0 {
+		t.Errorf("deferrableForeignKeys(none) = %v, want empty", got)
+	}
+	cyc := deferrableForeignKeys(schema, "cycles")
+	if len(cyc) != 3 || cyc["orgs.fk_orgs_plan"] || !cyc["employees.fk_employees_manager"] {
+		t.Errorf("deferrableForeignKeys(cycles) = %v", cyc)
+	}
+	if got := deferrableForeignKeys(schema, "all"); len(got) != 6 {
+		t.Errorf("deferrableForeignKeys(all) = %v, want 6 entries", got)
+	}
+}
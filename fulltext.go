@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// fullTextEnabled reports whether FULLTEXT indexes are recreated as
+// PostgreSQL full-text search indexes.
+func fullTextEnabled(typeMap TypeMappingConfig) bool {
+	return typeMap.FullTextMode == "expression" || typeMap.FullTextMode == "generated_column"
+}
+
+// fullTextVector returns the to_tsvector expression over the columns of a
+// FULLTEXT index. NULL columns are treated as empty so that one NULL column
+// does not hide the others, matching MySQL MATCH ... AGAINST.
+func fullTextVector(idx Index, typeMap TypeMappingConfig) string {
+	parts := make([]string, len(idx.Columns))
+	for i, name := range idx.Columns {
+		parts[i] = fmt.Sprintf("coalesce(%s, '')", pgIdent(name))
+	}
+	return fmt.Sprintf("to_tsvector(%s, %s)", pgLiteral(fullTextConfig(typeMap)), strings.Join(parts, " || ' ' || "))
+}
+
+func fullTextConfig(typeMap TypeMappingConfig) string {
+	if typeMap.FullTextConfig == "" {
+		return "simple"
+	}
+	return typeMap.FullTextConfig
+}
+
+// fullTextColumnName is the stored tsvector column added for idx in
+// generated_column mode.
+func fullTextColumnName(idx Index) string {
+	return truncateGeneratedIdentifier(idx.Name + "_tsv")
+}
+
+// fullTextColumnIndexes returns the FULLTEXT indexes of t that get a stored
+// tsvector column. generateCreateTable declares those columns with the table,
+// so the data load fills them and no table rewrite is needed afterwards.
+func fullTextColumnIndexes(t Table, typeMap TypeMappingConfig) []Index {
+	if typeMap.FullTextMode != "generated_column" {
+		return nil
+	}
+	var idxs []Index
+	for _, idx := range t.Indexes {
+		if idx.Type != "FULLTEXT" {
+			continue
+		}
+		if _, unsupported := indexUnsupportedReason(t, idx, typeMap); unsupported {
+			continue
+		}
+		idxs = append(idxs, idx)
+	}
+	return idxs
+}
+
+// createFullTextIndex recreates a FULLTEXT index as a GIN index, either on the
+// to_tsvector expression or on the stored tsvector column declared with the
+// table.
+func createFullTextIndex(ctx context.Context, pool *pgxpool.Pool, pgSchema string, t Table, idx Index, typeMap TypeMappingConfig) error {
+	idxName := generatedIndexName(t, idx)
+	key := "(" + fullTextVector(idx, typeMap) + ")"
+	if typeMap.FullTextMode == "generated_column" {
+		key = pgIdent(fullTextColumnName(idx))
+	}
+
+	q := fmt.Sprintf("CREATE INDEX %s ON %s.%s USING GIN (%s)",
+		pgIdent(idxName), pgIdent(pgSchema), pgIdent(t.PGName), key)
+	if err := execSQL(ctx, pool, idxName, q); err != nil {
+		return err
+	}
+	log.Printf("    index %s on %s.%s USING GIN (%s)", idxName, pgSchema, t.PGName, key)
+	return nil
+}
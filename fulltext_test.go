@@ -0,0 +1,23 @@
+package main
+
+import "testing"
+
+func TestFullTextVector(t *testing.T) {
+	idx := Index{Name: "ft_post", Type: "FULLTEXT", Columns: []string{"title", "body"}}
+
+	got := fullTextVector(idx, TypeMappingConfig{FullTextMode: "expression"})
+	want := `to_tsvector('simple', coalesce("title", '') || ' ' || coalesce("body", ''))`
+	if got != want {
+		t.Errorf("fullTextVector() = %s, want %s", got, want)
+	}
+
+	got = fullTextVector(idx, TypeMappingConfig{FullTextMode: "expression", FullTextConfig: "english"})
+	want = `to_tsvector('english', coalesce("title", '') || ' ' || coalesce("body", ''))`
+	if got != want {
+		t.Errorf("fullTextVector() = %s, want %s", got, want)
+	}
+
+	if got := fullTextColumnName(idx); got != "ft_post_tsv" {
+		t.Errorf("fullTextColumnName() = %q, want ft_post_tsv", got)
+	}
+}
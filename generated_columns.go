@@ -1,8 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // isGeneratedColumn detects generated columns from the Extra field.
@@ -14,7 +18,104 @@ func isGeneratedColumn(col Column) bool {
 	return strings.EqualFold(col.Extra, "COMPUTED")
 }
 
-func collectGeneratedColumnWarnings(schema *Schema) []string {
+// translateGeneratedColumns records the PostgreSQL expression of every
+// generated column whose source expression can be translated. Those columns
+// are created as GENERATED ALWAYS AS (...) STORED and left out of COPY; the
+// rest keep being copied as plain data.
+func translateGeneratedColumns(schema *Schema, src SourceDB, typeMap TypeMappingConfig) {
+	if schema == nil {
+		return
+	}
+	for i := range schema.Tables {
+		t := &schema.Tables[i]
+		for j := range t.Columns {
+			col := &t.Columns[j]
+			if !isGeneratedColumn(*col) {
+				continue
+			}
+			if expr, err := translateGeneratedExpression(*t, *col, src, typeMap); err == nil {
+				col.PGGeneration = expr
+			}
+		}
+	}
+}
+
+// countTranslatedGeneratedColumns returns how many columns carry a
+// PostgreSQL generation expression.
+func countTranslatedGeneratedColumns(schema *Schema) int {
+	n := 0
+	for _, t := range schema.Tables {
+		for _, col := range t.Columns {
+			if col.PGGeneration != "" {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// copyColumns returns the columns that are read from the source and written
+// by COPY. Translated generated columns are computed by PostgreSQL instead, as
+// are the fulltext tsvector columns, which are not part of table.Columns.
+func copyColumns(table Table) []Column {
+	for _, col := range table.Columns {
+		if col.PGGeneration == "" {
+			continue
+		}
+		cols := make([]Column, 0, len(table.Columns)-1)
+		for _, c := range table.Columns {
+			if c.PGGeneration == "" {
+				cols = append(cols, c)
+			}
+		}
+		return cols
+	}
+	return table.Columns
+}
+
+// matchTargetGeneratedColumns clears the translated expression of every
+// generated column that is a plain column in an existing target table, as in
+// data_only mode against tables pgferry did not create, so its source values
+// are copied instead of being left NULL.
+func matchTargetGeneratedColumns(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string) error {
+	rows, err := pool.Query(ctx, `
+		SELECT c.relname, a.attname
+		FROM pg_attribute a
+		JOIN pg_class c ON c.oid = a.attrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = $1 AND c.relkind IN ('r', 'p') AND a.attnum > 0
+		  AND NOT a.attisdropped AND a.attgenerated = ''`, pgSchema)
+	if err != nil {
+		return fmt.Errorf("query target generated columns: %w", err)
+	}
+	defer rows.Close()
+
+	plain := make(map[[2]string]bool)
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return fmt.Errorf("scan target generated columns: %w", err)
+		}
+		plain[[2]string{table, column}] = true
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate target generated columns: %w", err)
+	}
+
+	for i := range schema.Tables {
+		t := &schema.Tables[i]
+		for j := range t.Columns {
+			col := &t.Columns[j]
+			if col.PGGeneration != "" && plain[[2]string{t.PGName, col.PGName}] {
+				log.Printf("  [%s] %s is not generated on the target; copying source values", t.SourceName, col.PGName)
+				col.PGGeneration = ""
+			}
+		}
+	}
+	return nil
+}
+
+func collectGeneratedColumnWarnings(schema *Schema, src SourceDB, typeMap TypeMappingConfig) []string {
 	if schema == nil {
 		return nil
 	}
@@ -22,14 +123,98 @@ func collectGeneratedColumnWarnings(schema *Schema) []string {
 	var warnings []string
 	for _, t := range schema.Tables {
 		for _, col := range t.Columns {
-			if !isGeneratedColumn(col) {
+			if !isGeneratedColumn(col) || col.PGGeneration != "" {
 				continue
 			}
+			reason := "generation expression is not recreated"
+			if _, err := translateGeneratedExpression(t, col, src, typeMap); err != nil {
+				reason = "generation expression not translated: " + err.Error()
+			}
 			warnings = append(warnings, fmt.Sprintf(
-				"generated column %s.%s (%s) will be materialized as plain data; generation expression is not recreated",
-				t.SourceName, col.SourceName, col.Extra,
+				"generated column %s.%s (%s) will be materialized as plain data; %s",
+				t.SourceName, col.SourceName, col.Extra, reason,
 			))
 		}
 	}
 	return warnings
 }
+
+// generatedColumnProjection narrows t to its primary key plus its translated
+// generated columns, or reports false when there is nothing to verify.
+func generatedColumnProjection(t Table) (Table, bool) {
+	if t.PrimaryKey == nil {
+		return Table{}, false
+	}
+	inPK := make(map[string]bool, len(t.PrimaryKey.Columns))
+	for _, c := range t.PrimaryKey.Columns {
+		inPK[c] = true
+	}
+	proj := Table{SourceName: t.SourceName, PGName: t.PGName, PrimaryKey: t.PrimaryKey}
+	generated := 0
+	for _, col := range t.Columns {
+		switch {
+		case col.PGGeneration != "":
+			generated++
+		case !inPK[col.PGName]:
+			continue
+		}
+		proj.Columns = append(proj.Columns, col)
+	}
+	return proj, generated > 0
+}
+
+// verifyGeneratedColumns compares a random sample of source values of every
+// translated generated column with what PostgreSQL computed from the copied
+// base columns, and returns one description per differing value so that a
+// translation that changes the result is reported rather than silently
+// leaving different data behind. Errors are reserved for failed queries.
+func verifyGeneratedColumns(ctx context.Context, src SourceDB, source validationSource, pool *pgxpool.Pool, schema *Schema, pgSchema string, typeMap TypeMappingConfig, sampleSize int) ([]string, error) {
+	var problems []string
+	for _, t := range schema.Tables {
+		proj, ok := generatedColumnProjection(t)
+		if !ok {
+			continue
+		}
+		pkPositions := samplePrimaryKeyColumns(proj)
+		if pkPositions == nil {
+			log.Printf("  [%s] no primary key; generated columns not verified", t.SourceName)
+			continue
+		}
+
+		families, err := checksumColumnFamilies(proj, src, typeMap)
+		if err != nil {
+			return nil, fmt.Errorf("verify generated columns of %s: %w", t.SourceName, err)
+		}
+		sample, params, err := sampleSourceRows(ctx, source, src, proj, sampleSize, 0, families, pkPositions, typeMap)
+		if err != nil {
+			return nil, fmt.Errorf("sample source rows for %s: %w", t.SourceName, err)
+		}
+		if len(sample) == 0 {
+			continue
+		}
+		pgQuery := buildTargetSampleQuery(pgSchema, proj, families, pkPositions, len(sample))
+		target, err := fetchTargetSample(ctx, pool, proj, pgQuery, params, families, pkPositions)
+		if err != nil {
+			return nil, fmt.Errorf("sample target rows for %s: %w", t.PGName, err)
+		}
+		for _, d := range compareSampleRows(proj, sample, target) {
+			problems = append(problems, d.describe(t.PGName))
+		}
+	}
+	return problems, nil
+}
+
+// verifyGeneratedColumnsAfterLoad runs verifyGeneratedColumns against the
+// single_tx snapshot when one is open, or a fresh source connection.
+func verifyGeneratedColumnsAfterLoad(ctx context.Context, src SourceDB, cfg *MigrationConfig, pool *pgxpool.Pool, schema *Schema, typeMap TypeMappingConfig, snapshot *sourceSnapshot) ([]string, error) {
+	if tx := snapshot.Tx(); tx != nil {
+		return verifyGeneratedColumns(ctx, src, tx, pool, schema, cfg.Schema, typeMap, cfg.ValidationSampleSize)
+	}
+	srcDB, err := src.OpenDB(cfg.Source.DSN)
+	if err != nil {
+		return nil, err
+	}
+	defer srcDB.Close()
+	srcDB.SetMaxOpenConns(1)
+	return verifyGeneratedColumns(ctx, src, srcDB, pool, schema, cfg.Schema, typeMap, cfg.ValidationSampleSize)
+}
@@ -1,6 +1,9 @@
 package main
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestIsGeneratedColumn(t *testing.T) {
 	tests := []struct {
@@ -59,8 +62,79 @@ func TestCollectGeneratedColumnWarnings(t *testing.T) {
 		},
 	}
 
-	warnings := collectGeneratedColumnWarnings(schema)
+	warnings := collectGeneratedColumnWarnings(schema, mysqlSrc, defaultTypeMappingConfig())
 	if len(warnings) != 2 {
 		t.Fatalf("warnings len = %d, want 2 (%v)", len(warnings), warnings)
 	}
+	if !strings.Contains(warnings[0], "generation expression unavailable") {
+		t.Fatalf("warning = %q, want translation reason", warnings[0])
+	}
+}
+
+func generatedOrdersTable() Table {
+	return Table{
+		SourceName: "orders",
+		PGName:     "orders",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id", DataType: "int", ColumnType: "int"},
+			{SourceName: "qty", PGName: "qty", DataType: "int", ColumnType: "int"},
+			{SourceName: "price", PGName: "price", DataType: "decimal", ColumnType: "decimal(10,2)", Precision: 10, Scale: 2},
+			{SourceName: "total", PGName: "total", DataType: "decimal", ColumnType: "decimal(12,2)", Precision: 12, Scale: 2,
+				Extra: "STORED GENERATED", GenerationExpression: "(`qty` * `price`)"},
+			{SourceName: "label", PGName: "label", DataType: "varchar", ColumnType: "varchar(64)", CharMaxLen: 64,
+				Extra: "VIRTUAL GENERATED", GenerationExpression: "sha2(`id`,256)"},
+		},
+		PrimaryKey: &Index{Name: "PRIMARY", Columns: []string{"id"}, IsPrimary: true},
+	}
+}
+
+func TestTranslateGeneratedColumns(t *testing.T) {
+	schema := &Schema{Tables: []Table{generatedOrdersTable()}}
+	translateGeneratedColumns(schema, mysqlSrc, defaultTypeMappingConfig())
+
+	cols := schema.Tables[0].Columns
+	if cols[3].PGGeneration != `"qty" * "price"` {
+		t.Fatalf("total generation = %q", cols[3].PGGeneration)
+	}
+	if cols[4].PGGeneration != "" {
+		t.Fatalf("label generation = %q, want untranslated", cols[4].PGGeneration)
+	}
+	if n := countTranslatedGeneratedColumns(schema); n != 1 {
+		t.Fatalf("translated = %d, want 1", n)
+	}
+
+	warnings := collectGeneratedColumnWarnings(schema, mysqlSrc, defaultTypeMappingConfig())
+	if len(warnings) != 1 || !strings.Contains(warnings[0], "orders.label") || !strings.Contains(warnings[0], "unsupported function sha2()") {
+		t.Fatalf("warnings = %v", warnings)
+	}
+}
+
+func TestCopyColumnsSkipsTranslatedGeneratedColumns(t *testing.T) {
+	schema := &Schema{Tables: []Table{generatedOrdersTable()}}
+	translateGeneratedColumns(schema, mysqlSrc, defaultTypeMappingConfig())
+	table := schema.Tables[0]
+
+	got := buildSourceSelectQuery(mysqlSrc, table, defaultTypeMappingConfig())
+	want := "SELECT `id`, `qty`, `price`, `label` FROM `orders`"
+	if got != want {
+		t.Fatalf("buildSourceSelectQuery() = %q, want %q", got, want)
+	}
+
+	key := ChunkKey{SourceColumn: "id", PGColumn: "id"}
+	chunked := buildChunkedSelectQuery(mysqlSrc, table, key, Chunk{LowerBound: 1, UpperBound: 10, IsLast: true}, defaultTypeMappingConfig())
+	if strings.Contains(chunked, "`total`") {
+		t.Fatalf("chunked query selects generated column: %s", chunked)
+	}
+
+	proj, ok := generatedColumnProjection(table)
+	if !ok {
+		t.Fatal("generatedColumnProjection() = false, want true")
+	}
+	var names []string
+	for _, c := range proj.Columns {
+		names = append(names, c.PGName)
+	}
+	if strings.Join(names, ",") != "id,total" {
+		t.Fatalf("projection columns = %v, want [id total]", names)
+	}
 }
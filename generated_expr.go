@@ -0,0 +1,1322 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// genKind is the PostgreSQL type family of a translated generated-column
+// expression. It decides which operators and functions apply and whether the
+// result can be stored in the generated column.
+type genKind int
+
+const (
+	genUnknown     genKind = iota
+	genNull                // bare NULL, fits any family
+	genLiteral             // untyped string literal
+	genText                // text, varchar, char
+	genNumeric             // exact numbers: integers and numeric
+	genFloat               // real and double precision
+	genBool                // boolean (comparison results, IS NULL, ...)
+	genJSON                // json or jsonb
+	genDate                // date
+	genTimestamp           // timestamp without time zone
+	genTimestampTZ         // timestamptz; its field extraction depends on TimeZone
+)
+
+func (k genKind) String() string {
+	switch k {
+	case genNull:
+		return "null"
+	case genLiteral, genText:
+		return "text"
+	case genNumeric, genFloat:
+		return "number"
+	case genBool:
+		return "boolean"
+	case genJSON:
+		return "json"
+	case genDate:
+		return "date"
+	case genTimestamp:
+		return "timestamp"
+	case genTimestampTZ:
+		return "timestamptz"
+	default:
+		return "unsupported type"
+	}
+}
+
+// Binding strength of the top-level operator of a translated expression,
+// following PostgreSQL's operator precedence. Operands that bind more loosely
+// than their parent are parenthesized.
+const (
+	genPrecOr = iota + 1
+	genPrecAnd
+	genPrecNot
+	genPrecIs
+	genPrecCmp
+	genPrecConcat // ||, #>, #>> and other "any operator" operators
+	genPrecAdd
+	genPrecMul
+	genPrecUnary
+	genPrecAtom
+)
+
+// genExpr is a translated subexpression.
+type genExpr struct {
+	sql  string
+	kind genKind
+	prec int
+	// jsonDoc and jsonPath are set for JSON_EXTRACT results so that a
+	// wrapping JSON_UNQUOTE can switch from #> to #>>.
+	jsonDoc  string
+	jsonPath string
+}
+
+func (e genExpr) isNumber() bool {
+	return e.kind == genNumeric || e.kind == genFloat || e.kind == genNull
+}
+
+func (e genExpr) isText() bool {
+	return e.kind == genText || e.kind == genLiteral || e.kind == genNull
+}
+
+func (e genExpr) isBool() bool {
+	return e.kind == genBool || e.kind == genNull
+}
+
+// genWrap renders e as an operand of an operator with binding strength min.
+func genWrap(e genExpr, min int) string {
+	if e.prec < min {
+		return "(" + e.sql + ")"
+	}
+	return e.sql
+}
+
+func genAtom(sql string, kind genKind) genExpr {
+	return genExpr{sql: sql, kind: kind, prec: genPrecAtom}
+}
+
+func genBinary(left genExpr, op string, right genExpr, prec int, kind genKind) genExpr {
+	return genExpr{
+		sql:  genWrap(left, prec) + " " + op + " " + genWrap(right, prec+1),
+		kind: kind,
+		prec: prec,
+	}
+}
+
+// genNumberKind is the result family of arithmetic on a and b.
+func genNumberKind(a, b genExpr) genKind {
+	if a.kind == genFloat || b.kind == genFloat {
+		return genFloat
+	}
+	return genNumeric
+}
+
+// genUnify returns the common family of the branches of COALESCE, IF and
+// CASE, or false when they cannot share a result type.
+func genUnify(a, b genKind) (genKind, bool) {
+	switch {
+	case a == b:
+		return a, true
+	case a == genNull:
+		return b, true
+	case b == genNull:
+		return a, true
+	case a == genLiteral && b == genText, a == genText && b == genLiteral:
+		return genText, true
+	case a == genNumeric && b == genFloat, a == genFloat && b == genNumeric:
+		return genFloat, true
+	default:
+		return genUnknown, false
+	}
+}
+
+// genComparable reports whether a and b can be compared with =, <, ... in
+// PostgreSQL with the same meaning they have in the source.
+func genComparable(a, b genExpr) bool {
+	if a.kind == genNull || b.kind == genNull {
+		return true
+	}
+	if a.isNumber() && b.isNumber() {
+		return true
+	}
+	if a.isText() && b.isText() {
+		return true
+	}
+	switch a.kind {
+	case genDate, genTimestamp, genTimestampTZ:
+		return b.kind == a.kind || b.kind == genLiteral
+	}
+	switch b.kind {
+	case genDate, genTimestamp, genTimestampTZ:
+		return a.kind == genLiteral
+	}
+	return a.kind == genBool && b.kind == genBool
+}
+
+// genKindForPGType classifies a mapped PostgreSQL column type.
+func genKindForPGType(pgType string) genKind {
+	base := strings.ToLower(strings.TrimSpace(pgType))
+	if i := strings.IndexByte(base, '('); i >= 0 {
+		base = strings.TrimSpace(base[:i])
+	}
+	switch base {
+	case "smallint", "integer", "bigint", "numeric", "decimal":
+		return genNumeric
+	case "real", "double precision":
+		return genFloat
+	case "text", "varchar", "character varying", "char", "character", "citext":
+		return genText
+	case "boolean":
+		return genBool
+	case "json", "jsonb":
+		return genJSON
+	case "date":
+		return genDate
+	case "timestamp", "timestamp without time zone":
+		return genTimestamp
+	case "timestamptz", "timestamp with time zone":
+		return genTimestampTZ
+	default:
+		return genUnknown
+	}
+}
+
+// translateGeneratedExpression rewrites the source expression of a generated
+// column into a PostgreSQL GENERATED ALWAYS AS expression. It understands
+// arithmetic, comparisons, CONCAT and MSSQL string +, COALESCE/IFNULL/ISNULL,
+// IF/IIF/CASE, common string and numeric functions, JSON path extraction and
+// date part functions. Anything else, or a result that does not fit the
+// column's mapped type, returns an error explaining why the column stays a
+// plain data column.
+func translateGeneratedExpression(t Table, col Column, src SourceDB, typeMap TypeMappingConfig) (string, error) {
+	dialect := checkDialect(src)
+	if dialect != "mysql" && dialect != "mssql" {
+		return "", fmt.Errorf("%s generation expressions are not translated", src.Name())
+	}
+	expr := strings.TrimSpace(col.GenerationExpression)
+	if expr == "" {
+		return "", fmt.Errorf("generation expression unavailable")
+	}
+
+	pgType, err := src.MapType(col, typeMap)
+	if err != nil {
+		return "", err
+	}
+	target := genKindForPGType(pgType)
+	if target == genUnknown {
+		return "", fmt.Errorf("column type %s is not supported for generated columns", pgType)
+	}
+
+	p, err := newGenParser(t, expr, src, typeMap)
+	if err != nil {
+		return "", err
+	}
+	result, err := p.parseAll()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case result.kind == genNull:
+	case target == genText && (result.isText() || result.kind == genNumeric):
+	case (target == genNumeric || target == genFloat) && result.isNumber():
+	case target == genJSON && result.kind == genJSON:
+		// json and jsonb documents produce their own flavour; pin it.
+		return fmt.Sprintf("CAST(%s AS %s)", result.sql, pgType), nil
+	case result.kind == target:
+	default:
+		return "", fmt.Errorf("expression yields %s, column maps to %s", result.kind, pgType)
+	}
+	return result.sql, nil
+}
+
+// genParser is a recursive-descent parser over check-expression tokens that
+// emits PostgreSQL as it goes.
+type genParser struct {
+	tokens  []checkToken
+	pos     int
+	table   Table
+	src     SourceDB
+	typeMap TypeMappingConfig
+	dialect string
+	// index allows references to generated columns, which expression
+	// indexes may read but generated columns may not.
+	index bool
+}
+
+// newGenParser tokenizes a MySQL or MSSQL expression over the columns of t.
+func newGenParser(t Table, expr string, src SourceDB, typeMap TypeMappingConfig) (*genParser, error) {
+	dialect := checkDialect(src)
+	// Some MySQL 8.0 releases report GENERATION_EXPRESSION and index
+	// EXPRESSION with every quote escaped (_utf8mb4\'x\'); undo that when no
+	// bare quote remains.
+	if dialect == "mysql" && strings.Contains(expr, `\'`) && !strings.Contains(strings.ReplaceAll(expr, `\'`, ""), "'") {
+		expr = strings.ReplaceAll(expr, `\'`, "'")
+	}
+	tokens, err := tokenizeCheckExpression(expr, dialect)
+	if err != nil {
+		return nil, err
+	}
+	return &genParser{tokens: tokens, table: t, src: src, typeMap: typeMap, dialect: dialect}, nil
+}
+
+// parseAll parses the whole token stream as a single expression.
+func (p *genParser) parseAll() (genExpr, error) {
+	result, err := p.parseExpr()
+	if err != nil {
+		return genExpr{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return genExpr{}, fmt.Errorf("unexpected %q", p.tokens[p.pos].text)
+	}
+	return result, nil
+}
+
+func (p *genParser) peek() *checkToken {
+	if p.pos < len(p.tokens) {
+		return &p.tokens[p.pos]
+	}
+	return nil
+}
+
+func (p *genParser) peekWord(word string) bool {
+	tok := p.peek()
+	return tok != nil && tok.kind == checkTokenWord && strings.EqualFold(tok.text, word)
+}
+
+func (p *genParser) peekOp(ops ...string) string {
+	tok := p.peek()
+	if tok == nil || tok.kind != checkTokenOp {
+		return ""
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			return op
+		}
+	}
+	return ""
+}
+
+func (p *genParser) expect(kind checkTokenKind, what string) error {
+	tok := p.peek()
+	if tok == nil {
+		return fmt.Errorf("expected %s, found end of expression", what)
+	}
+	if tok.kind != kind {
+		return fmt.Errorf("expected %s, found %q", what, tok.text)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *genParser) parseExpr() (genExpr, error) {
+	return p.parseOr()
+}
+
+func (p *genParser) parseOr() (genExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return genExpr{}, err
+	}
+	for p.peekWord("OR") {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return genExpr{}, err
+		}
+		if !left.isBool() || !right.isBool() {
+			return genExpr{}, fmt.Errorf("OR needs boolean operands")
+		}
+		left = genBinary(left, "OR", right, genPrecOr, genBool)
+	}
+	return left, nil
+}
+
+func (p *genParser) parseAnd() (genExpr, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return genExpr{}, err
+	}
+	for p.peekWord("AND") {
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return genExpr{}, err
+		}
+		if !left.isBool() || !right.isBool() {
+			return genExpr{}, fmt.Errorf("AND needs boolean operands")
+		}
+		left = genBinary(left, "AND", right, genPrecAnd, genBool)
+	}
+	return left, nil
+}
+
+func (p *genParser) parseNot() (genExpr, error) {
+	if !p.peekWord("NOT") {
+		return p.parseComparison()
+	}
+	p.pos++
+	operand, err := p.parseNot()
+	if err != nil {
+		return genExpr{}, err
+	}
+	if !operand.isBool() {
+		return genExpr{}, fmt.Errorf("NOT needs a boolean operand")
+	}
+	return genExpr{sql: "NOT " + genWrap(operand, genPrecNot), kind: genBool, prec: genPrecNot}, nil
+}
+
+func (p *genParser) parseComparison() (genExpr, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return genExpr{}, err
+	}
+	if p.peekWord("IS") {
+		p.pos++
+		test := "IS NULL"
+		if p.peekWord("NOT") {
+			p.pos++
+			test = "IS NOT NULL"
+		}
+		if !p.peekWord("NULL") {
+			return genExpr{}, fmt.Errorf("only IS [NOT] NULL is supported")
+		}
+		p.pos++
+		return genExpr{sql: genWrap(left, genPrecIs+1) + " " + test, kind: genBool, prec: genPrecIs}, nil
+	}
+	tok := p.peek()
+	if tok == nil || tok.kind != checkTokenOp {
+		return left, nil
+	}
+	op, ok := checkComparisonOps[tok.text]
+	if !ok {
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parseAdditive()
+	if err != nil {
+		return genExpr{}, err
+	}
+	if !genComparable(left, right) {
+		return genExpr{}, fmt.Errorf("cannot compare %s with %s", left.kind, right.kind)
+	}
+	return genExpr{
+		sql:  genWrap(left, genPrecCmp+1) + " " + op + " " + genWrap(right, genPrecCmp+1),
+		kind: genBool,
+		prec: genPrecCmp,
+	}, nil
+}
+
+func (p *genParser) parseAdditive() (genExpr, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return genExpr{}, err
+	}
+	for {
+		op := p.peekOp("+", "-")
+		if op == "" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return genExpr{}, err
+		}
+		// MSSQL overloads + for string concatenation.
+		if op == "+" && p.dialect == "mssql" && (left.kind == genText || left.kind == genLiteral || right.kind == genText || right.kind == genLiteral) {
+			if !left.isText() || !right.isText() {
+				return genExpr{}, fmt.Errorf("+ mixes %s and %s", left.kind, right.kind)
+			}
+			left = genBinary(left, "||", right, genPrecConcat, genText)
+			continue
+		}
+		if !left.isNumber() || !right.isNumber() {
+			return genExpr{}, fmt.Errorf("%s needs numeric operands, got %s and %s", op, left.kind, right.kind)
+		}
+		left = genBinary(left, op, right, genPrecAdd, genNumberKind(left, right))
+	}
+}
+
+func (p *genParser) parseMultiplicative() (genExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return genExpr{}, err
+	}
+	for {
+		op := p.peekOp("*", "/", "%")
+		if op == "" {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return genExpr{}, err
+		}
+		if !left.isNumber() || !right.isNumber() {
+			return genExpr{}, fmt.Errorf("%s needs numeric operands, got %s and %s", op, left.kind, right.kind)
+		}
+		kind := genNumberKind(left, right)
+		switch {
+		case op == "%" && kind == genFloat:
+			return genExpr{}, fmt.Errorf("%% on floating-point operands has no PostgreSQL equivalent")
+		case op == "/" && p.dialect == "mysql" && kind != genFloat:
+			// MySQL / always divides exactly, even for integers.
+			left = genAtom("CAST("+left.sql+" AS numeric)", genNumeric)
+		}
+		left = genBinary(left, op, right, genPrecMul, kind)
+	}
+}
+
+func (p *genParser) parseUnary() (genExpr, error) {
+	if p.peekOp("-") == "" {
+		return p.parsePostfix()
+	}
+	p.pos++
+	operand, err := p.parseUnary()
+	if err != nil {
+		return genExpr{}, err
+	}
+	if !operand.isNumber() {
+		return genExpr{}, fmt.Errorf("unary - needs a numeric operand")
+	}
+	return genExpr{sql: "-" + genWrap(operand, genPrecUnary), kind: operand.kind, prec: genPrecUnary}, nil
+}
+
+func (p *genParser) parsePostfix() (genExpr, error) {
+	e, err := p.parsePrimary()
+	if err != nil {
+		return genExpr{}, err
+	}
+	for p.dialect == "mysql" {
+		op := p.peekOp("->", "->>")
+		if op == "" {
+			break
+		}
+		p.pos++
+		tok := p.peek()
+		if tok == nil || tok.kind != checkTokenString {
+			return genExpr{}, fmt.Errorf("%s needs a JSON path literal", op)
+		}
+		p.pos++
+		e, err = genJSONExtract(e, tok.text)
+		if err != nil {
+			return genExpr{}, err
+		}
+		if op == "->>" {
+			e = genJSONUnquote(e)
+		}
+	}
+	return e, nil
+}
+
+func (p *genParser) parsePrimary() (genExpr, error) {
+	tok := p.peek()
+	if tok == nil {
+		return genExpr{}, fmt.Errorf("unexpected end of expression")
+	}
+	switch tok.kind {
+	case checkTokenNumber:
+		p.pos++
+		return genAtom(tok.text, genNumeric), nil
+	case checkTokenString:
+		p.pos++
+		return genAtom(pgLiteral(tok.text), genLiteral), nil
+	case checkTokenLParen:
+		p.pos++
+		inner, err := p.parseExpr()
+		if err != nil {
+			return genExpr{}, err
+		}
+		if err := p.expect(checkTokenRParen, ")"); err != nil {
+			return genExpr{}, err
+		}
+		return inner, nil
+	case checkTokenIdent:
+		p.pos++
+		return p.columnRef(tok.text)
+	case checkTokenWord:
+		p.pos++
+		upper := strings.ToUpper(tok.text)
+		if upper == "NULL" {
+			return genAtom("NULL", genNull), nil
+		}
+		if upper == "CASE" {
+			return p.parseCase()
+		}
+		if next := p.peek(); next != nil && next.kind == checkTokenLParen {
+			p.pos++
+			return p.parseFunction(upper, strings.ToLower(tok.text))
+		}
+		if checkKeywords[upper] {
+			return genExpr{}, fmt.Errorf("unexpected keyword %s", upper)
+		}
+		return p.columnRef(tok.text)
+	default:
+		return genExpr{}, fmt.Errorf("unexpected %q", tok.text)
+	}
+}
+
+// columnRef resolves a column reference. PostgreSQL forbids generated
+// columns from reading other generated columns, so those are rejected unless
+// the expression is an index key.
+func (p *genParser) columnRef(name string) (genExpr, error) {
+	for _, col := range p.table.Columns {
+		if !strings.EqualFold(col.SourceName, name) {
+			continue
+		}
+		if isGeneratedColumn(col) && !p.index {
+			return genExpr{}, fmt.Errorf("references generated column %s", col.SourceName)
+		}
+		pgType, err := p.src.MapType(col, p.typeMap)
+		if err != nil {
+			return genExpr{}, fmt.Errorf("column %s: %w", col.SourceName, err)
+		}
+		kind := genKindForPGType(pgType)
+		if kind == genUnknown {
+			return genExpr{}, fmt.Errorf("column %s maps to unsupported type %s", col.SourceName, pgType)
+		}
+		return genAtom(pgIdent(col.PGName), kind), nil
+	}
+	return genExpr{}, fmt.Errorf("unknown column or unsupported keyword %q", name)
+}
+
+// parseCase handles both searched (CASE WHEN cond ...) and simple
+// (CASE x WHEN v ...) forms; the CASE keyword is already consumed.
+func (p *genParser) parseCase() (genExpr, error) {
+	var operand *genExpr
+	if !p.peekWord("WHEN") {
+		e, err := p.parseExpr()
+		if err != nil {
+			return genExpr{}, err
+		}
+		operand = &e
+	}
+
+	var b strings.Builder
+	b.WriteString("CASE")
+	if operand != nil {
+		b.WriteString(" " + operand.sql)
+	}
+	kind := genNull
+	branches := 0
+	for p.peekWord("WHEN") {
+		p.pos++
+		cond, err := p.parseExpr()
+		if err != nil {
+			return genExpr{}, err
+		}
+		if operand != nil && !genComparable(*operand, cond) {
+			return genExpr{}, fmt.Errorf("cannot compare %s with %s", operand.kind, cond.kind)
+		}
+		if operand == nil && !cond.isBool() {
+			return genExpr{}, fmt.Errorf("CASE WHEN needs a boolean condition")
+		}
+		if !p.peekWord("THEN") {
+			return genExpr{}, fmt.Errorf("expected THEN")
+		}
+		p.pos++
+		result, err := p.parseExpr()
+		if err != nil {
+			return genExpr{}, err
+		}
+		var ok bool
+		if kind, ok = genUnify(kind, result.kind); !ok {
+			return genExpr{}, fmt.Errorf("CASE branches mix incompatible types")
+		}
+		fmt.Fprintf(&b, " WHEN %s THEN %s", cond.sql, result.sql)
+		branches++
+	}
+	if branches == 0 {
+		return genExpr{}, fmt.Errorf("CASE without WHEN")
+	}
+	if p.peekWord("ELSE") {
+		p.pos++
+		result, err := p.parseExpr()
+		if err != nil {
+			return genExpr{}, err
+		}
+		var ok bool
+		if kind, ok = genUnify(kind, result.kind); !ok {
+			return genExpr{}, fmt.Errorf("CASE branches mix incompatible types")
+		}
+		fmt.Fprintf(&b, " ELSE %s", result.sql)
+	}
+	if !p.peekWord("END") {
+		return genExpr{}, fmt.Errorf("expected END")
+	}
+	p.pos++
+	b.WriteString(" END")
+	return genAtom(b.String(), kind), nil
+}
+
+// parseArgs reads a comma-separated argument list up to and including the
+// closing parenthesis.
+func (p *genParser) parseArgs() ([]genExpr, error) {
+	var args []genExpr
+	if tok := p.peek(); tok != nil && tok.kind == checkTokenRParen {
+		p.pos++
+		return args, nil
+	}
+	for {
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+		tok := p.peek()
+		if tok != nil && tok.kind == checkTokenComma {
+			p.pos++
+			continue
+		}
+		if err := p.expect(checkTokenRParen, ")"); err != nil {
+			return nil, err
+		}
+		return args, nil
+	}
+}
+
+// genDateParts maps MySQL function names and MSSQL DATEPART units to
+// EXTRACT fields. HOUR and MINUTE need a timestamp operand.
+var genDateParts = map[string]string{
+	"YEAR": "YEAR", "YY": "YEAR", "YYYY": "YEAR",
+	"QUARTER": "QUARTER", "QQ": "QUARTER", "Q": "QUARTER",
+	"MONTH": "MONTH", "MM": "MONTH", "M": "MONTH",
+	"DAY": "DAY", "DD": "DAY", "D": "DAY", "DAYOFMONTH": "DAY",
+	"DAYOFYEAR": "DOY", "DY": "DOY", "Y": "DOY",
+	"HOUR": "HOUR", "HH": "HOUR",
+	"MINUTE": "MINUTE", "MI": "MINUTE", "N": "MINUTE",
+}
+
+// genMySQLDateFunctions are the MySQL single-argument date part functions.
+var genMySQLDateFunctions = map[string]bool{
+	"YEAR": true, "QUARTER": true, "MONTH": true, "DAY": true,
+	"DAYOFMONTH": true, "DAYOFYEAR": true, "HOUR": true, "MINUTE": true,
+}
+
+func (p *genParser) parseFunction(name, display string) (genExpr, error) {
+	// MSSQL date functions take a bare datepart keyword first.
+	if p.dialect == "mssql" && (name == "DATEPART" || name == "DATEDIFF") {
+		tok := p.peek()
+		if tok == nil || tok.kind != checkTokenWord {
+			return genExpr{}, fmt.Errorf("%s() needs a datepart", display)
+		}
+		part := strings.ToUpper(tok.text)
+		p.pos++
+		if err := p.expect(checkTokenComma, ","); err != nil {
+			return genExpr{}, err
+		}
+		args, err := p.parseArgs()
+		if err != nil {
+			return genExpr{}, err
+		}
+		if name == "DATEPART" {
+			if len(args) != 1 {
+				return genExpr{}, fmt.Errorf("datepart() takes 2 arguments")
+			}
+			field, ok := genDateParts[part]
+			if !ok {
+				return genExpr{}, fmt.Errorf("unsupported datepart %s", strings.ToLower(part))
+			}
+			return genExtract(field, args[0])
+		}
+		if len(args) != 2 {
+			return genExpr{}, fmt.Errorf("datediff() takes 3 arguments")
+		}
+		return genMSSQLDateDiff(part, args[0], args[1])
+	}
+
+	if name == "CAST" {
+		return p.parseCast()
+	}
+
+	args, err := p.parseArgs()
+	if err != nil {
+		return genExpr{}, fmt.Errorf("%s(): %w", display, err)
+	}
+	arity := func(min, max int) error {
+		if len(args) < min || len(args) > max {
+			return fmt.Errorf("%s() called with %d arguments", display, len(args))
+		}
+		return nil
+	}
+	mysql := p.dialect == "mysql"
+	mssql := p.dialect == "mssql"
+
+	switch {
+	case name == "CONCAT":
+		if err := arity(1, 255); err != nil {
+			return genExpr{}, err
+		}
+		return p.concat(args)
+
+	case name == "COALESCE", name == "IFNULL" && mysql, name == "ISNULL" && mssql:
+		if err := arity(2, 255); err != nil {
+			return genExpr{}, err
+		}
+		if name != "COALESCE" && len(args) != 2 {
+			return genExpr{}, fmt.Errorf("%s() takes 2 arguments", display)
+		}
+		return genCall("COALESCE", args)
+
+	case name == "NULLIF":
+		if err := arity(2, 2); err != nil {
+			return genExpr{}, err
+		}
+		if !genComparable(args[0], args[1]) {
+			return genExpr{}, fmt.Errorf("cannot compare %s with %s", args[0].kind, args[1].kind)
+		}
+		return genAtom(fmt.Sprintf("NULLIF(%s, %s)", args[0].sql, args[1].sql), args[0].kind), nil
+
+	case name == "IF" && mysql, name == "IIF" && mssql:
+		if err := arity(3, 3); err != nil {
+			return genExpr{}, err
+		}
+		if !args[0].isBool() {
+			return genExpr{}, fmt.Errorf("%s() needs a boolean condition", display)
+		}
+		kind, ok := genUnify(args[1].kind, args[2].kind)
+		if !ok {
+			return genExpr{}, fmt.Errorf("%s() branches mix %s and %s", display, args[1].kind, args[2].kind)
+		}
+		return genAtom(fmt.Sprintf("CASE WHEN %s THEN %s ELSE %s END", args[0].sql, args[1].sql, args[2].sql), kind), nil
+
+	case name == "UPPER", name == "UCASE" && mysql, name == "LOWER", name == "LCASE" && mysql,
+		name == "TRIM", name == "LTRIM", name == "RTRIM":
+		if err := arity(1, 1); err != nil {
+			return genExpr{}, err
+		}
+		if !args[0].isText() {
+			return genExpr{}, fmt.Errorf("%s() needs a text argument", display)
+		}
+		fn := map[string]string{
+			"UPPER": "upper", "UCASE": "upper", "LOWER": "lower", "LCASE": "lower",
+			"TRIM": "btrim", "LTRIM": "ltrim", "RTRIM": "rtrim",
+		}[name]
+		return genAtom(fmt.Sprintf("%s(%s)", fn, args[0].sql), genText), nil
+
+	case name == "LEFT", name == "RIGHT":
+		if err := arity(2, 2); err != nil {
+			return genExpr{}, err
+		}
+		if !args[0].isText() || !args[1].isNumber() {
+			return genExpr{}, fmt.Errorf("%s() needs text and a length", display)
+		}
+		return genAtom(fmt.Sprintf("%s(%s, %s)", strings.ToLower(name), args[0].sql, args[1].sql), genText), nil
+
+	case name == "SUBSTRING", name == "SUBSTR" && mysql:
+		if err := arity(2, 3); err != nil {
+			return genExpr{}, err
+		}
+		if !args[0].isText() {
+			return genExpr{}, fmt.Errorf("%s() needs a text argument", display)
+		}
+		// A negative start counts from the end in MySQL; only accept
+		// literal positions so the meaning is known.
+		if args[1].kind != genNumeric || args[1].prec != genPrecAtom || strings.HasPrefix(args[1].sql, "-") {
+			return genExpr{}, fmt.Errorf("%s() start position must be a positive literal", display)
+		}
+		parts := []string{args[0].sql, args[1].sql}
+		if len(args) == 3 {
+			if !args[2].isNumber() {
+				return genExpr{}, fmt.Errorf("%s() length must be numeric", display)
+			}
+			parts = append(parts, args[2].sql)
+		}
+		return genAtom("substr("+strings.Join(parts, ", ")+")", genText), nil
+
+	case name == "REPLACE" && mysql:
+		// MSSQL REPLACE follows the collation, which is usually
+		// case-insensitive; PostgreSQL's is always case-sensitive.
+		if err := arity(3, 3); err != nil {
+			return genExpr{}, err
+		}
+		for _, a := range args {
+			if !a.isText() {
+				return genExpr{}, fmt.Errorf("replace() needs text arguments")
+			}
+		}
+		return genAtom(fmt.Sprintf("replace(%s, %s, %s)", args[0].sql, args[1].sql, args[2].sql), genText), nil
+
+	case name == "CHAR_LENGTH" && mysql, name == "CHARACTER_LENGTH" && mysql, name == "LENGTH" && mysql, name == "LEN" && mssql:
+		if err := arity(1, 1); err != nil {
+			return genExpr{}, err
+		}
+		if !args[0].isText() {
+			return genExpr{}, fmt.Errorf("%s() needs a text argument", display)
+		}
+		fn, closer, _ := checkFunction(name, p.dialect)
+		return genAtom(fn+args[0].sql+closer, genNumeric), nil
+
+	case name == "ABS", name == "FLOOR", name == "CEIL", name == "CEILING":
+		if err := arity(1, 1); err != nil {
+			return genExpr{}, err
+		}
+		if !args[0].isNumber() {
+			return genExpr{}, fmt.Errorf("%s() needs a numeric argument", display)
+		}
+		fn := strings.ToLower(name)
+		if fn == "ceiling" {
+			fn = "ceil"
+		}
+		return genAtom(fmt.Sprintf("%s(%s)", fn, args[0].sql), args[0].kind), nil
+
+	case name == "ROUND":
+		if err := arity(1, 2); err != nil {
+			return genExpr{}, err
+		}
+		for _, a := range args {
+			if !a.isNumber() {
+				return genExpr{}, fmt.Errorf("round() needs numeric arguments")
+			}
+		}
+		if args[0].kind == genFloat {
+			return genExpr{}, fmt.Errorf("round() of floating-point values rounds differently in PostgreSQL")
+		}
+		if len(args) == 1 {
+			return genAtom(fmt.Sprintf("round(%s)", args[0].sql), genNumeric), nil
+		}
+		return genAtom(fmt.Sprintf("round(CAST(%s AS numeric), %s)", args[0].sql, args[1].sql), genNumeric), nil
+
+	case name == "MOD" && mysql:
+		if err := arity(2, 2); err != nil {
+			return genExpr{}, err
+		}
+		if !args[0].isNumber() || !args[1].isNumber() || genNumberKind(args[0], args[1]) == genFloat {
+			return genExpr{}, fmt.Errorf("mod() needs exact numeric arguments")
+		}
+		return genAtom(fmt.Sprintf("mod(%s, %s)", args[0].sql, args[1].sql), genNumeric), nil
+
+	case name == "JSON_EXTRACT" && mysql:
+		if err := arity(2, 2); err != nil {
+			return genExpr{}, err
+		}
+		path, err := genPathLiteral(args[1])
+		if err != nil {
+			return genExpr{}, err
+		}
+		return genJSONExtract(args[0], path)
+
+	case name == "JSON_UNQUOTE" && mysql:
+		if err := arity(1, 1); err != nil {
+			return genExpr{}, err
+		}
+		if args[0].jsonDoc == "" {
+			return genExpr{}, fmt.Errorf("json_unquote() is only supported around json_extract()")
+		}
+		return genJSONUnquote(args[0]), nil
+
+	case name == "JSON_VALUE" && mssql:
+		if err := arity(2, 2); err != nil {
+			return genExpr{}, err
+		}
+		path, err := genPathLiteral(args[1])
+		if err != nil {
+			return genExpr{}, err
+		}
+		doc := args[0]
+		switch {
+		case doc.kind == genText:
+			// MSSQL stores JSON in nvarchar columns.
+			doc = genAtom("CAST("+doc.sql+" AS jsonb)", genJSON)
+		case doc.kind != genJSON:
+			return genExpr{}, fmt.Errorf("json_value() needs a JSON document")
+		}
+		e, err := genJSONExtract(doc, path)
+		if err != nil {
+			return genExpr{}, err
+		}
+		return genJSONUnquote(e), nil
+
+	case name == "DATE" && mysql:
+		if err := arity(1, 1); err != nil {
+			return genExpr{}, err
+		}
+		switch args[0].kind {
+		case genDate:
+			return args[0], nil
+		case genTimestamp:
+			return genAtom("CAST("+args[0].sql+" AS date)", genDate), nil
+		default:
+			return genExpr{}, fmt.Errorf("date() needs a date or datetime argument, got %s", args[0].kind)
+		}
+
+	case name == "DATEDIFF" && mysql:
+		if err := arity(2, 2); err != nil {
+			return genExpr{}, err
+		}
+		return genDayDiff(args[0], args[1])
+
+	case mysql && genMySQLDateFunctions[name], mssql && (name == "YEAR" || name == "MONTH" || name == "DAY"):
+		if err := arity(1, 1); err != nil {
+			return genExpr{}, err
+		}
+		return genExtract(genDateParts[name], args[0])
+	}
+	return genExpr{}, fmt.Errorf("unsupported function %s()", display)
+}
+
+// parseCast handles CAST(expr AS type); the opening parenthesis is already
+// consumed. Only conversions PostgreSQL treats as immutable are accepted:
+// text and dates do not convert into each other because the result depends
+// on DateStyle.
+func (p *genParser) parseCast() (genExpr, error) {
+	arg, err := p.parseExpr()
+	if err != nil {
+		return genExpr{}, fmt.Errorf("cast(): %w", err)
+	}
+	if !p.peekWord("AS") {
+		return genExpr{}, fmt.Errorf("cast() needs AS type")
+	}
+	p.pos++
+
+	var words []string
+	for {
+		tok := p.peek()
+		if tok == nil || tok.kind != checkTokenWord {
+			break
+		}
+		words = append(words, strings.ToUpper(tok.text))
+		p.pos++
+	}
+	if len(words) == 0 {
+		return genExpr{}, fmt.Errorf("cast() needs a target type")
+	}
+	var params []string
+	if tok := p.peek(); tok != nil && tok.kind == checkTokenLParen {
+		p.pos++
+		for {
+			tok := p.peek()
+			if tok == nil || (tok.kind != checkTokenNumber && tok.kind != checkTokenWord) {
+				return genExpr{}, fmt.Errorf("cast() type has invalid parameters")
+			}
+			params = append(params, strings.ToUpper(tok.text))
+			p.pos++
+			if next := p.peek(); next != nil && next.kind == checkTokenComma {
+				p.pos++
+				continue
+			}
+			if err := p.expect(checkTokenRParen, ")"); err != nil {
+				return genExpr{}, err
+			}
+			break
+		}
+	}
+	// MySQL: CAST(x AS CHAR(n) CHARSET utf8mb4). The character set has no
+	// PostgreSQL counterpart; a collation would change comparisons.
+	if p.peekWord("CHARSET") || p.peekWord("CHARACTER") {
+		p.pos++
+		if p.peekWord("SET") {
+			p.pos++
+		}
+		if tok := p.peek(); tok == nil || tok.kind != checkTokenWord {
+			return genExpr{}, fmt.Errorf("cast() needs a character set name")
+		}
+		p.pos++
+	}
+	if p.peekWord("COLLATE") {
+		return genExpr{}, fmt.Errorf("cast() with COLLATE is not supported")
+	}
+	if err := p.expect(checkTokenRParen, ")"); err != nil {
+		return genExpr{}, err
+	}
+
+	pgType, kind, err := genCastType(strings.Join(words, " "), params, p.dialect)
+	if err != nil {
+		return genExpr{}, err
+	}
+	switch {
+	case arg.kind == genNull:
+	case kind == genText && (arg.isText() || arg.isNumber() || arg.kind == genJSON):
+	case (kind == genNumeric || kind == genFloat) && (arg.isNumber() || arg.isText()):
+	case kind == genDate && (arg.kind == genDate || arg.kind == genTimestamp):
+	case kind == genTimestamp && (arg.kind == genDate || arg.kind == genTimestamp):
+	case kind == genJSON && (arg.kind == genJSON || arg.isText()):
+	default:
+		return genExpr{}, fmt.Errorf("cast() from %s to %s is not supported", arg.kind, pgType)
+	}
+	return genAtom(fmt.Sprintf("CAST(%s AS %s)", arg.sql, pgType), kind), nil
+}
+
+// genCastType maps the target type of a source CAST to PostgreSQL.
+func genCastType(name string, params []string, dialect string) (string, genKind, error) {
+	mysql := dialect == "mysql"
+	switch name {
+	case "CHAR", "NCHAR", "VARCHAR", "NVARCHAR":
+		if len(params) == 0 && (name == "CHAR" || name == "NCHAR") && !mysql {
+			return "char(1)", genText, nil
+		}
+		if len(params) == 0 || params[0] == "MAX" {
+			return "text", genText, nil
+		}
+		if len(params) != 1 {
+			return "", genUnknown, fmt.Errorf("cast() to %s takes one length", strings.ToLower(name))
+		}
+		if (name == "CHAR" || name == "NCHAR") && !mysql {
+			return "char(" + params[0] + ")", genText, nil
+		}
+		return "varchar(" + params[0] + ")", genText, nil
+	case "SIGNED", "SIGNED INTEGER", "SIGNED INT":
+		if mysql {
+			return "bigint", genNumeric, nil
+		}
+	case "INT", "INTEGER":
+		if !mysql {
+			return "integer", genNumeric, nil
+		}
+	case "BIGINT", "SMALLINT":
+		if !mysql {
+			return strings.ToLower(name), genNumeric, nil
+		}
+	case "DECIMAL", "NUMERIC", "DEC":
+		if len(params) == 0 {
+			if mysql {
+				return "numeric(10,0)", genNumeric, nil
+			}
+			return "numeric(18,0)", genNumeric, nil
+		}
+		return "numeric(" + strings.Join(params, ",") + ")", genNumeric, nil
+	case "DOUBLE", "DOUBLE PRECISION":
+		return "double precision", genFloat, nil
+	case "FLOAT":
+		if mysql && len(params) == 0 {
+			return "real", genFloat, nil
+		}
+		if !mysql {
+			return "double precision", genFloat, nil
+		}
+	case "REAL":
+		if !mysql {
+			return "real", genFloat, nil
+		}
+		return "double precision", genFloat, nil
+	case "DATE":
+		return "date", genDate, nil
+	case "DATETIME", "DATETIME2":
+		// PostgreSQL keeps at most microseconds.
+		precision := map[string]string{"DATETIME": "0", "DATETIME2": "6"}[name]
+		if !mysql && name == "DATETIME" {
+			precision = "3"
+		}
+		if len(params) == 1 {
+			precision = params[0]
+		}
+		if len(params) > 1 {
+			return "", genUnknown, fmt.Errorf("cast() to %s takes one precision", strings.ToLower(name))
+		}
+		if precision > "6" || len(precision) > 1 {
+			precision = "6"
+		}
+		return "timestamp(" + precision + ")", genTimestamp, nil
+	case "JSON":
+		if mysql {
+			return "jsonb", genJSON, nil
+		}
+	}
+	return "", genUnknown, fmt.Errorf("cast() to %s is not supported", strings.ToLower(name))
+}
+
+// concat translates CONCAT. MySQL returns NULL when any argument is NULL,
+// which matches ||; MSSQL treats NULL arguments as empty strings. Numbers are
+// cast to text explicitly because || does not accept them on both sides.
+// PostgreSQL's own concat() is only STABLE and cannot be used.
+func (p *genParser) concat(args []genExpr) (genExpr, error) {
+	parts := make([]string, len(args))
+	for i, a := range args {
+		var part genExpr
+		switch {
+		case a.kind == genNumeric:
+			part = genAtom("CAST("+a.sql+" AS text)", genText)
+		case a.isText():
+			part = a
+		default:
+			return genExpr{}, fmt.Errorf("concat() argument of type %s", a.kind)
+		}
+		if p.dialect == "mssql" {
+			part = genAtom("COALESCE("+part.sql+", '')", genText)
+		}
+		parts[i] = genWrap(part, genPrecConcat+1)
+	}
+	if len(parts) == 1 {
+		return genExpr{sql: parts[0], kind: genText, prec: genPrecConcat + 1}, nil
+	}
+	return genExpr{sql: strings.Join(parts, " || "), kind: genText, prec: genPrecConcat}, nil
+}
+
+// genCall renders fn(args...) whose result family is the unified family of
+// its arguments.
+func genCall(fn string, args []genExpr) (genExpr, error) {
+	kind := genNull
+	parts := make([]string, len(args))
+	for i, a := range args {
+		var ok bool
+		if kind, ok = genUnify(kind, a.kind); !ok {
+			return genExpr{}, fmt.Errorf("%s() mixes incompatible argument types", strings.ToLower(fn))
+		}
+		parts[i] = a.sql
+	}
+	return genAtom(fn+"("+strings.Join(parts, ", ")+")", kind), nil
+}
+
+// genExtract renders EXTRACT(field FROM x) as an integer. timestamptz
+// operands are rejected because their fields depend on the session time zone.
+func genExtract(field string, arg genExpr) (genExpr, error) {
+	switch {
+	case arg.kind == genTimestamp:
+	case arg.kind == genDate && field != "HOUR" && field != "MINUTE":
+	default:
+		return genExpr{}, fmt.Errorf("cannot extract %s from %s", strings.ToLower(field), arg.kind)
+	}
+	return genAtom(fmt.Sprintf("CAST(EXTRACT(%s FROM %s) AS integer)", field, arg.sql), genNumeric), nil
+}
+
+// genAsDate renders a date or timestamp operand as a date.
+func genAsDate(e genExpr) (string, error) {
+	switch e.kind {
+	case genDate:
+		return genWrap(e, genPrecAdd+1), nil
+	case genTimestamp:
+		return "CAST(" + e.sql + " AS date)", nil
+	default:
+		return "", fmt.Errorf("date difference needs date or datetime operands, got %s", e.kind)
+	}
+}
+
+// genDayDiff renders the number of calendar days from b to a.
+func genDayDiff(a, b genExpr) (genExpr, error) {
+	left, err := genAsDate(a)
+	if err != nil {
+		return genExpr{}, err
+	}
+	right, err := genAsDate(b)
+	if err != nil {
+		return genExpr{}, err
+	}
+	return genExpr{sql: left + " - " + right, kind: genNumeric, prec: genPrecAdd}, nil
+}
+
+// genMSSQLDateDiff translates DATEDIFF(part, start, end), which counts the
+// part boundaries crossed between start and end.
+func genMSSQLDateDiff(part string, start, end genExpr) (genExpr, error) {
+	switch genDateParts[part] {
+	case "DAY", "DOY":
+		return genDayDiff(end, start)
+	case "YEAR":
+		y1, err := genExtract("YEAR", start)
+		if err != nil {
+			return genExpr{}, err
+		}
+		y2, err := genExtract("YEAR", end)
+		if err != nil {
+			return genExpr{}, err
+		}
+		return genBinary(y2, "-", y1, genPrecAdd, genNumeric), nil
+	case "MONTH":
+		var months [2]genExpr
+		for i, e := range []genExpr{start, end} {
+			y, err := genExtract("YEAR", e)
+			if err != nil {
+				return genExpr{}, err
+			}
+			m, _ := genExtract("MONTH", e)
+			months[i] = genBinary(genBinary(y, "*", genAtom("12", genNumeric), genPrecMul, genNumeric), "+", m, genPrecAdd, genNumeric)
+		}
+		return genBinary(months[1], "-", months[0], genPrecAdd, genNumeric), nil
+	default:
+		return genExpr{}, fmt.Errorf("unsupported datediff part %s", strings.ToLower(part))
+	}
+}
+
+// genJSONExtract renders doc #> path for a source JSON path.
+func genJSONExtract(doc genExpr, sourcePath string) (genExpr, error) {
+	if doc.kind != genJSON {
+		return genExpr{}, fmt.Errorf("JSON path extraction needs a JSON document, got %s", doc.kind)
+	}
+	path, err := translateJSONPath(sourcePath)
+	if err != nil {
+		return genExpr{}, err
+	}
+	d := genWrap(doc, genPrecConcat+1)
+	return genExpr{
+		sql:      d + " #> " + path,
+		kind:     genJSON,
+		prec:     genPrecConcat,
+		jsonDoc:  d,
+		jsonPath: path,
+	}, nil
+}
+
+// genJSONUnquote turns a #> extraction into the text-returning #>>.
+func genJSONUnquote(e genExpr) genExpr {
+	return genExpr{sql: e.jsonDoc + " #>> " + e.jsonPath, kind: genText, prec: genPrecConcat}
+}
+
+// genPathLiteral returns the source text of a JSON path argument, which
+// must be a string literal.
+func genPathLiteral(e genExpr) (string, error) {
+	if e.kind != genLiteral {
+		return "", fmt.Errorf("JSON path must be a string literal")
+	}
+	return strings.ReplaceAll(strings.TrimSuffix(strings.TrimPrefix(e.sql, "'"), "'"), "''", "'"), nil
+}
+
+// translateJSONPath converts a MySQL/MSSQL JSON path made of member and
+// array steps ($.a.b[0]."c d") into a PostgreSQL text-array path literal.
+// Wildcards, ranges and MSSQL strict mode are rejected.
+func translateJSONPath(path string) (string, error) {
+	s := strings.TrimSpace(path)
+	if rest, ok := strings.CutPrefix(s, "lax "); ok {
+		s = strings.TrimSpace(rest)
+	}
+	if !strings.HasPrefix(s, "$") {
+		return "", fmt.Errorf("unsupported JSON path %q", path)
+	}
+	var steps []string
+	for i := 1; i < len(s); {
+		switch s[i] {
+		case '.':
+			i++
+			if i < len(s) && s[i] == '"' {
+				j := strings.IndexByte(s[i+1:], '"')
+				if j < 0 {
+					return "", fmt.Errorf("unsupported JSON path %q", path)
+				}
+				steps = append(steps, s[i+1:i+1+j])
+				i += j + 2
+				continue
+			}
+			j := i
+			for j < len(s) && s[j] != '.' && s[j] != '[' {
+				j++
+			}
+			key := s[i:j]
+			if key == "" || strings.ContainsAny(key, "*\" ") {
+				return "", fmt.Errorf("unsupported JSON path %q", path)
+			}
+			steps = append(steps, key)
+			i = j
+		case '[':
+			j := strings.IndexByte(s[i:], ']')
+			if j < 0 {
+				return "", fmt.Errorf("unsupported JSON path %q", path)
+			}
+			idx := strings.TrimSpace(s[i+1 : i+j])
+			if idx == "" || strings.Trim(idx, "0123456789") != "" {
+				return "", fmt.Errorf("unsupported JSON path %q", path)
+			}
+			steps = append(steps, idx)
+			i += j + 1
+		default:
+			return "", fmt.Errorf("unsupported JSON path %q", path)
+		}
+	}
+	if len(steps) == 0 {
+		return "", fmt.Errorf("JSON path %q selects the whole document", path)
+	}
+	for i, step := range steps {
+		steps[i] = `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(step) + `"`
+	}
+	return pgLiteral("{" + strings.Join(steps, ",") + "}"), nil
+}
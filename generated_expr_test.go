@@ -0,0 +1,227 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslateGeneratedExpression(t *testing.T) {
+	mysqlTable := Table{
+		SourceName: "people",
+		PGName:     "people",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id", DataType: "int", ColumnType: "int"},
+			{SourceName: "first", PGName: "first", DataType: "varchar", ColumnType: "varchar(32)", CharMaxLen: 32},
+			{SourceName: "last", PGName: "last", DataType: "varchar", ColumnType: "varchar(32)", CharMaxLen: 32},
+			{SourceName: "qty", PGName: "qty", DataType: "int", ColumnType: "int"},
+			{SourceName: "price", PGName: "price", DataType: "decimal", ColumnType: "decimal(10,2)", Precision: 10, Scale: 2},
+			{SourceName: "ratio", PGName: "ratio", DataType: "double", ColumnType: "double"},
+			{SourceName: "doc", PGName: "doc", DataType: "json", ColumnType: "json"},
+			{SourceName: "born", PGName: "born", DataType: "date", ColumnType: "date"},
+			{SourceName: "seen", PGName: "seen", DataType: "datetime", ColumnType: "datetime"},
+			{SourceName: "stamp", PGName: "stamp", DataType: "timestamp", ColumnType: "timestamp"},
+			{SourceName: "full", PGName: "full", DataType: "varchar", ColumnType: "varchar(64)", CharMaxLen: 64, Extra: "VIRTUAL GENERATED"},
+		},
+	}
+	mssqlTable := Table{
+		SourceName: "People",
+		PGName:     "people",
+		Columns: []Column{
+			{SourceName: "First", PGName: "first", DataType: "nvarchar", CharMaxLen: 32},
+			{SourceName: "Last", PGName: "last", DataType: "nvarchar", CharMaxLen: 32},
+			{SourceName: "Qty", PGName: "qty", DataType: "int"},
+			{SourceName: "Doc", PGName: "doc", DataType: "nvarchar", CharMaxLen: -1},
+			{SourceName: "Ordered", PGName: "ordered", DataType: "datetime2"},
+			{SourceName: "Shipped", PGName: "shipped", DataType: "datetime2"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		table   Table
+		src     SourceDB
+		col     Column
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "mysql arithmetic keeps precedence",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "decimal", ColumnType: "decimal(12,2)", Extra: "STORED GENERATED", GenerationExpression: "((`qty` * `price`) + 1)"},
+			want: `"qty" * "price" + 1`,
+		},
+		{
+			name:  "mysql subtraction groups right operand",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "int", ColumnType: "int", Extra: "VIRTUAL GENERATED", GenerationExpression: "(`qty` - (`id` - 1))"},
+			want: `"qty" - ("id" - 1)`,
+		},
+		{
+			name:  "mysql division is exact",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "decimal", ColumnType: "decimal(12,4)", Extra: "VIRTUAL GENERATED", GenerationExpression: "(`qty` / 3)"},
+			want: `CAST("qty" AS numeric) / 3`,
+		},
+		{
+			name:  "mysql concat with escaped quotes and numbers",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "varchar", ColumnType: "varchar(64)", CharMaxLen: 64, Extra: "VIRTUAL GENERATED", GenerationExpression: `concat(` + "`first`" + `,_utf8mb4\' \',` + "`last`" + `,_utf8mb4\'#\',` + "`id`" + `)`},
+			want: `"first" || ' ' || "last" || '#' || CAST("id" AS text)`,
+		},
+		{
+			name:  "mysql json unquote extract",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "varchar", ColumnType: "varchar(64)", CharMaxLen: 64, Extra: "VIRTUAL GENERATED", GenerationExpression: "json_unquote(json_extract(`doc`,_utf8mb4'$.user.names[0]'))"},
+			want: `"doc" #>> '{"user","names","0"}'`,
+		},
+		{
+			name:  "mysql json extract keeps json",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "json", ColumnType: "json", Extra: "VIRTUAL GENERATED", GenerationExpression: "json_extract(`doc`,_utf8mb4'$.\"a b\"')"},
+			want: `CAST("doc" #> '{"a b"}' AS jsonb)`,
+		},
+		{
+			name:  "mysql if and ifnull",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "varchar", ColumnType: "varchar(8)", CharMaxLen: 8, Extra: "VIRTUAL GENERATED", GenerationExpression: "if((ifnull(`qty`,0) > 0),_utf8mb4'yes',_utf8mb4'no')"},
+			want: `CASE WHEN COALESCE("qty", 0) > 0 THEN 'yes' ELSE 'no' END`,
+		},
+		{
+			name:  "mysql year of datetime",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "int", ColumnType: "int", Extra: "STORED GENERATED", GenerationExpression: "year(`seen`)"},
+			want: `CAST(EXTRACT(YEAR FROM "seen") AS integer)`,
+		},
+		{
+			name:  "mysql to_days rejected",
+			table: mysqlTable, src: mysqlSrc,
+			col:     Column{DataType: "int", ColumnType: "int", Extra: "STORED GENERATED", GenerationExpression: "(to_days(`seen`) - to_days(`born`))"},
+			wantErr: "unsupported function to_days()",
+		},
+		{
+			name:  "mysql datediff of date and datetime",
+			table: mysqlTable, src: mysqlSrc,
+			col:  Column{DataType: "int", ColumnType: "int", Extra: "STORED GENERATED", GenerationExpression: "datediff(`seen`,`born`)"},
+			want: `CAST("seen" AS date) - "born"`,
+		},
+		{
+			name:  "mysql timestamp depends on time zone",
+			table: mysqlTable, src: mysqlSrc,
+			col:     Column{DataType: "int", ColumnType: "int", Extra: "STORED GENERATED", GenerationExpression: "year(`stamp`)"},
+			wantErr: "cannot extract year from timestamptz",
+		},
+		{
+			name:  "mysql generated column reference rejected",
+			table: mysqlTable, src: mysqlSrc,
+			col:     Column{DataType: "varchar", ColumnType: "varchar(64)", CharMaxLen: 64, Extra: "VIRTUAL GENERATED", GenerationExpression: "upper(`full`)"},
+			wantErr: "references generated column full",
+		},
+		{
+			name:  "mysql result type mismatch rejected",
+			table: mysqlTable, src: mysqlSrc,
+			col:     Column{DataType: "int", ColumnType: "int", Extra: "VIRTUAL GENERATED", GenerationExpression: "concat(`first`,`last`)"},
+			wantErr: "expression yields text, column maps to integer",
+		},
+		{
+			name:  "mysql float modulo rejected",
+			table: mysqlTable, src: mysqlSrc,
+			col:     Column{DataType: "double", ColumnType: "double", Extra: "VIRTUAL GENERATED", GenerationExpression: "(`ratio` % 2)"},
+			wantErr: "floating-point",
+		},
+		{
+			name:  "mssql string plus becomes concatenation",
+			table: mssqlTable, src: &mssqlSourceDB{},
+			col:  Column{DataType: "nvarchar", CharMaxLen: 65, Extra: "COMPUTED", GenerationExpression: "(([First]+N' ')+[Last])"},
+			want: `"first" || ' ' || "last"`,
+		},
+		{
+			name:  "mssql concat treats null as empty",
+			table: mssqlTable, src: &mssqlSourceDB{},
+			col:  Column{DataType: "nvarchar", CharMaxLen: 65, Extra: "COMPUTED", GenerationExpression: "(concat([First],' ',[Qty]))"},
+			want: `COALESCE("first", '') || COALESCE(' ', '') || COALESCE(CAST("qty" AS text), '')`,
+		},
+		{
+			name:  "mssql iif isnull and integer division",
+			table: mssqlTable, src: &mssqlSourceDB{},
+			col:  Column{DataType: "int", Extra: "COMPUTED", GenerationExpression: "(iif(isnull([Qty],(0))>(10),[Qty]/(2),(0)))"},
+			want: `CASE WHEN COALESCE("qty", 0) > 10 THEN "qty" / 2 ELSE 0 END`,
+		},
+		{
+			name:  "mssql json_value on nvarchar",
+			table: mssqlTable, src: &mssqlSourceDB{},
+			col:  Column{DataType: "nvarchar", CharMaxLen: 100, Extra: "COMPUTED", GenerationExpression: "(json_value([Doc],'$.name'))"},
+			want: `CAST("doc" AS jsonb) #>> '{"name"}'`,
+		},
+		{
+			name:  "mssql datediff day reverses arguments",
+			table: mssqlTable, src: &mssqlSourceDB{},
+			col:  Column{DataType: "int", Extra: "COMPUTED", GenerationExpression: "(datediff(day,[Ordered],[Shipped]))"},
+			want: `CAST("shipped" AS date) - CAST("ordered" AS date)`,
+		},
+		{
+			name:  "mssql datepart",
+			table: mssqlTable, src: &mssqlSourceDB{},
+			col:  Column{DataType: "int", Extra: "COMPUTED", GenerationExpression: "(datepart(month,[Ordered]))"},
+			want: `CAST(EXTRACT(MONTH FROM "ordered") AS integer)`,
+		},
+		{
+			name:  "mssql convert rejected",
+			table: mssqlTable, src: &mssqlSourceDB{},
+			col:     Column{DataType: "nvarchar", CharMaxLen: 10, Extra: "COMPUTED", GenerationExpression: "(CONVERT([varchar](10),[Qty]))"},
+			wantErr: `convert(): unknown column or unsupported keyword "varchar"`,
+		},
+		{
+			name:  "sqlite not translated",
+			table: mysqlTable, src: &sqliteSourceDB{},
+			col:     Column{DataType: "integer", Extra: "STORED GENERATED"},
+			wantErr: "SQLite generation expressions are not translated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := translateGeneratedExpression(tt.table, tt.col, tt.src, defaultTypeMappingConfig())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("translateGeneratedExpression(%q) = %q, %v; want error %q", tt.col.GenerationExpression, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("translateGeneratedExpression(%q): %v", tt.col.GenerationExpression, err)
+			}
+			if got != tt.want {
+				t.Fatalf("translateGeneratedExpression(%q) = %q, want %q", tt.col.GenerationExpression, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateJSONPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "$.a", want: `'{"a"}'`},
+		{path: "$.a.b[2]", want: `'{"a","b","2"}'`},
+		{path: `$."odd \"key"`, wantErr: true},
+		{path: `$."it's"`, want: `'{"it''s"}'`},
+		{path: "lax $.x", want: `'{"x"}'`},
+		{path: "strict $.x", wantErr: true},
+		{path: "$.a[*]", wantErr: true},
+		{path: "$", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := translateJSONPath(tt.path)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("translateJSONPath(%q) = %q, want error", tt.path, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("translateJSONPath(%q) = %q, %v; want %q", tt.path, got, err, tt.want)
+		}
+	}
+}
@@ -1,13 +1,31 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
+
+// prefixIndexFullColumnMaxChars is the longest bounded string column that a
+// non-unique prefix index covers whole: 676 four-byte characters stay under
+// the PostgreSQL btree entry limit of about 2700 bytes.
+const prefixIndexFullColumnMaxChars = 676
 
 func indexUnsupportedReason(table Table, idx Index, typeMap TypeMappingConfig) (string, bool) {
 	if idx.HasExpression {
-		return "expression index key-parts are not currently supported", true
+		if len(idx.Expressions) == 0 {
+			return "expression index key-parts are not currently supported", true
+		}
+		if idx.PGExpressions == nil {
+			return "expression index key-parts could not be translated", true
+		}
+	}
+	if idx.Filter != "" && idx.PGFilter == "" {
+		return "filtered index predicate could not be translated", true
 	}
 	if idx.HasPrefix {
-		return "prefix indexes (SUB_PART) are not currently supported", true
+		if _, err := indexKeys(table, idx, typeMap); err != nil {
+			return err.Error(), true
+		}
 	}
 	if len(idx.Columns) == 0 {
 		return "index has no plain column key-parts", true
@@ -24,6 +42,12 @@ func indexUnsupportedReason(table Table, idx Index, typeMap TypeMappingConfig) (
 		}
 		return "", false
 	}
+	if idx.Type == "FULLTEXT" {
+		if !fullTextEnabled(typeMap) {
+			return "FULLTEXT indexes require fulltext_mode = \"expression\" or \"generated_column\"", true
+		}
+		return "", false
+	}
 	if idx.Type != "" && idx.Type != "BTREE" {
 		return fmt.Sprintf("index type %q is not supported", idx.Type), true
 	}
@@ -64,3 +88,147 @@ func findColumnByPGName(table Table, pgName string) (Column, bool) {
 	}
 	return Column{}, false
 }
+
+// indexKeys returns the PostgreSQL key list of idx with sort directions. Plain
+// columns are quoted; MySQL prefix key-parts become left(col, n) (or
+// substring for bytea). A non-unique prefix on a short bounded column indexes
+// the whole column instead, which serves the same queries and more.
+// Functional key-parts use their translated PGExpressions.
+func indexKeys(table Table, idx Index, typeMap TypeMappingConfig) ([]string, error) {
+	keys := make([]string, len(idx.Columns))
+	for i, name := range idx.Columns {
+		key := pgIdent(name)
+		if expr := indexExpression(idx, i); expr != "" {
+			if i >= len(idx.PGExpressions) || idx.PGExpressions[i] == "" {
+				return nil, fmt.Errorf("expression key-part %s is not translated", expr)
+			}
+			key = "(" + idx.PGExpressions[i] + ")"
+		} else if prefix := indexPrefixLength(idx, i); prefix > 0 {
+			expr, err := prefixIndexKey(table, idx, name, prefix, typeMap)
+			if err != nil {
+				return nil, err
+			}
+			key = expr
+		}
+		if i < len(idx.ColumnOrders) && strings.EqualFold(idx.ColumnOrders[i], "DESC") {
+			key += " DESC"
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+func indexExpression(idx Index, i int) string {
+	if i < len(idx.Expressions) {
+		return idx.Expressions[i]
+	}
+	return ""
+}
+
+func indexPrefixLength(idx Index, i int) int {
+	if i < len(idx.PrefixLengths) {
+		return idx.PrefixLengths[i]
+	}
+	return 0
+}
+
+// prefixIndexKey returns the key expression for one prefix key-part.
+func prefixIndexKey(table Table, idx Index, name string, prefix int, typeMap TypeMappingConfig) (string, error) {
+	col, ok := findColumnByPGName(table, name)
+	if !ok {
+		return "", fmt.Errorf("prefix index column %s not found", name)
+	}
+	pgType, err := mysqlMapType(col, typeMap)
+	if err != nil {
+		return "", fmt.Errorf("prefix index column %s: %w", name, err)
+	}
+	pgType = pgTypeForCollation(col, pgType, typeMap)
+
+	switch {
+	case pgType == "bytea":
+		return fmt.Sprintf("substring(%s FROM 1 FOR %d)", pgIdent(name), prefix), nil
+	case pgType == "text", pgType == "citext", strings.HasPrefix(pgType, "varchar"), strings.HasPrefix(pgType, "char"):
+	default:
+		return "", fmt.Errorf("prefix index on column %s mapped to %s is not supported", name, pgType)
+	}
+
+	if !idx.Unique && col.CharMaxLen > 0 && col.CharMaxLen <= prefixIndexFullColumnMaxChars {
+		return pgIdent(name), nil
+	}
+	expr := fmt.Sprintf("left(%s, %d)", pgIdent(name), prefix)
+	if pgType == "citext" {
+		// left() returns text; keep the case-insensitive comparison.
+		expr = fmt.Sprintf("CAST(%s AS citext)", expr)
+	}
+	return expr, nil
+}
+
+// indexHasExpressionKeys reports whether the PostgreSQL index for idx has
+// any key that is not a plain column.
+func indexHasExpressionKeys(table Table, idx Index, typeMap TypeMappingConfig) bool {
+	keys, err := indexKeys(table, idx, typeMap)
+	if err != nil {
+		return false
+	}
+	for i, key := range keys {
+		if strings.TrimSuffix(key, " DESC") != pgIdent(idx.Columns[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// translateIndexExpressions records the PostgreSQL key expressions of every
+// index whose functional key-parts all translate, and the WHERE clause of
+// every filtered index whose predicate translates. The others keep
+// PGExpressions nil or PGFilter empty and are skipped like other unsupported
+// indexes.
+func translateIndexExpressions(schema *Schema, src SourceDB, typeMap TypeMappingConfig) {
+	if schema == nil {
+		return
+	}
+	for i := range schema.Tables {
+		t := &schema.Tables[i]
+		for j := range t.Indexes {
+			idx := &t.Indexes[j]
+			if idx.Filter != "" {
+				if filter, err := translateCheckExpression(*t, idx.Filter, src, typeMap); err == nil {
+					idx.PGFilter = filter
+				}
+			}
+			if len(idx.Expressions) == 0 {
+				continue
+			}
+			if exprs, err := translateIndexKeyExpressions(*t, *idx, src, typeMap); err == nil {
+				idx.PGExpressions = exprs
+			}
+		}
+	}
+}
+
+// translateIndexKeyExpressions translates the functional key-parts of idx
+// with the generated-column expression translator. Column key-parts map to
+// "" in the result.
+func translateIndexKeyExpressions(table Table, idx Index, src SourceDB, typeMap TypeMappingConfig) ([]string, error) {
+	dialect := checkDialect(src)
+	if dialect != "mysql" && dialect != "mssql" {
+		return nil, fmt.Errorf("%s index expressions are not translated", src.Name())
+	}
+	exprs := make([]string, len(idx.Expressions))
+	for i, source := range idx.Expressions {
+		if source == "" {
+			continue
+		}
+		p, err := newGenParser(table, source, src, typeMap)
+		if err != nil {
+			return nil, fmt.Errorf("expression %s: %w", source, err)
+		}
+		p.index = true
+		result, err := p.parseAll()
+		if err != nil {
+			return nil, fmt.Errorf("expression %s: %w", source, err)
+		}
+		exprs[i] = result.sql
+	}
+	return exprs, nil
+}
@@ -9,6 +9,8 @@ func TestIndexUnsupportedReason(t *testing.T) {
 	table := Table{
 		Columns: []Column{
 			{PGName: "geom", DataType: "geometry", ColumnType: "geometry"},
+			{PGName: "title", DataType: "varchar", ColumnType: "varchar(255)", CharMaxLen: 255},
+			{PGName: "rank", DataType: "int", ColumnType: "int"},
 		},
 	}
 	tests := []struct {
@@ -18,9 +20,11 @@ func TestIndexUnsupportedReason(t *testing.T) {
 		ok   bool
 	}{
 		{"plain btree", Index{SourceName: "idx_a", Type: "BTREE", Columns: []string{"a"}}, defaultTypeMappingConfig(), false},
-		{"prefix index", Index{SourceName: "idx_p", Type: "BTREE", Columns: []string{"a"}, HasPrefix: true}, defaultTypeMappingConfig(), true},
+		{"prefix index", Index{SourceName: "idx_p", Type: "BTREE", Columns: []string{"title"}, HasPrefix: true, PrefixLengths: []int{10}}, defaultTypeMappingConfig(), false},
+		{"prefix on non-string column", Index{SourceName: "idx_p", Type: "BTREE", Columns: []string{"rank"}, HasPrefix: true, PrefixLengths: []int{4}}, defaultTypeMappingConfig(), true},
 		{"expression index", Index{SourceName: "idx_e", Type: "BTREE", HasExpression: true}, defaultTypeMappingConfig(), true},
 		{"fulltext", Index{SourceName: "idx_f", Type: "FULLTEXT", Columns: []string{"body"}}, defaultTypeMappingConfig(), true},
+		{"fulltext with fulltext_mode", Index{SourceName: "idx_f", Type: "FULLTEXT", Columns: []string{"body"}}, TypeMappingConfig{FullTextMode: "expression"}, false},
 		{"no columns", Index{SourceName: "idx_n", Type: "BTREE"}, defaultTypeMappingConfig(), true},
 		{"spatial without postgis", Index{SourceName: "idx_geom", Type: "SPATIAL", Columns: []string{"geom"}}, defaultTypeMappingConfig(), true},
 		{"spatial with postgis", Index{SourceName: "idx_geom", Type: "SPATIAL", Columns: []string{"geom"}}, TypeMappingConfig{UsePostGIS: true}, false},
@@ -83,3 +87,179 @@ func TestQuotedOrderedColumnList(t *testing.T) {
 		t.Fatalf("quotedOrderedColumnList()=%q, want %q", got, want)
 	}
 }
+
+func TestIndexKeys_Prefix(t *testing.T) {
+	table := Table{
+		Columns: []Column{
+			{PGName: "title", DataType: "varchar", ColumnType: "varchar(255)", CharMaxLen: 255},
+			{PGName: "body", DataType: "text", ColumnType: "text", CharMaxLen: 65535},
+			{PGName: "huge", DataType: "varchar", ColumnType: "varchar(2000)", CharMaxLen: 2000},
+			{PGName: "payload", DataType: "blob", ColumnType: "blob", CharMaxLen: 65535},
+			{PGName: "email", DataType: "varchar", ColumnType: "varchar(255)", CharMaxLen: 255, Collation: "utf8mb4_general_ci"},
+		},
+	}
+	ciTypeMap := defaultTypeMappingConfig()
+	ciTypeMap.CIAsCitext = true
+
+	tests := []struct {
+		name string
+		idx  Index
+		tm   TypeMappingConfig
+		want string
+	}{
+		{
+			name: "short column indexed whole",
+			idx:  Index{Columns: []string{"title"}, PrefixLengths: []int{20}},
+			tm:   defaultTypeMappingConfig(),
+			want: `"title"`,
+		},
+		{
+			name: "unique keeps prefix semantics",
+			idx:  Index{Columns: []string{"title"}, PrefixLengths: []int{20}, Unique: true},
+			tm:   defaultTypeMappingConfig(),
+			want: `left("title", 20)`,
+		},
+		{
+			name: "text column",
+			idx:  Index{Columns: []string{"body", "title"}, PrefixLengths: []int{100, 0}, ColumnOrders: []string{"ASC", "DESC"}},
+			tm:   defaultTypeMappingConfig(),
+			want: `left("body", 100), "title" DESC`,
+		},
+		{
+			name: "long bounded column",
+			idx:  Index{Columns: []string{"huge"}, PrefixLengths: []int{191}},
+			tm:   defaultTypeMappingConfig(),
+			want: `left("huge", 191)`,
+		},
+		{
+			name: "bytea",
+			idx:  Index{Columns: []string{"payload"}, PrefixLengths: []int{16}},
+			tm:   defaultTypeMappingConfig(),
+			want: `substring("payload" FROM 1 FOR 16)`,
+		},
+		{
+			name: "citext unique",
+			idx:  Index{Columns: []string{"email"}, PrefixLengths: []int{64}, Unique: true},
+			tm:   ciTypeMap,
+			want: `CAST(left("email", 64) AS citext)`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.idx.HasPrefix = true
+			keys, err := indexKeys(table, tt.idx, tt.tm)
+			if err != nil {
+				t.Fatalf("indexKeys() error: %v", err)
+			}
+			if got := strings.Join(keys, ", "); got != tt.want {
+				t.Errorf("indexKeys() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateIndexExpressions(t *testing.T) {
+	table := Table{
+		SourceName: "products",
+		PGName:     "products",
+		Columns: []Column{
+			{SourceName: "sku", PGName: "sku", DataType: "varchar", ColumnType: "varchar(64)", CharMaxLen: 64},
+			{SourceName: "doc", PGName: "doc", DataType: "json", ColumnType: "json"},
+			{SourceName: "created_at", PGName: "created_at", DataType: "datetime", ColumnType: "datetime"},
+			{SourceName: "price", PGName: "price", DataType: "decimal", ColumnType: "decimal(10,2)", Precision: 10, Scale: 2},
+		},
+	}
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr string
+	}{
+		{name: "lower", expr: "lower(`sku`)", want: `(lower("sku")), "price"`},
+		{name: "date", expr: "cast(`created_at` as date)", want: `(CAST("created_at" AS date)), "price"`},
+		{name: "mysql date()", expr: "date(`created_at`)", want: `(CAST("created_at" AS date)), "price"`},
+		{
+			name: "json path cast",
+			expr: "cast(json_unquote(json_extract(`doc`,_utf8mb4'$.sku')) as char(32) charset utf8mb4)",
+			want: `(CAST("doc" #>> '{"sku"}' AS varchar(32))), "price"`,
+		},
+		{name: "json operator", expr: "(`doc` ->> _utf8mb4'$.tag')", want: `("doc" #>> '{"tag"}'), "price"`},
+		{name: "date to text", expr: "cast(`created_at` as char(10))", wantErr: "cast() from timestamp"},
+		{name: "unknown function", expr: "md5(`sku`)", wantErr: "unsupported function md5()"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{Tables: []Table{table}}
+			schema.Tables[0].Indexes = []Index{{
+				Name:          "idx_x",
+				Columns:       []string{"", "price"},
+				Expressions:   []string{tt.expr, ""},
+				HasExpression: true,
+			}}
+			translateIndexExpressions(schema, mysqlSrc, defaultTypeMappingConfig())
+			idx := schema.Tables[0].Indexes[0]
+			if tt.wantErr != "" {
+				_, err := translateIndexKeyExpressions(table, idx, mysqlSrc, defaultTypeMappingConfig())
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("translateIndexKeyExpressions() error = %v, want containing %q", err, tt.wantErr)
+				}
+				if reason, unsupported := indexUnsupportedReason(table, idx, defaultTypeMappingConfig()); !unsupported {
+					t.Fatalf("indexUnsupportedReason() = %q, want unsupported", reason)
+				}
+				return
+			}
+			if reason, unsupported := indexUnsupportedReason(table, idx, defaultTypeMappingConfig()); unsupported {
+				t.Fatalf("indexUnsupportedReason() = %q, want supported", reason)
+			}
+			keys, err := indexKeys(table, idx, defaultTypeMappingConfig())
+			if err != nil {
+				t.Fatalf("indexKeys() error: %v", err)
+			}
+			if got := strings.Join(keys, ", "); got != tt.want {
+				t.Errorf("indexKeys() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateIndexFilter(t *testing.T) {
+	table := Table{
+		SourceName: "Orders",
+		PGName:     "orders",
+		Columns: []Column{
+			{SourceName: "AccountID", PGName: "account_id", DataType: "int"},
+			{SourceName: "DeletedAt", PGName: "deleted_at", DataType: "datetime2"},
+			{SourceName: "Status", PGName: "status", DataType: "int"},
+		},
+	}
+	tests := []struct {
+		name   string
+		filter string
+		want   string
+	}{
+		{name: "is null", filter: "([DeletedAt] IS NULL)", want: `("deleted_at" IS NULL)`},
+		{name: "is not null", filter: "([DeletedAt] IS NOT NULL)", want: `("deleted_at" IS NOT NULL)`},
+		{name: "comparison", filter: "([Status]>(0) AND [Status]<>(3))", want: `("status" > (0) AND "status" <> (3))`},
+		{name: "untranslatable", filter: "([Status]>dbo.fn_min())"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := &Schema{Tables: []Table{table}}
+			schema.Tables[0].Indexes = []Index{{
+				Name:    "ix_orders_account",
+				Columns: []string{"account_id"},
+				Include: []string{"status"},
+				Filter:  tt.filter,
+			}}
+			translateIndexExpressions(schema, &mssqlSourceDB{}, defaultTypeMappingConfig())
+			idx := schema.Tables[0].Indexes[0]
+			if idx.PGFilter != tt.want {
+				t.Fatalf("PGFilter = %q, want %q", idx.PGFilter, tt.want)
+			}
+			_, unsupported := indexUnsupportedReason(table, idx, defaultTypeMappingConfig())
+			if unsupported != (tt.want == "") {
+				t.Errorf("indexUnsupportedReason() unsupported = %v, want %v", unsupported, tt.want == "")
+			}
+		})
+	}
+}
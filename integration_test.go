@@ -379,6 +379,14 @@ dsn = %q
 	assertFKExists(t, pgPool, pgSchema, "comments", "posts")
 	assertFKExists(t, pgPool, pgSchema, "comments", "users")
 
+	issues, err := checkSchemaConformance(ctx, pgPool, schema, pgSchema, src, effectiveTypeMapping(cfg))
+	if err != nil {
+		t.Fatalf("checkSchemaConformance: %v", err)
+	}
+	for _, issue := range issues {
+		t.Errorf("schema conformance: %s", issue.describe())
+	}
+
 	// Spot-check data
 	var name string
 	err = pgPool.QueryRow(ctx,
@@ -1554,6 +1562,79 @@ func buildReadOnlyUserDSN(baseDSN, user, password string) (string, error) {
 	return cfg.FormatDSN(), nil
 }
 
+func TestIntegration_CreateViews_RecordsFailures(t *testing.T) {
+	pgDSN := os.Getenv("POSTGRES_DSN")
+	if pgDSN == "" {
+		t.Skip("POSTGRES_DSN env var required")
+	}
+	ctx := context.Background()
+	pool := openIntegrationPGPool(t, pgDSN)
+	defer pool.Close()
+
+	pgSchema := integrationSchemaName("pgferry_views")
+	ensureDroppedSchema(t, pool, pgSchema)
+	defer dropSchema(t, pool, pgSchema)
+	if _, err := pool.Exec(ctx, fmt.Sprintf("CREATE SCHEMA %s", pgIdent(pgSchema))); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+
+	views := []View{
+		{SourceName: "v_ok", PGName: "v_ok", SQL: fmt.Sprintf("CREATE VIEW %s.v_ok AS SELECT 1 AS one", pgIdent(pgSchema))},
+		{SourceName: "v_bad", PGName: "v_bad", SQL: fmt.Sprintf("CREATE VIEW %s.v_bad AS SELECT pgferry_missing_fn() AS x", pgIdent(pgSchema))},
+	}
+	if err := createViews(ctx, pool, views, pgSchema); err != nil {
+		t.Fatalf("createViews: %v", err)
+	}
+	if views[0].CreateError != "" {
+		t.Errorf("v_ok CreateError = %q, want empty", views[0].CreateError)
+	}
+	if views[1].CreateError == "" {
+		t.Error("v_bad CreateError is empty, want the creation error")
+	}
+	if got := failedViewWarnings(views); len(got) != 1 {
+		t.Errorf("failedViewWarnings() = %v, want 1 entry", got)
+	}
+}
+
+func TestIntegration_RepairTable_FailedCopyKeepsRows(t *testing.T) {
+	pgDSN := os.Getenv("POSTGRES_DSN")
+	if pgDSN == "" {
+		t.Skip("POSTGRES_DSN env var required")
+	}
+	ctx := context.Background()
+	pool := openIntegrationPGPool(t, pgDSN)
+	defer pool.Close()
+
+	pgSchema := integrationSchemaName("pgferry_repair")
+	ensureDroppedSchema(t, pool, pgSchema)
+	defer dropSchema(t, pool, pgSchema)
+	if _, err := pool.Exec(ctx, fmt.Sprintf("CREATE SCHEMA %s; CREATE TABLE %s.users (id smallint); INSERT INTO %s.users VALUES (7), (8)",
+		pgIdent(pgSchema), pgIdent(pgSchema), pgIdent(pgSchema))); err != nil {
+		t.Fatalf("seed target: %v", err)
+	}
+
+	// 100000 overflows the target smallint, so the COPY fails after the delete.
+	src := &sqliteSourceDB{}
+	sqliteDB, err := src.OpenDB(filepath.Join(t.TempDir(), "source.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer sqliteDB.Close()
+	if _, err := sqliteDB.Exec(`CREATE TABLE users (id INTEGER); INSERT INTO users VALUES (1), (100000)`); err != nil {
+		t.Fatalf("seed source: %v", err)
+	}
+
+	table := Table{
+		SourceName: "users",
+		PGName:     "users",
+		Columns:    []Column{{SourceName: "id", PGName: "id", DataType: "integer", ColumnType: "INTEGER", Nullable: true}},
+	}
+	if err := repairTable(ctx, src, sqliteDB, pool, pgSchema, defaultTypeMappingConfig(), repairTarget{Table: table}); err == nil {
+		t.Fatal("repairTable() error = nil, want copy error")
+	}
+	assertRowCount(t, pool, pgSchema, "users", 2)
+}
+
 func requireMySQLAndPostgresDSNs(t *testing.T) (string, string) {
 	t.Helper()
 
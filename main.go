@@ -62,6 +62,8 @@ func init() {
 	rootCmd.AddCommand(migrateCmd)
 	rootCmd.AddCommand(generateCmd)
 	rootCmd.AddCommand(planCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(repairCmd)
 }
 
 func main() {
@@ -193,13 +195,36 @@ func runMigrationWithConfig(cfg *MigrationConfig) error {
 	}
 	if sourceObjects, err := src.IntrospectSourceObjects(sourceDB, dbName); err != nil {
 		log.Printf("WARN: failed to introspect non-table source objects: %v", err)
-	} else if warnings := sourceObjectWarnings(sourceObjects); len(warnings) > 0 {
-		log.Printf("source object report:")
-		for _, w := range warnings {
-			log.Printf("  WARN: %s", w)
+	} else {
+		schema.Views = translateViews(sourceObjects, schema, src, cfg.Schema)
+		if n := countTranslatedViews(schema.Views); n > 0 {
+			log.Printf("translated %d of %d view(s); they are created after the tables", n, len(schema.Views))
+		}
+		if warnings := sourceObjectWarnings(sourceObjects, schema.Views); len(warnings) > 0 {
+			log.Printf("source object report:")
+			for _, w := range warnings {
+				log.Printf("  WARN: %s", w)
+			}
 		}
 	}
 	typeMap := effectiveTypeMapping(cfg)
+	translateGeneratedColumns(schema, src, typeMap)
+	translateIndexExpressions(schema, src, typeMap)
+	translatePartitioning(schema, src, typeMap)
+	if err := applyPartitioningConfig(ctx, sourceDB, src, schema, cfg.Partitioning, typeMap); err != nil {
+		return err
+	}
+	for _, t := range schema.Tables {
+		if p := t.Partitioning; p != nil && p.PGKey == "" {
+			log.Printf("WARN: %s is partitioned by %s %s but is created unpartitioned: %s", t.SourceName, p.Method, p.Expression, p.Reason)
+		}
+	}
+	if cycles := findForeignKeyCycles(schema); len(cycles) > 0 {
+		log.Printf("foreign key cycle report: %d cycle(s) detected (deferrable_foreign_keys = %q)", len(cycles), cfg.DeferrableForeignKeys)
+		for _, c := range cycles {
+			log.Printf("  %s: %s", strings.Join(c.Tables, " ↔ "), strings.Join(c.ForeignKeys, ", "))
+		}
+	}
 	var resumeCompatibility checkpointCompatibility
 	if cfg.Resume {
 		resumeCompatibility, err = buildCheckpointCompatibility(cfg, schema, src, dbName, typeMap)
@@ -213,12 +238,21 @@ func runMigrationWithConfig(cfg *MigrationConfig) error {
 			log.Printf("  WARN: %s", w)
 		}
 	}
-	if warnings := collectGeneratedColumnWarnings(schema); len(warnings) > 0 {
+	if n := countTranslatedGeneratedColumns(schema); n > 0 {
+		log.Printf("generated column report: %d generated column(s) will be recreated as PostgreSQL generated columns", n)
+	}
+	if warnings := collectGeneratedColumnWarnings(schema, src, typeMap); len(warnings) > 0 {
 		log.Printf("generated column report: %d generated column(s) need manual expression migration", len(warnings))
 		for _, w := range warnings {
 			log.Printf("  WARN: %s", w)
 		}
 	}
+	if warnings := collectCheckConstraintWarnings(schema, src, typeMap); len(warnings) > 0 {
+		log.Printf("check constraint report: %d check constraint(s) need manual migration", len(warnings))
+		for _, w := range warnings {
+			log.Printf("  WARN: %s", w)
+		}
+	}
 	if warnings := collectCollationWarnings(schema, typeMap); len(warnings) > 0 {
 		log.Printf("charset/collation report:")
 		for _, w := range warnings {
@@ -278,11 +312,31 @@ func runMigrationWithConfig(cfg *MigrationConfig) error {
 			}
 		}
 
-		// 5b. Create bare tables (no PKs, FKs, indexes)
+		// 5b. Create standalone sequences referenced by column defaults
+		if len(schema.Sequences) > 0 {
+			log.Printf("creating sequences...")
+			if err := createSequences(ctx, pgPool, schema, cfg.Schema); err != nil {
+				return fmt.Errorf("create sequences: %w", err)
+			}
+		}
+
+		// 5c. Create bare tables (no PKs, FKs, indexes)
 		log.Printf("creating tables...")
 		if err := createTables(ctx, pgPool, schema, cfg.Schema, cfg.UnloggedTables, cfg.PreserveDefaults, typeMap, src); err != nil {
 			return fmt.Errorf("create tables: %w", err)
 		}
+	} else if countTranslatedGeneratedColumns(schema) > 0 {
+		if err := matchTargetGeneratedColumns(ctx, pgPool, schema, cfg.Schema); err != nil {
+			return err
+		}
+	}
+
+	// In single_tx mode, keep the source snapshot open through validation so
+	// source reads see exactly the data that was copied.
+	var snapshot *sourceSnapshot
+	if cfg.SourceSnapshotMode == "single_tx" && cfg.Validation != "none" && !cfg.SchemaOnly {
+		snapshot = &sourceSnapshot{}
+		defer snapshot.Close()
 	}
 
 	if !cfg.SchemaOnly {
@@ -314,6 +368,7 @@ func runMigrationWithConfig(cfg *MigrationConfig) error {
 					Resume:              cfg.Resume,
 					ConfigDir:           cfg.configDir,
 					ResumeCompatibility: resumeCompatibility,
+					Snapshot:            snapshot,
 				})
 			},
 			func() error {
@@ -323,19 +378,49 @@ func runMigrationWithConfig(cfg *MigrationConfig) error {
 		if err != nil {
 			return err
 		}
+
+		if countTranslatedGeneratedColumns(schema) > 0 {
+			log.Printf("verifying generated columns against source values...")
+			problems, err := verifyGeneratedColumnsAfterLoad(ctx, src, cfg, pgPool, schema, typeMap, snapshot)
+			if err != nil {
+				return fmt.Errorf("verify generated columns: %w", err)
+			}
+			if len(problems) > 0 {
+				log.Printf("WARN: %d sampled generated column value(s) differ from the source; check the translated expressions:", len(problems))
+				for _, p := range problems {
+					log.Printf("  %s", p)
+				}
+			}
+		}
 	}
 
 	// Validation
 	if cfg.Validation != "none" && !cfg.SchemaOnly {
-		if cfg.SourceSnapshotMode == "single_tx" {
-			log.Printf("WARN: validation with source_snapshot_mode=single_tx compares against current source state, not the snapshot; results may be inaccurate if the source was modified during migration")
+		if snapshot.Tx() != nil {
+			log.Printf("running post-load validation (mode=%s) inside the source snapshot (sequential)...", cfg.Validation)
+		} else {
+			log.Printf("running post-load validation (mode=%s)...", cfg.Validation)
 		}
-		log.Printf("running post-load validation (mode=%s)...", cfg.Validation)
-		if _, err := validateMigration(ctx, src, cfg.Source.DSN, pgPool, schema, cfg.Schema, cfg.Validation, cfg.Workers); err != nil {
+		if _, err := validateMigration(ctx, validationConfig{
+			Src:            src,
+			SrcDSN:         cfg.Source.DSN,
+			Pool:           pgPool,
+			Schema:         schema,
+			PGSchema:       cfg.Schema,
+			Mode:           cfg.Validation,
+			Workers:        cfg.Workers,
+			TypeMap:        typeMap,
+			ChunkSize:      cfg.ChunkSize,
+			SampleSize:     cfg.ValidationSampleSize,
+			SourceSnapshot: snapshot.Tx(),
+		}); err != nil {
 			return fmt.Errorf("validation: %w", err)
 		}
 		log.Printf("validation passed")
 	}
+	if err := snapshot.Close(); err != nil {
+		log.Printf("WARN: %v", err)
+	}
 
 	// 9. Post-migration: SET LOGGED, PKs, indexes, hooks, FKs, sequences, triggers
 	log.Printf("running post-migration steps...")
@@ -344,6 +429,12 @@ func runMigrationWithConfig(cfg *MigrationConfig) error {
 	}
 
 	log.Printf("migration completed in %s", time.Since(start).Round(time.Millisecond))
+	if warnings := failedViewWarnings(schema.Views); len(warnings) > 0 {
+		log.Printf("WARN: %d view(s) could not be created; recreate them in an after_all hook:", len(warnings))
+		for _, w := range warnings {
+			log.Printf("  %s", w)
+		}
+	}
 	return nil
 }
 
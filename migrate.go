@@ -29,6 +29,43 @@ type migrateDataConfig struct {
 	// ResumeCompatibility is used only when Resume=true to validate that an
 	// existing checkpoint still matches the current migration shape.
 	ResumeCompatibility checkpointCompatibility
+	// Snapshot, when set in single_tx mode, receives the still-open source
+	// transaction after a successful copy instead of it being committed, so
+	// post-load validation can read the same snapshot. The caller must Close it.
+	Snapshot *sourceSnapshot
+}
+
+// sourceSnapshot holds the single_tx source transaction open past the data
+// copy. Close is safe to call more than once.
+type sourceSnapshot struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+// Tx returns the open snapshot transaction, or nil if none was handed over.
+func (s *sourceSnapshot) Tx() *sql.Tx {
+	if s == nil {
+		return nil
+	}
+	return s.tx
+}
+
+// Close ends the read-only snapshot transaction and its connection.
+func (s *sourceSnapshot) Close() error {
+	if s == nil || s.db == nil {
+		return nil
+	}
+	var err error
+	if s.tx != nil {
+		if rbErr := s.tx.Rollback(); rbErr != nil && rbErr != sql.ErrTxDone {
+			err = fmt.Errorf("end source snapshot: %w", rbErr)
+		}
+	}
+	if closeErr := s.db.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	s.db, s.tx = nil, nil
+	return err
 }
 
 // migrateData streams data from the source to PostgreSQL for all tables using parallel workers.
@@ -151,7 +188,13 @@ func migrateDataSingleTx(ctx context.Context, cfg migrateDataConfig) error {
 	if err != nil {
 		return err
 	}
-	defer srcDB.Close()
+	// handedOff is set once the open snapshot belongs to cfg.Snapshot.
+	handedOff := false
+	defer func() {
+		if !handedOff {
+			srcDB.Close()
+		}
+	}()
 	srcDB.SetMaxOpenConns(1)
 	srcDB.SetMaxIdleConns(1)
 
@@ -177,7 +220,11 @@ func migrateDataSingleTx(ctx context.Context, cfg migrateDataConfig) error {
 	if err != nil {
 		return fmt.Errorf("begin source transaction: %w", err)
 	}
-	defer tx.Rollback()
+	defer func() {
+		if !handedOff {
+			tx.Rollback()
+		}
+	}()
 
 	// Create checkpoint manager: noop when resume is disabled.
 	cpPath := checkpointPath(cfg.ConfigDir)
@@ -245,7 +292,10 @@ func migrateDataSingleTx(ctx context.Context, cfg migrateDataConfig) error {
 		}
 	}
 
-	if err := tx.Commit(); err != nil {
+	if cfg.Snapshot != nil {
+		cfg.Snapshot.db, cfg.Snapshot.tx = srcDB, tx
+		handedOff = true
+	} else if err := tx.Commit(); err != nil {
 		return fmt.Errorf("commit source transaction: %w", err)
 	}
 
@@ -275,11 +325,17 @@ type dbQuerier interface {
 	QueryContext(context.Context, string, ...any) (*sql.Rows, error)
 }
 
-func migrateTableFromSourceFull(ctx context.Context, src SourceDB, source dbQuerier, pool *pgxpool.Pool, table Table, pgSchema string, typeMap TypeMappingConfig) (int64, error) {
+// pgCopier receives COPY rows: the pool, or a transaction whose other
+// statements must commit or roll back together with the copy.
+type pgCopier interface {
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+}
+
+func migrateTableFromSourceFull(ctx context.Context, src SourceDB, source dbQuerier, target pgCopier, table Table, pgSchema string, typeMap TypeMappingConfig) (int64, error) {
 	log.Printf("  [%s] starting row copy", table.SourceName)
 
 	query := buildSourceSelectQuery(src, table, typeMap)
-	count, err := copyFromSource(ctx, source, pool, table, pgSchema, typeMap, src, query)
+	count, err := copyFromSource(ctx, source, target, table, pgSchema, typeMap, src, query)
 	if err != nil {
 		return 0, err
 	}
@@ -302,11 +358,11 @@ func migrateChunk(ctx context.Context, src SourceDB, srcDSN string, pool *pgxpoo
 }
 
 // migrateChunkFromSource copies a single chunk using an existing source querier.
-func migrateChunkFromSource(ctx context.Context, src SourceDB, source dbQuerier, pool *pgxpool.Pool, table Table, pgSchema string, typeMap TypeMappingConfig, key ChunkKey, chunk Chunk) (int64, error) {
+func migrateChunkFromSource(ctx context.Context, src SourceDB, source dbQuerier, target pgCopier, table Table, pgSchema string, typeMap TypeMappingConfig, key ChunkKey, chunk Chunk) (int64, error) {
 	log.Printf("  [%s] chunk %d starting", table.SourceName, chunk.Index)
 
 	query := buildChunkedSelectQuery(src, table, key, chunk, typeMap)
-	count, err := copyFromSource(ctx, source, pool, table, pgSchema, typeMap, src, query)
+	count, err := copyFromSource(ctx, source, target, table, pgSchema, typeMap, src, query)
 	if err != nil {
 		return 0, err
 	}
@@ -316,17 +372,23 @@ func migrateChunkFromSource(ctx context.Context, src SourceDB, source dbQuerier,
 }
 
 // copyFromSource runs a SELECT query on the source and streams results into PG via COPY.
-func copyFromSource(ctx context.Context, source dbQuerier, pool *pgxpool.Pool, table Table, pgSchema string, typeMap TypeMappingConfig, src SourceDB, query string) (int64, error) {
-	pgColumns := make([]string, len(table.Columns))
-	for i, col := range table.Columns {
+func copyFromSource(ctx context.Context, source dbQuerier, target pgCopier, table Table, pgSchema string, typeMap TypeMappingConfig, src SourceDB, query string) (int64, error) {
+	columns := copyColumns(table)
+	pgColumns := make([]string, len(columns))
+	for i, col := range columns {
 		pgColumns[i] = col.PGName
 	}
 
-	conn, err := pool.Acquire(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("acquire pg conn: %w", err)
+	// Hold a pooled connection before starting the source query, as the
+	// copy would otherwise wait for one with the source cursor open.
+	if pool, ok := target.(*pgxpool.Pool); ok {
+		conn, err := pool.Acquire(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("acquire pg conn: %w", err)
+		}
+		defer conn.Release()
+		target = conn.Conn()
 	}
-	defer conn.Release()
 
 	rows, err := source.QueryContext(ctx, query)
 	if err != nil {
@@ -336,7 +398,7 @@ func copyFromSource(ctx context.Context, source dbQuerier, pool *pgxpool.Pool, t
 
 	rs := newRowSource(rows, table, src, typeMap)
 
-	count, err := conn.Conn().CopyFrom(
+	count, err := target.CopyFrom(
 		ctx,
 		pgx.Identifier{pgSchema, table.PGName},
 		pgColumns,
@@ -412,7 +474,7 @@ func buildChunkPlans(ctx context.Context, src SourceDB, srcDSN string, schema *S
 // rowSource implements pgx.CopyFromSource by reading from source rows.
 type rowSource struct {
 	rows        *sql.Rows
-	table       Table
+	columns     []Column
 	scanDest    []any
 	scanPtrs    []any
 	values      []any
@@ -425,7 +487,8 @@ type rowSource struct {
 }
 
 func newRowSource(rows *sql.Rows, table Table, src SourceDB, typeMap TypeMappingConfig) *rowSource {
-	numCols := len(table.Columns)
+	columns := copyColumns(table)
+	numCols := len(columns)
 	scanDest := make([]any, numCols)
 	scanPtrs := make([]any, numCols)
 	for i := range scanDest {
@@ -434,7 +497,7 @@ func newRowSource(rows *sql.Rows, table Table, src SourceDB, typeMap TypeMapping
 
 	return &rowSource{
 		rows:        rows,
-		table:       table,
+		columns:     columns,
 		scanDest:    scanDest,
 		scanPtrs:    scanPtrs,
 		values:      make([]any, numCols),
@@ -456,7 +519,7 @@ func (r *rowSource) Next() bool {
 		return false
 	}
 
-	for i, col := range r.table.Columns {
+	for i, col := range r.columns {
 		v, err := r.src.TransformValue(r.scanDest[i], col, r.typeMapping)
 		if err != nil {
 			r.err = fmt.Errorf("column %s: %w", col.SourceName, err)
@@ -482,8 +545,9 @@ func (r *rowSource) Err() error {
 }
 
 func buildSourceSelectQuery(src SourceDB, table Table, typeMap TypeMappingConfig) string {
-	cols := make([]string, len(table.Columns))
-	for i, col := range table.Columns {
+	columns := copyColumns(table)
+	cols := make([]string, len(columns))
+	for i, col := range columns {
 		cols[i] = columnSelectExpr(src, col, typeMap)
 	}
 	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), src.SourceTableRef(table))
@@ -518,14 +582,19 @@ func columnSelectExpr(src SourceDB, col Column, typeMap TypeMappingConfig) strin
 }
 
 func mysqlPostGISSelectExpr(src SourceDB, quoted string) string {
-	mysqlSrc := src.(*mysqlSourceDB)
-	wkbExpr := fmt.Sprintf("ST_AsWKB(%s)", quoted)
-	if mysqlSrc.supportsAxisOrderOption() {
-		wkbExpr = fmt.Sprintf("ST_AsWKB(%s, 'axis-order=long-lat')", quoted)
-	}
+	wkbExpr := mysqlPostGISWKBExpr(src, quoted)
 	sridExpr := fmt.Sprintf("ST_SRID(%s)", quoted)
 	return fmt.Sprintf(
 		"CONCAT(CHAR((%[1]s) & 255 USING binary), CHAR(((%[1]s) >> 8) & 255 USING binary), CHAR(((%[1]s) >> 16) & 255 USING binary), CHAR(((%[1]s) >> 24) & 255 USING binary), %[2]s) AS %[3]s",
 		sridExpr, wkbExpr, quoted,
 	)
 }
+
+// mysqlPostGISWKBExpr selects a MySQL geometry as WKB in the longitude-latitude
+// axis order PostGIS expects, when the server supports choosing it.
+func mysqlPostGISWKBExpr(src SourceDB, quoted string) string {
+	if src.(*mysqlSourceDB).supportsAxisOrderOption() {
+		return fmt.Sprintf("ST_AsWKB(%s, 'axis-order=long-lat')", quoted)
+	}
+	return fmt.Sprintf("ST_AsWKB(%s)", quoted)
+}
@@ -1,6 +1,10 @@
 package main
 
-import "testing"
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
 
 func TestNewRowSourcePreallocatesBuffers(t *testing.T) {
 	table := Table{
@@ -130,3 +134,39 @@ func TestBuildSourceSelectQuery_MySQLPostGISLegacyExport(t *testing.T) {
 		t.Fatalf("buildSourceSelectQuery() = %q, want %q", got, want)
 	}
 }
+
+func TestSourceSnapshotClose(t *testing.T) {
+	var nilSnapshot *sourceSnapshot
+	if nilSnapshot.Tx() != nil {
+		t.Fatal("nil snapshot should have no transaction")
+	}
+	if err := nilSnapshot.Close(); err != nil {
+		t.Fatalf("Close() on nil snapshot: %v", err)
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "snap.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("begin: %v", err)
+	}
+
+	snap := &sourceSnapshot{db: db, tx: tx}
+	if snap.Tx() != tx {
+		t.Fatal("Tx() should return the handed-over transaction")
+	}
+	if err := snap.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+	if err := snap.Close(); err != nil {
+		t.Fatalf("second Close(): %v", err)
+	}
+	if snap.Tx() != nil {
+		t.Error("Tx() should be nil after Close")
+	}
+	if err := db.Ping(); err == nil {
+		t.Error("source connection should be closed after Close")
+	}
+}
@@ -2,20 +2,23 @@ package main
 
 // Column represents a single column from the source database.
 type Column struct {
-	SourceName string
-	PGName     string
-	DataType   string // e.g. "binary", "int", "varchar"
-	ColumnType string // full type e.g. "tinyint(1)", "enum('a','b')"
-	CharMaxLen int64
-	Precision  int64
-	Scale      int64
-	Nullable   bool
-	Default    *string
+	SourceName           string
+	PGName               string
+	DataType             string // e.g. "binary", "int", "varchar"
+	ColumnType           string // full type e.g. "tinyint(1)", "enum('a','b')"
+	CharMaxLen           int64
+	Precision            int64
+	Scale                int64
+	Nullable             bool
+	Default              *string
 	Extra                string // e.g. "auto_increment", "on update CURRENT_TIMESTAMP"
 	GenerationExpression string // actual expression for generated columns (MySQL GENERATION_EXPRESSION)
-	OrdinalPos int
-	Charset    string // e.g. "utf8mb4" — MySQL only, zero-value for SQLite
-	Collation  string // e.g. "utf8mb4_general_ci" — MySQL only, zero-value for SQLite
+	PGGeneration         string // translated PostgreSQL expression; empty when the column is copied as data
+	OrdinalPos           int
+	Charset              string // e.g. "utf8mb4" — MySQL only, zero-value for SQLite
+	Collation            string // e.g. "utf8mb4_general_ci" — MySQL only, zero-value for SQLite
+	Comment              string // column documentation, empty when none
+	DefaultSequence      string // PG name of the sequence behind a NEXT VALUE FOR default (MSSQL)
 }
 
 // Index represents a source database index (may span multiple columns).
@@ -26,9 +29,18 @@ type Index struct {
 	ColumnOrders  []string // ASC/DESC order per column
 	Unique        bool
 	IsPrimary     bool
-	Type          string // BTREE, FULLTEXT, SPATIAL, HASH
-	HasPrefix     bool   // MySQL prefix index (SUB_PART)
-	HasExpression bool   // expression/key-part index not representable as plain column list
+	Type          string   // BTREE, FULLTEXT, SPATIAL, HASH
+	HasPrefix     bool     // MySQL prefix index (SUB_PART)
+	PrefixLengths []int    // SUB_PART per column, 0 when the whole column is indexed
+	HasExpression bool     // expression/key-part index not representable as plain column list
+	Expressions   []string // source expression per key-part, "" for column key-parts
+	PGExpressions []string // translated Expressions, set when every expression key-part translates
+	Include       []string // PG names of non-key INCLUDE columns (MSSQL included columns)
+	Filter        string   // source WHERE predicate of a filtered index
+	PGFilter      string   // translated Filter, set when the predicate translates
+	// NullsNotDistinct marks a unique index that must reject duplicate NULLs,
+	// as SQL Server unique indexes do on nullable columns.
+	NullsNotDistinct bool
 }
 
 // ForeignKey represents a source database foreign key constraint.
@@ -42,17 +54,86 @@ type ForeignKey struct {
 	DeleteRule string   // CASCADE, SET NULL, etc.
 }
 
+// CheckConstraint represents a source database CHECK constraint.
+type CheckConstraint struct {
+	Name       string // PG constraint name
+	SourceName string
+	Expression string // source SQL expression, untranslated
+}
+
 // Table holds the full introspected definition of a source database table.
 type Table struct {
+	SourceName       string
+	PGName           string
+	Columns          []Column
+	PrimaryKey       *Index
+	Indexes          []Index // non-primary indexes
+	ForeignKeys      []ForeignKey
+	CheckConstraints []CheckConstraint
+	Comment          string // table documentation, empty when none
+	// AutoIncrementNext is the next value the source auto-increment/IDENTITY
+	// counter hands out, 0 when unknown. It can exceed MAX(col)+1 after
+	// deletes.
+	AutoIncrementNext int64
+	// AutoIncrementStep is the MSSQL identity increment, 0 when unknown or
+	// not per-table (treated as 1).
+	AutoIncrementStep int64
+	Partitioning      *Partitioning // nil for unpartitioned tables
+}
+
+// Partitioning describes how a source table is partitioned.
+type Partitioning struct {
+	Method     string      // RANGE, LIST or HASH (MySQL KEY partitioning is HASH)
+	Expression string      // source partitioning expression, e.g. "year(`created_at`)"
+	Columns    []string    // PG column names when the key is a plain column list
+	Partitions []Partition // in source ordinal order
+	PGKey      string      // translated PARTITION BY key list; empty when the table is created unpartitioned
+	Reason     string      // why PGKey is empty
+	// ExtendedKeys names the primary key ("PRIMARY KEY") and unique indexes
+	// that a [[partitioning]] entry widened with the partition key, so they no
+	// longer enforce uniqueness of their original columns alone.
+	ExtendedKeys []string
+	// DroppedForeignKeys lists, as "table.constraint", the foreign keys that
+	// reference this table by a key lacking the partition column and are
+	// therefore not created.
+	DroppedForeignKeys []string
+}
+
+// Partition is one partition of a partitioned table.
+type Partition struct {
+	Name        string // PG table name
 	SourceName  string
-	PGName      string
-	Columns     []Column
-	PrimaryKey  *Index
-	Indexes     []Index // non-primary indexes
-	ForeignKeys []ForeignKey
+	Description string // range upper bound(s) or list values, as the source reports them
+	PGBound     string // FOR VALUES ... or DEFAULT, set with PGKey
+}
+
+// View is a source view with its best-effort PostgreSQL translation.
+type View struct {
+	SourceName string
+	PGName     string
+	Definition string // source SQL as introspected
+	SQL        string // CREATE VIEW statement; empty when translation failed
+	Reason     string // why SQL is empty
+	// CreateError is set when SQL failed to create the view on the target.
+	CreateError string
 }
 
 // Schema holds all introspected tables for a source database.
 type Schema struct {
-	Tables []Table
+	Tables    []Table
+	Views     []View
+	Sequences []Sequence
+}
+
+// Sequence is a standalone source sequence object (MSSQL CREATE SEQUENCE).
+type Sequence struct {
+	SourceName string
+	PGName     string
+	DataType   string // source type: tinyint, smallint, int, bigint, decimal, numeric
+	Start      int64
+	Increment  int64
+	MinValue   *int64 // nil when outside the bigint range
+	MaxValue   *int64 // nil when outside the bigint range
+	Cycle      bool
+	LastValue  *int64 // last value handed out, nil when never used
 }
@@ -0,0 +1,586 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// maxGeneratedPartitions bounds how many range partitions an interval may
+// generate for one table.
+const maxGeneratedPartitions = 1000
+
+// translatePartitioning records the PostgreSQL partition key and partition
+// bounds of every partitioned source table whose scheme translates. The
+// others keep PGKey empty with Reason set and are created as plain tables.
+func translatePartitioning(schema *Schema, src SourceDB, typeMap TypeMappingConfig) {
+	if schema == nil {
+		return
+	}
+	for i := range schema.Tables {
+		t := &schema.Tables[i]
+		p := t.Partitioning
+		if p == nil || p.Reason != "" || p.PGKey != "" {
+			continue
+		}
+		key, bounds, err := translatePartitionScheme(*t, src, typeMap)
+		if err != nil {
+			p.Reason = err.Error()
+			continue
+		}
+		p.PGKey = key
+		for j := range p.Partitions {
+			p.Partitions[j].PGBound = bounds[j]
+		}
+	}
+}
+
+// translatePartitionScheme returns the PARTITION BY key list of t and the
+// FOR VALUES clause (or DEFAULT) of each of its partitions.
+func translatePartitionScheme(t Table, src SourceDB, typeMap TypeMappingConfig) (string, []string, error) {
+	p := t.Partitioning
+	if len(p.Partitions) == 0 {
+		return "", nil, fmt.Errorf("table has no partitions")
+	}
+
+	var key string
+	keyCount := len(p.Columns)
+	if keyCount > 0 {
+		for _, name := range p.Columns {
+			if _, ok := findColumnByPGName(t, name); !ok {
+				return "", nil, fmt.Errorf("partition column %s not found", name)
+			}
+		}
+		key = quotedColumnList(p.Columns)
+	} else {
+		// PostgreSQL cannot enforce a primary key or unique index on a table
+		// partitioned by an expression, while MySQL can.
+		if t.PrimaryKey != nil || tableHasUniqueIndex(t) {
+			return "", nil, fmt.Errorf("partition expression %s: PostgreSQL cannot enforce primary keys or unique indexes on tables partitioned by an expression", p.Expression)
+		}
+		if dialect := checkDialect(src); dialect != "mysql" {
+			return "", nil, fmt.Errorf("%s partition expressions are not translated", src.Name())
+		}
+		parser, err := newGenParser(t, p.Expression, src, typeMap)
+		if err != nil {
+			return "", nil, fmt.Errorf("partition expression %s: %w", p.Expression, err)
+		}
+		expr, err := parser.parseAll()
+		if err != nil {
+			return "", nil, fmt.Errorf("partition expression %s: %w", p.Expression, err)
+		}
+		key = "(" + expr.sql + ")"
+		keyCount = 1
+	}
+
+	bounds := make([]string, len(p.Partitions))
+	switch p.Method {
+	case "RANGE":
+		lower := strings.TrimSuffix(strings.Repeat("MINVALUE, ", keyCount), ", ")
+		for i, part := range p.Partitions {
+			values, err := partitionBoundValues(part.Description)
+			if err != nil {
+				return "", nil, fmt.Errorf("partition %s: %w", part.SourceName, err)
+			}
+			if len(values) != keyCount {
+				return "", nil, fmt.Errorf("partition %s: bound %s has %d values, want %d", part.SourceName, part.Description, len(values), keyCount)
+			}
+			upper := strings.Join(values, ", ")
+			if upper == strings.TrimSuffix(strings.Repeat("MAXVALUE, ", keyCount), ", ") {
+				// The catch-all partition also receives NULL keys, which
+				// MySQL stores in the first range partition and PostgreSQL
+				// only routes to a DEFAULT partition.
+				if i != len(p.Partitions)-1 {
+					return "", nil, fmt.Errorf("partition %s: MAXVALUE partition is not the last one", part.SourceName)
+				}
+				bounds[i] = "DEFAULT"
+				continue
+			}
+			bounds[i] = fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", lower, upper)
+			lower = upper
+		}
+	case "LIST":
+		if keyCount != 1 {
+			return "", nil, fmt.Errorf("multi-column LIST COLUMNS partitioning is not supported")
+		}
+		for i, part := range p.Partitions {
+			values, err := partitionBoundValues(part.Description)
+			if err != nil {
+				return "", nil, fmt.Errorf("partition %s: %w", part.SourceName, err)
+			}
+			for _, v := range values {
+				if v == "MAXVALUE" {
+					return "", nil, fmt.Errorf("partition %s: MAXVALUE in a list bound", part.SourceName)
+				}
+			}
+			bounds[i] = fmt.Sprintf("FOR VALUES IN (%s)", strings.Join(values, ", "))
+		}
+	case "HASH":
+		// MySQL assigns rows with its own hash function; PostgreSQL
+		// redistributes them over the same number of partitions.
+		for i := range p.Partitions {
+			bounds[i] = fmt.Sprintf("FOR VALUES WITH (MODULUS %d, REMAINDER %d)", len(p.Partitions), i)
+		}
+	default:
+		return "", nil, fmt.Errorf("partitioning method %q is not supported", p.Method)
+	}
+	return key, bounds, nil
+}
+
+func tableHasUniqueIndex(t Table) bool {
+	for _, idx := range t.Indexes {
+		if idx.Unique {
+			return true
+		}
+	}
+	return false
+}
+
+// partitionBoundValues parses a MySQL PARTITION_DESCRIPTION (a comma-separated
+// list of literals or MAXVALUE) into PostgreSQL literals.
+func partitionBoundValues(desc string) ([]string, error) {
+	tokens, err := tokenizeCheckExpression(desc, "mysql")
+	if err != nil {
+		return nil, fmt.Errorf("bound %s: %w", desc, err)
+	}
+	var values []string
+	negative := false
+	expectValue := true
+	for _, tok := range tokens {
+		if !expectValue {
+			if tok.kind != checkTokenComma {
+				return nil, fmt.Errorf("bound %s: unexpected %q", desc, tok.text)
+			}
+			expectValue = true
+			continue
+		}
+		switch {
+		case tok.kind == checkTokenOp && tok.text == "-" && !negative:
+			negative = true
+			continue
+		case tok.kind == checkTokenNumber:
+			v := tok.text
+			if negative {
+				v = "-" + v
+			}
+			values = append(values, v)
+		case negative:
+			return nil, fmt.Errorf("bound %s: unexpected %q", desc, tok.text)
+		case tok.kind == checkTokenString:
+			values = append(values, pgLiteral(tok.text))
+		case tok.kind == checkTokenWord && (strings.EqualFold(tok.text, "MAXVALUE") || strings.EqualFold(tok.text, "NULL")):
+			values = append(values, strings.ToUpper(tok.text))
+		default:
+			return nil, fmt.Errorf("bound %s: unsupported value %q", desc, tok.text)
+		}
+		negative = false
+		expectValue = false
+	}
+	if expectValue {
+		return nil, fmt.Errorf("bound %q is incomplete", desc)
+	}
+	return values, nil
+}
+
+// isPartitionedTable reports whether t is created as a PostgreSQL partitioned
+// table.
+func isPartitionedTable(t Table) bool {
+	return t.Partitioning != nil && t.Partitioning.PGKey != ""
+}
+
+// generateCreatePartitions produces the CREATE TABLE ... PARTITION OF
+// statements for the partitions of t.
+func generateCreatePartitions(t Table, pgSchema string, unlogged bool) []string {
+	if !isPartitionedTable(t) {
+		return nil
+	}
+	tableKind := "TABLE"
+	if unlogged {
+		tableKind = "UNLOGGED TABLE"
+	}
+	stmts := make([]string, len(t.Partitioning.Partitions))
+	for i, part := range t.Partitioning.Partitions {
+		stmts[i] = fmt.Sprintf("CREATE %s %s PARTITION OF %s %s",
+			tableKind, pgQualifiedIdent(pgSchema, part.Name), pgQualifiedIdent(pgSchema, t.PGName), part.PGBound)
+	}
+	return stmts
+}
+
+// withPartitionKeyColumns returns cols extended with the partition columns of
+// t it lacks. PostgreSQL only accepts primary keys and unique indexes on a
+// partitioned table when they include every partition column.
+func withPartitionKeyColumns(t Table, cols []string) []string {
+	if !isPartitionedTable(t) {
+		return cols
+	}
+	out := cols
+	for _, key := range t.Partitioning.Columns {
+		found := false
+		for _, c := range cols {
+			if c == key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			out = append(append([]string(nil), out...), key)
+		}
+	}
+	return out
+}
+
+func isPartitionInterval(interval string) bool {
+	switch interval {
+	case "day", "week", "month", "year":
+		return true
+	}
+	n, err := strconv.ParseInt(interval, 10, 64)
+	return err == nil && n > 0
+}
+
+// applyPartitioningConfig replaces the partitioning of every table named in a
+// [[partitioning]] entry. Range partitions generated from an interval cover
+// the source MIN..MAX of the key; range and list tables also get a DEFAULT
+// partition for rows outside the configured bounds and NULL keys. Foreign keys
+// referencing a table whose primary key or unique indexes gain the partition
+// key can no longer be created and are dropped with a warning; both are
+// recorded on the Partitioning for the plan report. A nil source skips the
+// MIN..MAX scan and leaves interval tables with only their DEFAULT partition;
+// validate and repair pass nil and read the partitions from the target with
+// loadTargetPartitions instead.
+func applyPartitioningConfig(ctx context.Context, source dbQuerier, src SourceDB, schema *Schema, configs []PartitioningConfig, typeMap TypeMappingConfig) error {
+	for _, pc := range configs {
+		ti := -1
+		for i, t := range schema.Tables {
+			if t.SourceName == pc.Table || t.PGName == pc.Table {
+				ti = i
+				break
+			}
+		}
+		if ti < 0 {
+			return fmt.Errorf("partitioning: table %q not found in source schema", pc.Table)
+		}
+		t := &schema.Tables[ti]
+		p, err := buildConfiguredPartitioning(ctx, source, src, *t, pc, typeMap)
+		if err != nil {
+			return fmt.Errorf("partitioning %s: %w", pc.Table, err)
+		}
+		t.Partitioning = p
+		log.Printf("  partitioning %s by %s (%s) into %d partitions", t.PGName, p.Method, p.PGKey, len(p.Partitions))
+
+		key := p.Columns[0]
+		if t.PrimaryKey != nil && len(withPartitionKeyColumns(*t, t.PrimaryKey.Columns)) != len(t.PrimaryKey.Columns) {
+			log.Printf("  WARN: primary key of %s is extended with partition key %s", t.PGName, key)
+			p.ExtendedKeys = append(p.ExtendedKeys, "PRIMARY KEY")
+		}
+		for _, idx := range t.Indexes {
+			if idx.Unique && len(withPartitionKeyColumns(*t, idx.Columns)) != len(idx.Columns) {
+				log.Printf("  WARN: unique index %s on %s is extended with partition key %s", idx.Name, t.PGName, key)
+				p.ExtendedKeys = append(p.ExtendedKeys, idx.Name)
+			}
+		}
+		for i := range schema.Tables {
+			child := &schema.Tables[i]
+			kept := child.ForeignKeys[:0]
+			for _, fk := range child.ForeignKeys {
+				if fk.RefPGTable == t.PGName && len(withPartitionKeyColumns(*t, fk.RefColumns)) != len(fk.RefColumns) {
+					log.Printf("  WARN: skipping foreign key %s on %s: %s is partitioned by %s, which the referenced key lacks",
+						fk.Name, child.PGName, t.PGName, key)
+					p.DroppedForeignKeys = append(p.DroppedForeignKeys, child.PGName+"."+fk.Name)
+					continue
+				}
+				kept = append(kept, fk)
+			}
+			child.ForeignKeys = kept
+		}
+	}
+	return nil
+}
+
+func buildConfiguredPartitioning(ctx context.Context, source dbQuerier, src SourceDB, t Table, pc PartitioningConfig, typeMap TypeMappingConfig) (*Partitioning, error) {
+	var col Column
+	found := false
+	for _, c := range t.Columns {
+		if c.SourceName == pc.Key || c.PGName == pc.Key {
+			col, found = c, true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("key column %q not found", pc.Key)
+	}
+	if col.PGGeneration != "" {
+		return nil, fmt.Errorf("key column %s is a generated column", col.PGName)
+	}
+
+	p := &Partitioning{
+		Method:     strings.ToUpper(pc.Method),
+		Expression: col.SourceName,
+		Columns:    []string{col.PGName},
+		PGKey:      pgIdent(col.PGName),
+	}
+	addPartition := func(suffix, bound string) {
+		p.Partitions = append(p.Partitions, Partition{
+			Name:    truncateGeneratedIdentifier(t.PGName + "_" + suffix),
+			PGBound: bound,
+		})
+	}
+
+	switch pc.Method {
+	case "hash":
+		for i := 0; i < pc.Partitions; i++ {
+			addPartition(fmt.Sprintf("p%d", i), fmt.Sprintf("FOR VALUES WITH (MODULUS %d, REMAINDER %d)", pc.Partitions, i))
+		}
+		return p, nil
+	case "list":
+		for _, l := range pc.Lists {
+			values := make([]string, len(l.Values))
+			for i, v := range l.Values {
+				values[i] = pgLiteral(v)
+			}
+			addPartition(partitionNameSuffix(l.Name), fmt.Sprintf("FOR VALUES IN (%s)", strings.Join(values, ", ")))
+		}
+		addPartition("default", "DEFAULT")
+		return p, nil
+	}
+
+	bounds := make([]string, len(pc.Bounds))
+	names := make([]string, len(pc.Bounds))
+	for i, b := range pc.Bounds {
+		bounds[i] = pgLiteral(b)
+		names[i] = "p" + partitionNameSuffix(b)
+	}
+	if pc.Interval != "" && source != nil {
+		var err error
+		bounds, names, err = generateRangeBounds(ctx, source, src, t, col, pc.Interval, typeMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i := 0; i+1 < len(bounds); i++ {
+		addPartition(names[i], fmt.Sprintf("FOR VALUES FROM (%s) TO (%s)", bounds[i], bounds[i+1]))
+	}
+	addPartition("default", "DEFAULT")
+	return p, nil
+}
+
+// loadTargetPartitions replaces the partitions of every partitioned table in
+// schema with those attached to it in pgSchema, so checks after the migration
+// compare against the bounds it created rather than ones recomputed from the
+// current source data. Tables without partitions on the target keep theirs.
+func loadTargetPartitions(ctx context.Context, pool *pgxpool.Pool, pgSchema string, schema *Schema) error {
+	rows, err := pool.Query(ctx, `
+		SELECT p.relname, c.relname, pg_get_expr(c.relpartbound, c.oid)
+		FROM pg_inherits i
+		JOIN pg_class p ON p.oid = i.inhparent
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_namespace n ON n.oid = p.relnamespace
+		WHERE n.nspname = $1 AND p.relkind = 'p'
+		ORDER BY p.relname, c.relname`, pgSchema)
+	if err != nil {
+		return fmt.Errorf("query target partitions: %w", err)
+	}
+	defer rows.Close()
+
+	target := make(map[string][]Partition)
+	for rows.Next() {
+		var parent string
+		var part Partition
+		if err := rows.Scan(&parent, &part.Name, &part.PGBound); err != nil {
+			return fmt.Errorf("scan target partitions: %w", err)
+		}
+		target[parent] = append(target[parent], part)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate target partitions: %w", err)
+	}
+
+	for i := range schema.Tables {
+		t := &schema.Tables[i]
+		parts := target[t.PGName]
+		if !isPartitionedTable(*t) || len(parts) == 0 {
+			continue
+		}
+		known := make(map[string]Partition, len(t.Partitioning.Partitions))
+		for _, part := range t.Partitioning.Partitions {
+			known[part.Name] = part
+		}
+		for j, part := range parts {
+			if k, ok := known[part.Name]; ok {
+				parts[j].SourceName, parts[j].Description = k.SourceName, k.Description
+			}
+		}
+		t.Partitioning.Partitions = parts
+	}
+	return nil
+}
+
+var partitionNameUnsafe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// partitionNameSuffix turns a partition name or bound value into an
+// identifier-safe suffix, e.g. "2024-01-01" to "2024_01_01".
+func partitionNameSuffix(v string) string {
+	return strings.Trim(partitionNameUnsafe.ReplaceAllString(strings.ToLower(v), "_"), "_")
+}
+
+// generateRangeBounds returns the ascending boundaries (as PostgreSQL
+// literals) and partition names of the interval partitions covering the
+// source MIN..MAX of col. An empty table yields no boundaries.
+func generateRangeBounds(ctx context.Context, source dbQuerier, src SourceDB, t Table, col Column, interval string, typeMap TypeMappingConfig) ([]string, []string, error) {
+	pgType, err := src.MapType(col, typeMap)
+	if err != nil {
+		return nil, nil, fmt.Errorf("key column %s: %w", col.PGName, err)
+	}
+	key := ChunkKey{SourceColumn: col.SourceName, PGColumn: col.PGName}
+
+	var bounds, names []string
+	switch pgType {
+	case "smallint", "integer", "bigint":
+		step, err := strconv.ParseInt(interval, 10, 64)
+		if err != nil {
+			return nil, nil, fmt.Errorf("interval %q needs a date or timestamp key, %s is %s", interval, col.PGName, pgType)
+		}
+		minVal, maxVal, ok, err := queryMinMax(ctx, source, src, t, key)
+		if err != nil || !ok {
+			return nil, nil, err
+		}
+		lower := minVal / step * step
+		if minVal < 0 && minVal%step != 0 {
+			lower -= step
+		}
+		for v := lower; ; v += step {
+			if len(bounds) > maxGeneratedPartitions {
+				return nil, nil, fmt.Errorf("interval %s yields more than %d partitions; use a larger interval", interval, maxGeneratedPartitions)
+			}
+			bounds = append(bounds, strconv.FormatInt(v, 10))
+			names = append(names, "p"+strings.ReplaceAll(strconv.FormatInt(v, 10), "-", "m"))
+			if v > maxVal {
+				break
+			}
+		}
+	case "date", "timestamp", "timestamptz":
+		if _, err := strconv.ParseInt(interval, 10, 64); err == nil {
+			return nil, nil, fmt.Errorf("interval %q needs an integer key, %s is %s", interval, col.PGName, pgType)
+		}
+		minVal, maxVal, ok, err := queryMinMaxTime(ctx, source, src, t, key)
+		if err != nil || !ok {
+			return nil, nil, err
+		}
+		layout := map[string]string{"year": "2006", "month": "2006_01"}[interval]
+		if layout == "" {
+			layout = "2006_01_02"
+		}
+		for v := truncateToInterval(minVal, interval); ; v = addInterval(v, interval) {
+			if len(bounds) > maxGeneratedPartitions {
+				return nil, nil, fmt.Errorf("interval %s yields more than %d partitions; use a larger interval", interval, maxGeneratedPartitions)
+			}
+			bounds = append(bounds, partitionTimeLiteral(v, pgType))
+			names = append(names, "p"+v.Format(layout))
+			if v.After(maxVal) {
+				break
+			}
+		}
+	default:
+		return nil, nil, fmt.Errorf("interval partitioning needs an integer, date or timestamp key, %s is %s", col.PGName, pgType)
+	}
+	return bounds, names, nil
+}
+
+func truncateToInterval(t time.Time, interval string) time.Time {
+	switch interval {
+	case "year":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == "week" {
+		// ISO weeks start on Monday.
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	}
+	return day
+}
+
+func addInterval(t time.Time, interval string) time.Time {
+	switch interval {
+	case "year":
+		return t.AddDate(1, 0, 0)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	case "week":
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
+
+func partitionTimeLiteral(t time.Time, pgType string) string {
+	switch pgType {
+	case "date":
+		return pgLiteral(t.Format("2006-01-02"))
+	case "timestamptz":
+		return pgLiteral(t.Format("2006-01-02 15:04:05+00"))
+	}
+	return pgLiteral(t.Format("2006-01-02 15:04:05"))
+}
+
+// queryMinMaxTime queries the MIN and MAX of a date or timestamp key column.
+// Returns (min, max, hasRows, error); hasRows is false for an empty table.
+func queryMinMaxTime(ctx context.Context, source dbQuerier, src SourceDB, table Table, key ChunkKey) (time.Time, time.Time, bool, error) {
+	query := buildMinMaxQuery(src, table, key)
+	rows, err := source.QueryContext(ctx, query)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("query min/max for %s: %w", table.SourceName, err)
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return time.Time{}, time.Time{}, false, fmt.Errorf("query min/max for %s: %w", table.SourceName, err)
+		}
+		return time.Time{}, time.Time{}, false, nil
+	}
+	var minRaw, maxRaw any
+	if err := rows.Scan(&minRaw, &maxRaw); err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("scan min/max for %s: %w", table.SourceName, err)
+	}
+	if minRaw == nil || maxRaw == nil {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	minVal, err := partitionKeyTime(minRaw)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("min/max for %s: %w", table.SourceName, err)
+	}
+	maxVal, err := partitionKeyTime(maxRaw)
+	if err != nil {
+		return time.Time{}, time.Time{}, false, fmt.Errorf("min/max for %s: %w", table.SourceName, err)
+	}
+	return minVal, maxVal, true, nil
+}
+
+// partitionKeyTime converts a scanned date or timestamp value to UTC. SQLite
+// returns text.
+func partitionKeyTime(v any) (time.Time, error) {
+	var s string
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC(), nil
+	case []byte:
+		s = string(v)
+	case string:
+		s = v
+	default:
+		return time.Time{}, fmt.Errorf("unexpected %T value", v)
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02T15:04:05.999999999", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as a date or timestamp", s)
+}
@@ -0,0 +1,368 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func partitionTestTable(p *Partitioning) Table {
+	return Table{
+		SourceName: "events",
+		PGName:     "events",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id", DataType: "bigint", ColumnType: "bigint"},
+			{SourceName: "region", PGName: "region", DataType: "varchar", ColumnType: "varchar(8)", CharMaxLen: 8},
+			{SourceName: "created_at", PGName: "created_at", DataType: "datetime", ColumnType: "datetime"},
+		},
+		Partitioning: p,
+	}
+}
+
+func partitionList(descs ...string) []Partition {
+	parts := make([]Partition, len(descs))
+	for i, d := range descs {
+		parts[i] = Partition{Name: "events_p" + string(rune('0'+i)), SourceName: "p" + string(rune('0'+i)), Description: d}
+	}
+	return parts
+}
+
+func TestTranslatePartitioning(t *testing.T) {
+	tests := []struct {
+		name       string
+		p          Partitioning
+		primaryKey bool
+		wantKey    string
+		wantBounds []string
+		wantReason string
+	}{
+		{
+			name:       "range columns with maxvalue",
+			p:          Partitioning{Method: "RANGE", Expression: "`id`", Columns: []string{"id"}, Partitions: partitionList("1000", "2000", "MAXVALUE")},
+			primaryKey: true,
+			wantKey:    `"id"`,
+			wantBounds: []string{
+				"FOR VALUES FROM (MINVALUE) TO (1000)",
+				"FOR VALUES FROM (1000) TO (2000)",
+				"DEFAULT",
+			},
+		},
+		{
+			name:    "multi-column range",
+			p:       Partitioning{Method: "RANGE", Columns: []string{"region", "id"}, Partitions: partitionList("'eu',-5", "'us',MAXVALUE")},
+			wantKey: `"region", "id"`,
+			wantBounds: []string{
+				"FOR VALUES FROM (MINVALUE, MINVALUE) TO ('eu', -5)",
+				"FOR VALUES FROM ('eu', -5) TO ('us', MAXVALUE)",
+			},
+		},
+		{
+			name:       "range expression",
+			p:          Partitioning{Method: "RANGE", Expression: "year(`created_at`)", Partitions: partitionList("2024", "MAXVALUE")},
+			wantKey:    `(CAST(EXTRACT(YEAR FROM "created_at") AS integer))`,
+			wantBounds: []string{"FOR VALUES FROM (MINVALUE) TO (2024)", "DEFAULT"},
+		},
+		{
+			name:       "expression with primary key",
+			p:          Partitioning{Method: "RANGE", Expression: "year(`created_at`)", Partitions: partitionList("2024")},
+			primaryKey: true,
+			wantReason: "PostgreSQL cannot enforce primary keys",
+		},
+		{
+			name:       "untranslatable expression",
+			p:          Partitioning{Method: "RANGE", Expression: "to_days(`created_at`)", Partitions: partitionList("739000")},
+			wantReason: "to_days",
+		},
+		{
+			name:       "list",
+			p:          Partitioning{Method: "LIST", Columns: []string{"region"}, Partitions: partitionList("'eu','uk'", "NULL,'us'")},
+			wantKey:    `"region"`,
+			wantBounds: []string{"FOR VALUES IN ('eu', 'uk')", "FOR VALUES IN (NULL, 'us')"},
+		},
+		{
+			name:       "multi-column list",
+			p:          Partitioning{Method: "LIST", Columns: []string{"region", "id"}, Partitions: partitionList("('eu',1)")},
+			wantReason: "multi-column LIST COLUMNS",
+		},
+		{
+			name:       "hash",
+			p:          Partitioning{Method: "HASH", Columns: []string{"id"}, Partitions: partitionList("", "", "")},
+			wantKey:    `"id"`,
+			wantBounds: []string{"FOR VALUES WITH (MODULUS 3, REMAINDER 0)", "FOR VALUES WITH (MODULUS 3, REMAINDER 1)", "FOR VALUES WITH (MODULUS 3, REMAINDER 2)"},
+		},
+		{
+			name:       "introspection reason kept",
+			p:          Partitioning{Method: "RANGE", Columns: []string{"id"}, Partitions: partitionList("10"), Reason: "subpartitioning is not supported"},
+			wantReason: "subpartitioning is not supported",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.p
+			table := partitionTestTable(&p)
+			if tt.primaryKey {
+				table.PrimaryKey = &Index{IsPrimary: true, Unique: true, Columns: []string{"id"}}
+			}
+			schema := &Schema{Tables: []Table{table}}
+			translatePartitioning(schema, mysqlSrc, defaultTypeMappingConfig())
+			got := schema.Tables[0].Partitioning
+			if tt.wantReason != "" {
+				if got.PGKey != "" || !strings.Contains(got.Reason, tt.wantReason) {
+					t.Fatalf("PGKey = %q, Reason = %q, want reason containing %q", got.PGKey, got.Reason, tt.wantReason)
+				}
+				if isPartitionedTable(schema.Tables[0]) {
+					t.Fatal("isPartitionedTable() = true, want false")
+				}
+				return
+			}
+			if got.Reason != "" {
+				t.Fatalf("Reason = %q, want none", got.Reason)
+			}
+			if got.PGKey != tt.wantKey {
+				t.Errorf("PGKey = %s, want %s", got.PGKey, tt.wantKey)
+			}
+			for i, want := range tt.wantBounds {
+				if got.Partitions[i].PGBound != want {
+					t.Errorf("partition %d bound = %s, want %s", i, got.Partitions[i].PGBound, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPartitionBoundValues(t *testing.T) {
+	got, err := partitionBoundValues("_utf8mb4'2024-01-01',-3,maxvalue")
+	if err != nil {
+		t.Fatalf("partitionBoundValues() error: %v", err)
+	}
+	if strings.Join(got, "|") != "'2024-01-01'|-3|MAXVALUE" {
+		t.Fatalf("partitionBoundValues() = %q", got)
+	}
+	for _, desc := range []string{"", "1,", "1 2", "now()"} {
+		if _, err := partitionBoundValues(desc); err == nil {
+			t.Errorf("partitionBoundValues(%q) error = nil, want error", desc)
+		}
+	}
+}
+
+func TestMySQLPartitionColumns(t *testing.T) {
+	cols, ok := mysqlPartitionColumns("`TenantID`,`CreatedAt`", toSnakeCase)
+	if !ok || strings.Join(cols, ",") != "tenant_id,created_at" {
+		t.Fatalf("mysqlPartitionColumns() = %v, %v", cols, ok)
+	}
+	for _, expr := range []string{"", "year(`CreatedAt`)", "`a`,", "`a` + 1"} {
+		if cols, ok := mysqlPartitionColumns(expr, toSnakeCase); ok {
+			t.Errorf("mysqlPartitionColumns(%q) = %v, want not a column list", expr, cols)
+		}
+	}
+}
+
+func TestGenerateCreateTable_Partitioned(t *testing.T) {
+	p := &Partitioning{Method: "RANGE", Columns: []string{"id"}, PGKey: `"id"`, Partitions: []Partition{
+		{Name: "events_p0", PGBound: "FOR VALUES FROM (MINVALUE) TO (1000)"},
+		{Name: "events_pmax", PGBound: "DEFAULT"},
+	}}
+	table := partitionTestTable(p)
+
+	ddl, err := generateCreateTable(table, "app", true, false, defaultTypeMappingConfig(), mysqlSrc)
+	if err != nil {
+		t.Fatalf("generateCreateTable() error: %v", err)
+	}
+	if !strings.HasPrefix(ddl, `CREATE TABLE "app"."events" (`) {
+		t.Errorf("partitioned parent should not be UNLOGGED, got:\n%s", ddl)
+	}
+	if !strings.HasSuffix(ddl, `) PARTITION BY RANGE ("id")`) {
+		t.Errorf("expected PARTITION BY clause, got:\n%s", ddl)
+	}
+
+	got := generateCreatePartitions(table, "app", true)
+	want := []string{
+		`CREATE UNLOGGED TABLE "app"."events_p0" PARTITION OF "app"."events" FOR VALUES FROM (MINVALUE) TO (1000)`,
+		`CREATE UNLOGGED TABLE "app"."events_pmax" PARTITION OF "app"."events" DEFAULT`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("generateCreatePartitions() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	table.Partitioning.PGKey = ""
+	if stmts := generateCreatePartitions(table, "app", true); stmts != nil {
+		t.Errorf("generateCreatePartitions() for untranslated partitioning = %v, want nil", stmts)
+	}
+}
+
+func configuredPartitionTestDB(t *testing.T) (*sql.DB, Table) {
+	t.Helper()
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(`CREATE TABLE events (id INTEGER PRIMARY KEY, created_at DATETIME, region TEXT);
+		INSERT INTO events VALUES (7, '2024-01-15 10:00:00', 'eu'), (2350, '2024-03-02 08:30:00', 'us')`); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+	table := Table{
+		SourceName: "events",
+		PGName:     "events",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id", ColumnType: "INTEGER"},
+			{SourceName: "created_at", PGName: "created_at", ColumnType: "DATETIME", Nullable: true},
+			{SourceName: "region", PGName: "region", ColumnType: "TEXT", Nullable: true},
+		},
+		PrimaryKey: &Index{Name: "events_pkey", IsPrimary: true, Unique: true, Columns: []string{"id"}},
+	}
+	return db, table
+}
+
+func TestApplyPartitioningConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		pc    PartitioningConfig
+		names []string
+		want  []string
+	}{
+		{
+			name:  "monthly interval",
+			pc:    PartitioningConfig{Table: "events", Method: "range", Key: "created_at", Interval: "month"},
+			names: []string{"events_p2024_01", "events_p2024_02", "events_p2024_03", "events_default"},
+			want: []string{
+				"FOR VALUES FROM ('2024-01-01 00:00:00') TO ('2024-02-01 00:00:00')",
+				"FOR VALUES FROM ('2024-02-01 00:00:00') TO ('2024-03-01 00:00:00')",
+				"FOR VALUES FROM ('2024-03-01 00:00:00') TO ('2024-04-01 00:00:00')",
+				"DEFAULT",
+			},
+		},
+		{
+			name:  "integer interval",
+			pc:    PartitioningConfig{Table: "events", Method: "range", Key: "id", Interval: "1000"},
+			names: []string{"events_p0", "events_p1000", "events_p2000", "events_default"},
+			want: []string{
+				"FOR VALUES FROM (0) TO (1000)",
+				"FOR VALUES FROM (1000) TO (2000)",
+				"FOR VALUES FROM (2000) TO (3000)",
+				"DEFAULT",
+			},
+		},
+		{
+			name:  "explicit bounds",
+			pc:    PartitioningConfig{Table: "events", Method: "range", Key: "created_at", Bounds: []string{"2024-01-01", "2025-01-01"}},
+			names: []string{"events_p2024_01_01", "events_default"},
+			want:  []string{"FOR VALUES FROM ('2024-01-01') TO ('2025-01-01')", "DEFAULT"},
+		},
+		{
+			name:  "list",
+			pc:    PartitioningConfig{Table: "events", Method: "list", Key: "region", Lists: []PartitionListConfig{{Name: "EU", Values: []string{"eu", "uk"}}}},
+			names: []string{"events_eu", "events_default"},
+			want:  []string{"FOR VALUES IN ('eu', 'uk')", "DEFAULT"},
+		},
+		{
+			name:  "hash",
+			pc:    PartitioningConfig{Table: "events", Method: "hash", Key: "id", Partitions: 2},
+			names: []string{"events_p0", "events_p1"},
+			want:  []string{"FOR VALUES WITH (MODULUS 2, REMAINDER 0)", "FOR VALUES WITH (MODULUS 2, REMAINDER 1)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, table := configuredPartitionTestDB(t)
+			schema := &Schema{Tables: []Table{table}}
+			if err := applyPartitioningConfig(context.Background(), db, &sqliteSourceDB{}, schema, []PartitioningConfig{tt.pc}, defaultTypeMappingConfig()); err != nil {
+				t.Fatalf("applyPartitioningConfig() error: %v", err)
+			}
+			p := schema.Tables[0].Partitioning
+			if p == nil || !isPartitionedTable(schema.Tables[0]) {
+				t.Fatalf("partitioning = %+v, want partitioned", p)
+			}
+			if len(p.Partitions) != len(tt.want) {
+				t.Fatalf("partitions = %+v, want %d", p.Partitions, len(tt.want))
+			}
+			for i, part := range p.Partitions {
+				if part.Name != tt.names[i] || part.PGBound != tt.want[i] {
+					t.Errorf("partition %d = %s %s, want %s %s", i, part.Name, part.PGBound, tt.names[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApplyPartitioningConfig_NilSourceSkipsIntervalScan(t *testing.T) {
+	_, table := configuredPartitionTestDB(t)
+	schema := &Schema{Tables: []Table{table}}
+	pc := PartitioningConfig{Table: "events", Method: "range", Key: "id", Interval: "1"}
+	if err := applyPartitioningConfig(context.Background(), nil, &sqliteSourceDB{}, schema, []PartitioningConfig{pc}, defaultTypeMappingConfig()); err != nil {
+		t.Fatalf("applyPartitioningConfig() error: %v", err)
+	}
+	p := schema.Tables[0].Partitioning
+	if !isPartitionedTable(schema.Tables[0]) || len(p.Partitions) != 1 || p.Partitions[0].PGBound != "DEFAULT" {
+		t.Fatalf("partitioning = %+v, want only the DEFAULT partition", p)
+	}
+}
+
+func TestApplyPartitioningConfig_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		pc      PartitioningConfig
+		wantErr string
+	}{
+		{"unknown table", PartitioningConfig{Table: "missing", Method: "hash", Key: "id", Partitions: 2}, "not found in source schema"},
+		{"unknown key", PartitioningConfig{Table: "events", Method: "hash", Key: "nope", Partitions: 2}, `key column "nope" not found`},
+		{"calendar interval on integer", PartitioningConfig{Table: "events", Method: "range", Key: "id", Interval: "month"}, "needs a date or timestamp key"},
+		{"integer interval on timestamp", PartitioningConfig{Table: "events", Method: "range", Key: "created_at", Interval: "10"}, "needs an integer key"},
+		{"text key interval", PartitioningConfig{Table: "events", Method: "range", Key: "region", Interval: "day"}, "needs an integer, date or timestamp key"},
+		{"too many partitions", PartitioningConfig{Table: "events", Method: "range", Key: "id", Interval: "1"}, "more than 1000 partitions"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, table := configuredPartitionTestDB(t)
+			schema := &Schema{Tables: []Table{table}}
+			err := applyPartitioningConfig(context.Background(), db, &sqliteSourceDB{}, schema, []PartitioningConfig{tt.pc}, defaultTypeMappingConfig())
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("applyPartitioningConfig() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyPartitioningConfig_ExtendsKeys(t *testing.T) {
+	db, events := configuredPartitionTestDB(t)
+	events.Indexes = []Index{{Name: "events_region_uq", Unique: true, Columns: []string{"region"}}}
+	child := Table{
+		PGName: "event_notes",
+		ForeignKeys: []ForeignKey{
+			{Name: "fk_notes_event", Columns: []string{"event_id"}, RefPGTable: "events", RefColumns: []string{"id"}},
+			{Name: "fk_notes_event_time", Columns: []string{"event_id", "event_at"}, RefPGTable: "events", RefColumns: []string{"id", "created_at"}},
+		},
+	}
+	schema := &Schema{Tables: []Table{events, child}}
+	pc := PartitioningConfig{Table: "events", Method: "range", Key: "created_at", Interval: "year"}
+	if err := applyPartitioningConfig(context.Background(), db, &sqliteSourceDB{}, schema, []PartitioningConfig{pc}, defaultTypeMappingConfig()); err != nil {
+		t.Fatalf("applyPartitioningConfig() error: %v", err)
+	}
+
+	events = schema.Tables[0]
+	if got := strings.Join(withPartitionKeyColumns(events, events.PrimaryKey.Columns), ","); got != "id,created_at" {
+		t.Errorf("primary key columns = %s, want id,created_at", got)
+	}
+	if got := strings.Join(events.PrimaryKey.Columns, ","); got != "id" {
+		t.Errorf("model primary key changed to %s; chunking relies on it", got)
+	}
+	def, err := indexDefinition(events, events.Indexes[0], defaultTypeMappingConfig(), 160000)
+	if err != nil {
+		t.Fatalf("indexDefinition() error: %v", err)
+	}
+	if def != `("region", "created_at")` {
+		t.Errorf("indexDefinition() = %s, want region and created_at", def)
+	}
+	if fks := schema.Tables[1].ForeignKeys; len(fks) != 1 || fks[0].Name != "fk_notes_event_time" {
+		t.Errorf("foreign keys = %+v, want only fk_notes_event_time", fks)
+	}
+	p := events.Partitioning
+	if got := strings.Join(p.ExtendedKeys, ","); got != "PRIMARY KEY,events_region_uq" {
+		t.Errorf("extended keys = %s, want PRIMARY KEY,events_region_uq", got)
+	}
+	if got := strings.Join(p.DroppedForeignKeys, ","); got != "event_notes.fk_notes_event" {
+		t.Errorf("dropped foreign keys = %s, want event_notes.fk_notes_event", got)
+	}
+}
@@ -21,8 +21,10 @@ var planCmd = &cobra.Command{
 	Use:   "plan [migration.toml]",
 	Short: "Analyze source schema and generate a migration plan report",
 	Long: `Analyze the source database schema and produce a report of objects that
-require manual follow-up: views, routines, triggers, generated columns,
-and skipped indexes.
+require manual follow-up: untranslatable views, routines, triggers,
+untranslatable generated columns, skipped indexes, and untranslatable CHECK
+constraints. Views whose definitions translate are listed separately; pgferry
+creates them after the tables.
 
 Optionally generates hook skeleton files in the specified output directory.`,
 	Args: cobra.MaximumNArgs(1),
@@ -39,12 +41,20 @@ func init() {
 
 // PlanReport holds all findings from the plan analysis.
 type PlanReport struct {
-	RequiredExtensions []PlanRequiredExtension `json:"required_extensions"`
-	SourceObjects      PlanSourceObjects       `json:"source_objects"`
-	UnsupportedColumns []PlanUnsupportedColumn `json:"unsupported_columns"`
-	GeneratedColumns   []PlanGeneratedColumn   `json:"generated_columns"`
-	SkippedIndexes     []PlanSkippedIndex      `json:"skipped_indexes"`
-	CollationWarnings  []string                `json:"collation_warnings"`
+	RequiredExtensions  []PlanRequiredExtension  `json:"required_extensions"`
+	SourceObjects       PlanSourceObjects        `json:"source_objects"`
+	ViewTranslations    []PlanView               `json:"view_translations"`
+	RoutineDefinitions  []PlanRoutine            `json:"routine_definitions"`
+	TriggerTranslations []PlanTrigger            `json:"trigger_translations"`
+	UnsupportedColumns  []PlanUnsupportedColumn  `json:"unsupported_columns"`
+	GeneratedColumns    []PlanGeneratedColumn    `json:"generated_columns"`
+	SkippedIndexes      []PlanSkippedIndex       `json:"skipped_indexes"`
+	IndexRewrites       []PlanIndexRewrite       `json:"index_rewrites"`
+	SkippedChecks       []PlanSkippedCheck       `json:"skipped_check_constraints"`
+	SkippedPartitioning []PlanSkippedPartition   `json:"skipped_partitioning"`
+	PartitioningChanges []PlanPartitioningChange `json:"partitioning_integrity_changes"`
+	ForeignKeyCycles    []PlanForeignKeyCycle    `json:"foreign_key_cycles"`
+	CollationWarnings   []string                 `json:"collation_warnings"`
 }
 
 type PlanRequiredExtension struct {
@@ -53,13 +63,44 @@ type PlanRequiredExtension struct {
 	Mode    string `json:"mode"`
 }
 
-// PlanSourceObjects holds non-table source objects.
+// PlanSourceObjects holds non-table source objects that need manual migration.
 type PlanSourceObjects struct {
 	Views    []string `json:"views"`
 	Routines []string `json:"routines"`
 	Triggers []string `json:"triggers"`
 }
 
+// PlanView describes the translation of one source view.
+type PlanView struct {
+	View       string `json:"view"`
+	Translated bool   `json:"translated"`
+	SQL        string `json:"sql,omitempty"`
+	Reason     string `json:"reason,omitempty"`
+	Definition string `json:"definition,omitempty"`
+}
+
+// PlanRoutine carries the source definition of a stored routine.
+type PlanRoutine struct {
+	Routine    string `json:"routine"` // entry of SourceObjects.Routines
+	Kind       string `json:"kind"`    // PROCEDURE or FUNCTION
+	PGName     string `json:"pg_name"`
+	Definition string `json:"definition,omitempty"`
+}
+
+// PlanTrigger describes the PL/pgSQL translation of one source trigger. SQL
+// uses the {{schema}} placeholder, like the hook skeletons it is written to.
+type PlanTrigger struct {
+	Trigger    string   `json:"trigger"`
+	PGName     string   `json:"pg_name"`
+	Table      string   `json:"table,omitempty"`
+	Timing     string   `json:"timing,omitempty"`
+	Events     []string `json:"events,omitempty"`
+	Translated bool     `json:"translated"`
+	SQL        string   `json:"sql,omitempty"`
+	Reason     string   `json:"reason,omitempty"`
+	Definition string   `json:"definition,omitempty"`
+}
+
 type PlanUnsupportedColumn struct {
 	Table      string `json:"table"`
 	Column     string `json:"column"`
@@ -72,13 +113,62 @@ type PlanGeneratedColumn struct {
 	Table      string `json:"table"`
 	Column     string `json:"column"`
 	Expression string `json:"expression"`
+	Reason     string `json:"reason,omitempty"`
+}
+
+// PlanIndexRewrite describes a prefix index that is created on key
+// expressions, so queries must use the same expressions to hit it.
+type PlanIndexRewrite struct {
+	Table string `json:"table"`
+	Index string `json:"index"`
+	Keys  string `json:"keys"`
+	Note  string `json:"note"`
 }
 
 // PlanSkippedIndex describes an index that cannot be automatically migrated.
 type PlanSkippedIndex struct {
+	Table      string `json:"table"`
+	Index      string `json:"index"`
+	Reason     string `json:"reason"`
+	Expression string `json:"expression,omitempty"` // source functional key-parts
+	Filter     string `json:"filter,omitempty"`     // source WHERE predicate
+}
+
+// PlanSkippedCheck describes a CHECK constraint whose expression cannot be
+// translated to PostgreSQL automatically.
+type PlanSkippedCheck struct {
+	Table      string `json:"table"`
+	Constraint string `json:"constraint"`
+	Expression string `json:"expression"`
+	Reason     string `json:"reason"`
+}
+
+// PlanSkippedPartition describes a partitioned source table that is created
+// as a plain PostgreSQL table.
+type PlanSkippedPartition struct {
+	Table      string `json:"table"`
+	Method     string `json:"method"`
+	Expression string `json:"expression"`
+	Partitions int    `json:"partitions"`
+	Reason     string `json:"reason"`
+}
+
+// PlanPartitioningChange is a key or foreign key that a [[partitioning]] entry
+// weakens: a unique key widened with the partition key, or a referencing
+// foreign key that is not created.
+type PlanPartitioningChange struct {
 	Table  string `json:"table"`
-	Index  string `json:"index"`
-	Reason string `json:"reason"`
+	Kind   string `json:"kind"` // extended_key or dropped_foreign_key
+	Object string `json:"object"`
+	Detail string `json:"detail"`
+}
+
+// PlanForeignKeyCycle describes tables whose foreign keys reference each
+// other, so their rows cannot be inserted in dependency order.
+type PlanForeignKeyCycle struct {
+	Tables      []string `json:"tables"`
+	ForeignKeys []string `json:"foreign_keys"`
+	Deferrable  bool     `json:"deferrable"`
 }
 
 func runPlan(cmd *cobra.Command, args []string) error {
@@ -147,6 +237,9 @@ func runPlanWithConfig(cfg *MigrationConfig, out io.Writer) error {
 	}
 
 	typeMap := effectiveTypeMapping(cfg)
+	if err := applyPartitioningConfig(ctx, sourceDB, src, schema, cfg.Partitioning, typeMap); err != nil {
+		return err
+	}
 	report := buildPlanReport(schema, sourceObjects, src, cfg, typeMap)
 
 	if format == "json" {
@@ -169,11 +262,19 @@ func runPlanWithConfig(cfg *MigrationConfig, out io.Writer) error {
 
 func buildPlanReport(schema *Schema, sourceObjects *SourceObjects, src SourceDB, cfg *MigrationConfig, typeMap TypeMappingConfig) *PlanReport {
 	report := &PlanReport{
-		RequiredExtensions: []PlanRequiredExtension{},
-		UnsupportedColumns: []PlanUnsupportedColumn{},
-		GeneratedColumns:   []PlanGeneratedColumn{},
-		SkippedIndexes:     []PlanSkippedIndex{},
-		CollationWarnings:  []string{},
+		RequiredExtensions:  []PlanRequiredExtension{},
+		UnsupportedColumns:  []PlanUnsupportedColumn{},
+		GeneratedColumns:    []PlanGeneratedColumn{},
+		SkippedIndexes:      []PlanSkippedIndex{},
+		IndexRewrites:       []PlanIndexRewrite{},
+		SkippedChecks:       []PlanSkippedCheck{},
+		SkippedPartitioning: []PlanSkippedPartition{},
+		PartitioningChanges: []PlanPartitioningChange{},
+		ForeignKeyCycles:    []PlanForeignKeyCycle{},
+		CollationWarnings:   []string{},
+		ViewTranslations:    []PlanView{},
+		RoutineDefinitions:  []PlanRoutine{},
+		TriggerTranslations: []PlanTrigger{},
 	}
 
 	for _, req := range collectRequiredExtensions(schema, src, cfg, typeMap) {
@@ -190,7 +291,44 @@ func buildPlanReport(schema *Schema, sourceObjects *SourceObjects, src SourceDB,
 
 	// Source objects
 	if sourceObjects != nil {
-		report.SourceObjects.Views = ensureStringSlice(sourceObjects.Views)
+		report.SourceObjects.Views = []string{}
+		var views []View
+		if src != nil && cfg != nil {
+			views = translateViews(sourceObjects, schema, src, cfg.Schema)
+		}
+		translated := make(map[string]bool, len(views))
+		for _, v := range views {
+			translated[v.SourceName] = v.SQL != ""
+			report.ViewTranslations = append(report.ViewTranslations, PlanView{
+				View:       v.SourceName,
+				Translated: v.SQL != "",
+				SQL:        v.SQL,
+				Reason:     v.Reason,
+				Definition: v.Definition,
+			})
+		}
+		for _, v := range sourceObjects.Views {
+			if !translated[v] {
+				report.SourceObjects.Views = append(report.SourceObjects.Views, v)
+			}
+		}
+		if src != nil {
+			report.RoutineDefinitions = buildPlanRoutines(sourceObjects, sourceIdentName(src))
+			for _, tr := range translateTriggers(sourceObjects, schema, src, "{{schema}}") {
+				def := sourceObjects.TriggerDefinitions[tr.SourceName]
+				report.TriggerTranslations = append(report.TriggerTranslations, PlanTrigger{
+					Trigger:    tr.SourceName,
+					PGName:     tr.PGName,
+					Table:      tr.Table,
+					Timing:     def.Timing,
+					Events:     def.Events,
+					Translated: tr.SQL != "",
+					SQL:        tr.SQL,
+					Reason:     tr.Reason,
+					Definition: tr.Definition,
+				})
+			}
+		}
 		report.SourceObjects.Routines = ensureStringSlice(sourceObjects.Routines)
 		report.SourceObjects.Triggers = ensureStringSlice(sourceObjects.Triggers)
 	} else {
@@ -214,12 +352,20 @@ func buildPlanReport(schema *Schema, sourceObjects *SourceObjects, src SourceDB,
 		}
 	}
 
-	// Generated columns
+	// Generated columns that cannot be recreated as PostgreSQL generated columns
 	for _, t := range schema.Tables {
 		for _, col := range t.Columns {
 			if !isGeneratedColumn(col) {
 				continue
 			}
+			var reason string
+			if src != nil {
+				_, err := translateGeneratedExpression(t, col, src, typeMap)
+				if err == nil {
+					continue
+				}
+				reason = err.Error()
+			}
 			expr := col.GenerationExpression
 			if expr == "" {
 				expr = col.Extra
@@ -228,23 +374,129 @@ func buildPlanReport(schema *Schema, sourceObjects *SourceObjects, src SourceDB,
 				Table:      t.PGName,
 				Column:     col.PGName,
 				Expression: expr,
+				Reason:     reason,
 			})
 		}
 	}
 
 	// Skipped indexes
+	if src != nil {
+		translateIndexExpressions(schema, src, typeMap)
+	}
 	for _, t := range schema.Tables {
 		for _, idx := range t.Indexes {
 			if reason, unsupported := indexUnsupportedReason(t, idx, typeMap); unsupported {
+				var exprs []string
+				for _, e := range idx.Expressions {
+					if e != "" {
+						exprs = append(exprs, e)
+					}
+				}
+				if len(exprs) > 0 && src != nil {
+					if _, err := translateIndexKeyExpressions(t, idx, src, typeMap); err != nil {
+						reason = err.Error()
+					}
+				} else if idx.Filter != "" && idx.PGFilter == "" && src != nil {
+					if _, err := translateCheckExpression(t, idx.Filter, src, typeMap); err != nil {
+						reason = "filter " + idx.Filter + ": " + err.Error()
+					}
+				}
 				report.SkippedIndexes = append(report.SkippedIndexes, PlanSkippedIndex{
-					Table:  t.PGName,
-					Index:  idx.Name,
-					Reason: reason,
+					Table:      t.PGName,
+					Index:      idx.Name,
+					Reason:     reason,
+					Expression: strings.Join(exprs, ", "),
+					Filter:     idx.Filter,
+				})
+				continue
+			}
+			if idx.HasPrefix && indexHasExpressionKeys(t, idx, typeMap) {
+				keys, _ := indexKeys(t, idx, typeMap)
+				note := "queries must filter on the same expressions to use this index"
+				if idx.Unique {
+					note = "uniqueness is enforced on the prefix; " + note
+				}
+				report.IndexRewrites = append(report.IndexRewrites, PlanIndexRewrite{
+					Table: t.PGName,
+					Index: idx.Name,
+					Keys:  strings.Join(keys, ", "),
+					Note:  note,
 				})
 			}
 		}
 	}
 
+	// Untranslatable CHECK constraints
+	if src != nil {
+		for _, t := range schema.Tables {
+			for _, ck := range t.CheckConstraints {
+				if _, err := translateCheckExpression(t, ck.Expression, src, typeMap); err != nil {
+					report.SkippedChecks = append(report.SkippedChecks, PlanSkippedCheck{
+						Table:      t.PGName,
+						Constraint: ck.Name,
+						Expression: ck.Expression,
+						Reason:     err.Error(),
+					})
+				}
+			}
+		}
+	}
+
+	// Partitioned tables created unpartitioned
+	if src != nil {
+		translatePartitioning(schema, src, typeMap)
+	}
+	for _, t := range schema.Tables {
+		p := t.Partitioning
+		if p == nil || p.PGKey != "" {
+			continue
+		}
+		reason := p.Reason
+		if reason == "" {
+			reason = "partitioning is not translated"
+		}
+		report.SkippedPartitioning = append(report.SkippedPartitioning, PlanSkippedPartition{
+			Table:      t.PGName,
+			Method:     p.Method,
+			Expression: p.Expression,
+			Partitions: len(p.Partitions),
+			Reason:     reason,
+		})
+	}
+
+	// Integrity given up for configured partitioning
+	for _, t := range schema.Tables {
+		p := t.Partitioning
+		if p == nil {
+			continue
+		}
+		for _, k := range p.ExtendedKeys {
+			report.PartitioningChanges = append(report.PartitioningChanges, PlanPartitioningChange{
+				Table:  t.PGName,
+				Kind:   "extended_key",
+				Object: k,
+				Detail: fmt.Sprintf("extended with partition key %s; uniqueness is only enforced together with it", p.PGKey),
+			})
+		}
+		for _, fk := range p.DroppedForeignKeys {
+			report.PartitioningChanges = append(report.PartitioningChanges, PlanPartitioningChange{
+				Table:  t.PGName,
+				Kind:   "dropped_foreign_key",
+				Object: fk,
+				Detail: fmt.Sprintf("not created; the referenced key of %s lacks partition key %s", t.PGName, p.PGKey),
+			})
+		}
+	}
+
+	deferrable := cfg.DeferrableForeignKeys == "cycles" || cfg.DeferrableForeignKeys == "all"
+	for _, c := range findForeignKeyCycles(schema) {
+		report.ForeignKeyCycles = append(report.ForeignKeyCycles, PlanForeignKeyCycle{
+			Tables:      c.Tables,
+			ForeignKeys: c.ForeignKeys,
+			Deferrable:  deferrable,
+		})
+	}
+
 	// Collation warnings
 	if warnings := collectCollationWarnings(schema, typeMap); len(warnings) > 0 {
 		report.CollationWarnings = warnings
@@ -282,6 +534,17 @@ func writePlanText(w io.Writer, report *PlanReport) {
 		fmt.Fprintln(w)
 	}
 
+	if n := countPlanTranslatedViews(report.ViewTranslations); n > 0 {
+		hasContent = true
+		fmt.Fprintf(w, "## Translated Views (%d, created after the tables)\n\n", n)
+		for _, v := range report.ViewTranslations {
+			if v.Translated {
+				fmt.Fprintf(w, "  - %s\n", v.View)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
 	// Source objects
 	objs := &report.SourceObjects
 	if len(objs.Views) > 0 || len(objs.Routines) > 0 || len(objs.Triggers) > 0 {
@@ -290,6 +553,10 @@ func writePlanText(w io.Writer, report *PlanReport) {
 		if len(objs.Views) > 0 {
 			fmt.Fprintf(w, "Views (%d):\n", len(objs.Views))
 			for _, v := range objs.Views {
+				if pv, ok := findPlanView(report.ViewTranslations, v); ok && pv.Reason != "" {
+					fmt.Fprintf(w, "  - %s (%s)\n", v, pv.Reason)
+					continue
+				}
 				fmt.Fprintf(w, "  - %s\n", v)
 			}
 			fmt.Fprintf(w, "  Recommended hook phase: after_all\n\n")
@@ -299,13 +566,23 @@ func writePlanText(w io.Writer, report *PlanReport) {
 			for _, r := range objs.Routines {
 				fmt.Fprintf(w, "  - %s\n", r)
 			}
+			fmt.Fprintf(w, "  Definitions and PL/pgSQL stubs: routine_*.sql in --output-dir\n")
 			fmt.Fprintf(w, "  Recommended hook phase: after_all\n\n")
 		}
 		if len(objs.Triggers) > 0 {
 			fmt.Fprintf(w, "Triggers (%d):\n", len(objs.Triggers))
 			for _, t := range objs.Triggers {
-				fmt.Fprintf(w, "  - %s\n", t)
+				pt, ok := findPlanTrigger(report.TriggerTranslations, t)
+				switch {
+				case ok && pt.Translated:
+					fmt.Fprintf(w, "  - %s (translated to PL/pgSQL)\n", t)
+				case ok && pt.Reason != "":
+					fmt.Fprintf(w, "  - %s (%s)\n", t, pt.Reason)
+				default:
+					fmt.Fprintf(w, "  - %s\n", t)
+				}
 			}
+			fmt.Fprintf(w, "  Translations and PL/pgSQL stubs: trigger_*.sql in --output-dir\n")
 			fmt.Fprintf(w, "  Recommended hook phase: after_all\n\n")
 		}
 	}
@@ -324,9 +601,13 @@ func writePlanText(w io.Writer, report *PlanReport) {
 	if len(report.GeneratedColumns) > 0 {
 		hasContent = true
 		fmt.Fprintf(w, "## Generated Columns (%d)\n\n", len(report.GeneratedColumns))
-		fmt.Fprintf(w, "These columns will be materialized as plain data. Generation expressions\n")
-		fmt.Fprintf(w, "must be recreated manually in PostgreSQL.\n\n")
+		fmt.Fprintf(w, "These expressions could not be translated, so the columns will be materialized\n")
+		fmt.Fprintf(w, "as plain data. Generation expressions must be recreated manually in PostgreSQL.\n\n")
 		for _, gc := range report.GeneratedColumns {
+			if gc.Reason != "" {
+				fmt.Fprintf(w, "  - %s.%s (%s): %s\n", gc.Table, gc.Column, gc.Expression, gc.Reason)
+				continue
+			}
 			fmt.Fprintf(w, "  - %s.%s (%s)\n", gc.Table, gc.Column, gc.Expression)
 		}
 		fmt.Fprintf(w, "  Recommended hook phase: after_data\n\n")
@@ -339,10 +620,81 @@ func writePlanText(w io.Writer, report *PlanReport) {
 		fmt.Fprintf(w, "These indexes cannot be migrated automatically and need manual recreation.\n\n")
 		for _, si := range report.SkippedIndexes {
 			fmt.Fprintf(w, "  - %s.%s: %s\n", si.Table, si.Index, si.Reason)
+			if si.Expression != "" {
+				fmt.Fprintf(w, "    Source: %s\n", si.Expression)
+			}
+			if si.Filter != "" {
+				fmt.Fprintf(w, "    Filter: %s\n", si.Filter)
+			}
 		}
 		fmt.Fprintf(w, "  Recommended hook phase: after_all\n\n")
 	}
 
+	// Prefix indexes created on expressions
+	if len(report.IndexRewrites) > 0 {
+		hasContent = true
+		fmt.Fprintf(w, "## Rewritten Indexes (%d)\n\n", len(report.IndexRewrites))
+		fmt.Fprintf(w, "These prefix indexes are created on key expressions. Queries must compare the\n")
+		fmt.Fprintf(w, "same expressions (e.g. WHERE left(col, n) = left($1, n)) for the planner to use them.\n\n")
+		for _, ir := range report.IndexRewrites {
+			fmt.Fprintf(w, "  - %s.%s (%s): %s\n", ir.Table, ir.Index, ir.Keys, ir.Note)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Skipped CHECK constraints
+	if len(report.SkippedChecks) > 0 {
+		hasContent = true
+		fmt.Fprintf(w, "## Skipped CHECK Constraints (%d)\n\n", len(report.SkippedChecks))
+		fmt.Fprintf(w, "These CHECK constraints could not be translated and need manual recreation.\n\n")
+		for _, sc := range report.SkippedChecks {
+			fmt.Fprintf(w, "  - %s.%s: %s\n", sc.Table, sc.Constraint, sc.Reason)
+			fmt.Fprintf(w, "    Source: %s\n", sc.Expression)
+		}
+		fmt.Fprintf(w, "  Recommended hook phase: after_all\n\n")
+	}
+
+	// Skipped partitioning
+	if len(report.SkippedPartitioning) > 0 {
+		hasContent = true
+		fmt.Fprintf(w, "## Skipped Partitioning (%d)\n\n", len(report.SkippedPartitioning))
+		fmt.Fprintf(w, "These partitioned tables will be created unpartitioned and hold all rows.\n\n")
+		for _, sp := range report.SkippedPartitioning {
+			fmt.Fprintf(w, "  - %s (%s, %d partitions): %s\n", sp.Table, sp.Method, sp.Partitions, sp.Reason)
+			if sp.Expression != "" {
+				fmt.Fprintf(w, "    Source: %s\n", sp.Expression)
+			}
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Partitioning integrity changes
+	if len(report.PartitioningChanges) > 0 {
+		hasContent = true
+		fmt.Fprintf(w, "## Partitioning Integrity Changes (%d)\n\n", len(report.PartitioningChanges))
+		fmt.Fprintf(w, "[[partitioning]] entries weaken these keys; enforce the original rules by other means if needed.\n\n")
+		for _, pc := range report.PartitioningChanges {
+			fmt.Fprintf(w, "  - %s %s: %s\n", pc.Table, pc.Object, pc.Detail)
+		}
+		fmt.Fprintln(w)
+	}
+
+	// Foreign key cycles
+	if len(report.ForeignKeyCycles) > 0 {
+		hasContent = true
+		fmt.Fprintf(w, "## Foreign Key Cycles (%d)\n\n", len(report.ForeignKeyCycles))
+		fmt.Fprintf(w, "These tables reference each other, so no insert order satisfies their foreign keys.\n\n")
+		for _, fc := range report.ForeignKeyCycles {
+			note := "not deferrable; set deferrable_foreign_keys = \"cycles\""
+			if fc.Deferrable {
+				note = "DEFERRABLE INITIALLY DEFERRED"
+			}
+			fmt.Fprintf(w, "  - %s (%s)\n", strings.Join(fc.Tables, " ↔ "), note)
+			fmt.Fprintf(w, "    Foreign keys: %s\n", strings.Join(fc.ForeignKeys, ", "))
+		}
+		fmt.Fprintln(w)
+	}
+
 	// Collation warnings
 	if len(report.CollationWarnings) > 0 {
 		hasContent = true
@@ -386,11 +738,19 @@ func writeHookSkeletons(dir string, report *PlanReport, schema string) error {
 		files = append(files, hookFile{"before_fk.sql", body})
 	}
 
-	// after_all: views, routines, triggers, skipped indexes
+	// after_all: views, routines, triggers, skipped indexes, skipped checks
 	if body := buildAfterAllSkeleton(report, schema); body != "" {
 		files = append(files, hookFile{"after_all.sql", body})
 	}
 
+	// One file per routine and trigger, referenced from after_all.sql
+	for _, r := range report.SourceObjects.Routines {
+		files = append(files, hookFile{routineSkeletonFile(r), buildRoutineSkeleton(report, r)})
+	}
+	for _, t := range report.SourceObjects.Triggers {
+		files = append(files, hookFile{triggerSkeletonFile(t), buildTriggerSkeleton(report, t)})
+	}
+
 	if len(files) == 0 {
 		return nil
 	}
@@ -445,8 +805,9 @@ func buildAfterAllSkeleton(report *PlanReport, schema string) string {
 	objs := &report.SourceObjects
 	hasObjects := len(objs.Views) > 0 || len(objs.Routines) > 0 || len(objs.Triggers) > 0
 	hasIndexes := len(report.SkippedIndexes) > 0
+	hasChecks := len(report.SkippedChecks) > 0
 
-	if !hasObjects && !hasIndexes {
+	if !hasObjects && !hasIndexes && !hasChecks {
 		return ""
 	}
 
@@ -459,6 +820,19 @@ func buildAfterAllSkeleton(report *PlanReport, schema string) string {
 		b.WriteString("-- Recreate these views in PostgreSQL syntax.\n")
 		for _, v := range objs.Views {
 			fmt.Fprintf(&b, "-- TODO: CREATE VIEW %s.%s AS ...;\n", pgIdent("{{schema}}"), pgIdent(v))
+			pv, ok := findPlanView(report.ViewTranslations, v)
+			if !ok {
+				continue
+			}
+			if pv.Reason != "" {
+				fmt.Fprintf(&b, "--   Not translated: %s\n", pv.Reason)
+			}
+			if pv.Definition != "" {
+				b.WriteString("--   Source definition:\n")
+				for _, line := range strings.Split(strings.TrimSpace(pv.Definition), "\n") {
+					fmt.Fprintf(&b, "--     %s\n", strings.TrimRight(line, "\r"))
+				}
+			}
 		}
 		b.WriteByte('\n')
 	}
@@ -467,7 +841,7 @@ func buildAfterAllSkeleton(report *PlanReport, schema string) string {
 		b.WriteString("-- Routines (functions/procedures)\n")
 		b.WriteString("-- Rewrite these in PL/pgSQL or another PostgreSQL procedural language.\n")
 		for _, r := range objs.Routines {
-			fmt.Fprintf(&b, "-- TODO: %s — rewrite for PostgreSQL\n", r)
+			fmt.Fprintf(&b, "-- TODO: %s — rewrite for PostgreSQL (stub in %s)\n", r, routineSkeletonFile(r))
 		}
 		b.WriteByte('\n')
 	}
@@ -476,7 +850,11 @@ func buildAfterAllSkeleton(report *PlanReport, schema string) string {
 		b.WriteString("-- Triggers\n")
 		b.WriteString("-- Recreate these triggers using PostgreSQL trigger functions.\n")
 		for _, t := range objs.Triggers {
-			fmt.Fprintf(&b, "-- TODO: CREATE TRIGGER %s ...;\n", pgIdent(t))
+			if pt, ok := findPlanTrigger(report.TriggerTranslations, t); ok && pt.Translated {
+				fmt.Fprintf(&b, "-- %s: translated to PL/pgSQL in %s; review it and add it as a hook\n", t, triggerSkeletonFile(t))
+				continue
+			}
+			fmt.Fprintf(&b, "-- TODO: CREATE TRIGGER %s ...; (stub in %s)\n", pgIdent(t), triggerSkeletonFile(t))
 		}
 		b.WriteByte('\n')
 	}
@@ -487,6 +865,22 @@ func buildAfterAllSkeleton(report *PlanReport, schema string) string {
 		for _, si := range report.SkippedIndexes {
 			fmt.Fprintf(&b, "-- TODO: CREATE INDEX ON %s.%s ...;\n", pgIdent("{{schema}}"), pgIdent(si.Table))
 			fmt.Fprintf(&b, "--   Source: %s.%s — %s\n", si.Table, si.Index, si.Reason)
+			if si.Expression != "" {
+				fmt.Fprintf(&b, "--   Key expressions: %s\n", si.Expression)
+			}
+			if si.Filter != "" {
+				fmt.Fprintf(&b, "--   WHERE %s\n", si.Filter)
+			}
+		}
+		b.WriteByte('\n')
+	}
+
+	if hasChecks {
+		b.WriteString("-- Skipped CHECK Constraints\n")
+		b.WriteString("-- Rewrite these source expressions in PostgreSQL syntax.\n")
+		for _, sc := range report.SkippedChecks {
+			fmt.Fprintf(&b, "-- TODO: ALTER TABLE %s.%s ADD CONSTRAINT %s CHECK (...);\n", pgIdent("{{schema}}"), pgIdent(sc.Table), pgIdent(sc.Constraint))
+			fmt.Fprintf(&b, "--   Source: %s — %s\n", sc.Expression, sc.Reason)
 		}
 		b.WriteByte('\n')
 	}
@@ -510,3 +904,177 @@ func sortedGeneratedColumnTables(m map[string][]PlanGeneratedColumn) []string {
 	sort.Strings(keys)
 	return keys
 }
+
+func countPlanTranslatedViews(views []PlanView) int {
+	n := 0
+	for _, v := range views {
+		if v.Translated {
+			n++
+		}
+	}
+	return n
+}
+
+func findPlanView(views []PlanView, name string) (PlanView, bool) {
+	for _, v := range views {
+		if v.View == name {
+			return v, true
+		}
+	}
+	return PlanView{}, false
+}
+
+// buildPlanRoutines pairs each routine with its source definition and the
+// name it would get in PostgreSQL.
+func buildPlanRoutines(objs *SourceObjects, identName func(string) string) []PlanRoutine {
+	routines := []PlanRoutine{}
+	for _, label := range objs.Routines {
+		kind, name := "FUNCTION", label
+		if i := strings.LastIndexByte(label, ' '); i >= 0 {
+			name = label[i+1:]
+			if strings.Contains(strings.ToUpper(label[:i]), "PROCEDURE") {
+				kind = "PROCEDURE"
+			}
+		}
+		routines = append(routines, PlanRoutine{
+			Routine:    label,
+			Kind:       kind,
+			PGName:     identName(name),
+			Definition: objs.RoutineDefinitions[label],
+		})
+	}
+	return routines
+}
+
+func findPlanTrigger(triggers []PlanTrigger, name string) (PlanTrigger, bool) {
+	for _, t := range triggers {
+		if t.Trigger == name {
+			return t, true
+		}
+	}
+	return PlanTrigger{}, false
+}
+
+// skeletonFileSlug turns an object name into a safe file name part.
+func skeletonFileSlug(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteByte('_')
+	}
+	return b.String()
+}
+
+func routineSkeletonFile(routine string) string {
+	return "routine_" + skeletonFileSlug(routine) + ".sql"
+}
+
+func triggerSkeletonFile(trigger string) string {
+	return "trigger_" + skeletonFileSlug(trigger) + ".sql"
+}
+
+// writeCommentedBlock writes text as indented SQL comment lines.
+func writeCommentedBlock(b *strings.Builder, text string) {
+	for _, line := range strings.Split(strings.TrimSpace(text), "\n") {
+		fmt.Fprintf(b, "--   %s\n", strings.TrimRight(line, "\r"))
+	}
+}
+
+func buildRoutineSkeleton(report *PlanReport, routine string) string {
+	pr := PlanRoutine{Routine: routine, Kind: "FUNCTION", PGName: strings.ToLower(routine)}
+	for _, r := range report.RoutineDefinitions {
+		if r.Routine == routine {
+			pr = r
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "-- Routine: %s\n", routine)
+	b.WriteString("-- Rewrite the body in PL/pgSQL, uncomment, and add this file to an after_all hook.\n")
+	b.WriteString("--\n")
+	if pr.Definition != "" {
+		b.WriteString("-- Source definition:\n")
+		writeCommentedBlock(&b, pr.Definition)
+	} else {
+		b.WriteString("-- Source definition unavailable (missing privileges or encrypted routine).\n")
+	}
+	b.WriteString("--\n")
+	name := pgIdent("{{schema}}") + "." + pgIdent(pr.PGName)
+	if pr.Kind == "PROCEDURE" {
+		fmt.Fprintf(&b, "-- CREATE OR REPLACE PROCEDURE %s() AS $fn$\n", name)
+	} else {
+		fmt.Fprintf(&b, "-- CREATE OR REPLACE FUNCTION %s() RETURNS void AS $fn$ -- TODO: arguments and return type\n", name)
+	}
+	b.WriteString("-- BEGIN\n")
+	b.WriteString("--     -- TODO: port the routine body\n")
+	b.WriteString("-- END;\n")
+	b.WriteString("-- $fn$ LANGUAGE plpgsql;\n")
+	return b.String()
+}
+
+func buildTriggerSkeleton(report *PlanReport, trigger string) string {
+	pt, ok := findPlanTrigger(report.TriggerTranslations, trigger)
+	if !ok {
+		pt = PlanTrigger{Trigger: trigger, PGName: strings.ToLower(trigger)}
+	}
+
+	var b strings.Builder
+	if pt.Table != "" {
+		fmt.Fprintf(&b, "-- Trigger: %s on %s\n", trigger, pt.Table)
+	} else {
+		fmt.Fprintf(&b, "-- Trigger: %s\n", trigger)
+	}
+	if pt.Translated {
+		b.WriteString("-- Translated automatically from the source definition below. Review it, then\n")
+		b.WriteString("-- add this file to an after_all hook.\n")
+	} else {
+		if pt.Reason != "" {
+			fmt.Fprintf(&b, "-- Not translated: %s\n", pt.Reason)
+		}
+		b.WriteString("-- Port the body to PL/pgSQL, uncomment, and add this file to an after_all hook.\n")
+	}
+	b.WriteString("--\n")
+	if pt.Definition != "" {
+		b.WriteString("-- Source definition:\n")
+		writeCommentedBlock(&b, pt.Definition)
+	} else {
+		b.WriteString("-- Source definition unavailable.\n")
+	}
+
+	if pt.Translated {
+		b.WriteString("\n")
+		b.WriteString(pt.SQL)
+		return b.String()
+	}
+
+	timing, events := pt.Timing, strings.Join(pt.Events, " OR ")
+	if timing == "" {
+		timing = "AFTER"
+	}
+	if events == "" {
+		events = "INSERT"
+	}
+	table := pt.Table
+	if table == "" {
+		table = "table_name"
+	}
+	ret := "NULL"
+	if timing == "BEFORE" {
+		ret = "NEW"
+	}
+	funcName := pgIdent("{{schema}}") + "." + pgIdent(pt.PGName+"_fn")
+	b.WriteString("--\n")
+	fmt.Fprintf(&b, "-- CREATE OR REPLACE FUNCTION %s() RETURNS TRIGGER AS $fn$\n", funcName)
+	b.WriteString("-- BEGIN\n")
+	b.WriteString("--     -- TODO: port the trigger body\n")
+	fmt.Fprintf(&b, "--     RETURN %s;\n", ret)
+	b.WriteString("-- END;\n")
+	b.WriteString("-- $fn$ LANGUAGE plpgsql;\n")
+	b.WriteString("--\n")
+	fmt.Fprintf(&b, "-- CREATE TRIGGER %s %s %s ON %s.%s\n", pgIdent(pt.PGName), timing, events, pgIdent("{{schema}}"), pgIdent(table))
+	fmt.Fprintf(&b, "-- FOR EACH ROW EXECUTE FUNCTION %s();\n", funcName)
+	return b.String()
+}
@@ -35,18 +35,30 @@ func TestBuildPlanReport_Full(t *testing.T) {
 				Columns: []Column{
 					{SourceName: "id", PGName: "id", DataType: "int"},
 					{SourceName: "full_name", PGName: "full_name", DataType: "varchar", Extra: "VIRTUAL GENERATED", GenerationExpression: "concat(`first_name`,' ',`last_name`)"},
+					{SourceName: "double_id", PGName: "double_id", DataType: "int", ColumnType: "int", Extra: "STORED GENERATED", GenerationExpression: "(`id` * 2)"},
+					{SourceName: "bio", PGName: "bio", DataType: "varchar", ColumnType: "varchar(500)", CharMaxLen: 500},
 				},
 				Indexes: []Index{
 					{Name: "idx_ft", SourceName: "idx_ft", Type: "FULLTEXT", Columns: []string{"full_name"}},
 					{Name: "idx_normal", SourceName: "idx_normal", Type: "BTREE", Columns: []string{"id"}},
+					{Name: "uq_bio", SourceName: "uq_bio", Type: "BTREE", Unique: true, HasPrefix: true, Columns: []string{"bio"}, PrefixLengths: []int{32}},
+					{Name: "idx_bio_lower", SourceName: "idx_bio_lower", Type: "BTREE", HasExpression: true, Columns: []string{""}, Expressions: []string{"lower(`bio`)"}},
+					{Name: "idx_bio_hash", SourceName: "idx_bio_hash", Type: "BTREE", HasExpression: true, Columns: []string{""}, Expressions: []string{"md5(`bio`)"}},
+				},
+				CheckConstraints: []CheckConstraint{
+					{Name: "users_chk_1", SourceName: "users_chk_1", Expression: "(`id` > 0)"},
+					{Name: "users_chk_2", SourceName: "users_chk_2", Expression: "(`full_name` regexp _utf8mb4'^[A-Z]')"},
 				},
 			},
 		},
 	}
 	objs := &SourceObjects{
-		Views:    []string{"v_active_users"},
+		Views:    []string{"v_active_users", "v_totals"},
 		Routines: []string{"FUNCTION calc_score"},
 		Triggers: []string{"trg_audit"},
+		ViewDefinitions: map[string]string{
+			"v_totals": "select count(0) AS `n` from `users`",
+		},
 	}
 	cfg := &MigrationConfig{TypeMapping: defaultTypeMappingConfig()}
 
@@ -55,6 +67,12 @@ func TestBuildPlanReport_Full(t *testing.T) {
 	if len(report.SourceObjects.Views) != 1 || report.SourceObjects.Views[0] != "v_active_users" {
 		t.Errorf("views = %v, want [v_active_users]", report.SourceObjects.Views)
 	}
+	if len(report.ViewTranslations) != 2 {
+		t.Fatalf("view translations = %d, want 2", len(report.ViewTranslations))
+	}
+	if vt := report.ViewTranslations[1]; !vt.Translated || vt.View != "v_totals" {
+		t.Errorf("view translation = %+v, want v_totals translated", vt)
+	}
 	if len(report.SourceObjects.Routines) != 1 {
 		t.Errorf("routines = %d, want 1", len(report.SourceObjects.Routines))
 	}
@@ -71,12 +89,30 @@ func TestBuildPlanReport_Full(t *testing.T) {
 	if gc.Expression != "concat(`first_name`,' ',`last_name`)" {
 		t.Errorf("generated column expression = %q, want source formula", gc.Expression)
 	}
-	if len(report.SkippedIndexes) != 1 {
-		t.Fatalf("skipped indexes = %d, want 1", len(report.SkippedIndexes))
+	if gc.Reason != `concat(): unknown column or unsupported keyword "first_name"` {
+		t.Errorf("generated column reason = %q", gc.Reason)
+	}
+	if len(report.SkippedIndexes) != 2 {
+		t.Fatalf("skipped indexes = %d, want 2", len(report.SkippedIndexes))
 	}
 	if report.SkippedIndexes[0].Index != "idx_ft" {
 		t.Errorf("skipped index = %+v", report.SkippedIndexes[0])
 	}
+	if si := report.SkippedIndexes[1]; si.Index != "idx_bio_hash" || si.Expression != "md5(`bio`)" || !strings.Contains(si.Reason, "unsupported function md5()") {
+		t.Errorf("skipped expression index = %+v", si)
+	}
+	if len(report.IndexRewrites) != 1 {
+		t.Fatalf("index rewrites = %d, want 1", len(report.IndexRewrites))
+	}
+	if ir := report.IndexRewrites[0]; ir.Index != "uq_bio" || ir.Keys != `left("bio", 32)` || !strings.Contains(ir.Note, "uniqueness") {
+		t.Errorf("index rewrite = %+v", ir)
+	}
+	if len(report.SkippedChecks) != 1 {
+		t.Fatalf("skipped checks = %d, want 1", len(report.SkippedChecks))
+	}
+	if sc := report.SkippedChecks[0]; sc.Constraint != "users_chk_2" || sc.Expression != "(`full_name` regexp _utf8mb4'^[A-Z]')" {
+		t.Errorf("skipped check = %+v", sc)
+	}
 }
 
 func TestWritePlanText_Empty(t *testing.T) {
@@ -256,9 +292,16 @@ func TestWriteHookSkeletons_AfterAll(t *testing.T) {
 			Routines: []string{"FUNCTION calc"},
 			Triggers: []string{"trg_audit"},
 		},
+		ViewTranslations: []PlanView{
+			{View: "v_summary", Reason: "unsupported TOP clause", Definition: "CREATE VIEW v_summary AS\nSELECT TOP 10 PERCENT id FROM orders"},
+			{View: "v_ok", Translated: true, SQL: `CREATE VIEW "app"."v_ok" AS SELECT 1`},
+		},
 		SkippedIndexes: []PlanSkippedIndex{
 			{Table: "orders", Index: "idx_ft", Reason: "FULLTEXT not supported"},
 		},
+		SkippedChecks: []PlanSkippedCheck{
+			{Table: "orders", Constraint: "orders_chk_1", Expression: "(`code` regexp '^A')", Reason: `unknown column or unsupported keyword "regexp"`},
+		},
 	}
 
 	if err := writeHookSkeletons(dir, report, "app"); err != nil {
@@ -279,11 +322,18 @@ func TestWriteHookSkeletons_AfterAll(t *testing.T) {
 		"{{schema}}",
 		`"{{schema}}"."v_summary"`,
 		`"{{schema}}"."orders"`,
+		`ADD CONSTRAINT "orders_chk_1" CHECK (...)`,
+		"(`code` regexp '^A')",
+		"--   Not translated: unsupported TOP clause",
+		"--     SELECT TOP 10 PERCENT id FROM orders",
 	} {
 		if !strings.Contains(content, want) {
 			t.Errorf("after_all.sql missing %q", want)
 		}
 	}
+	if strings.Contains(content, "v_ok") {
+		t.Error("after_all.sql should not list views pgferry creates")
+	}
 }
 
 func TestWriteHookSkeletons_CreatesDir(t *testing.T) {
@@ -402,3 +452,175 @@ func TestBuildPlanReport_PostGISDisabledMarksSpatialUnsupported(t *testing.T) {
 		t.Fatalf("skipped index reason = %q, want postgis hint", report.SkippedIndexes[0].Reason)
 	}
 }
+
+func TestWriteHookSkeletons_RoutinesAndTriggers(t *testing.T) {
+	schema := triggerTestSchema()
+	objs := &SourceObjects{
+		Routines: []string{"PROCEDURE sync_data"},
+		Triggers: []string{"trg_touch", "trg_complex"},
+		RoutineDefinitions: map[string]string{
+			"PROCEDURE sync_data": "CREATE PROCEDURE `sync_data`()\nBEGIN\n  DELETE FROM stats;\nEND",
+		},
+		TriggerDefinitions: map[string]TriggerDefinition{
+			"trg_touch": {
+				Table: "orders", Timing: "BEFORE", Events: []string{"UPDATE"},
+				Body:       "SET NEW.updated_at = NOW()",
+				Definition: "CREATE TRIGGER `trg_touch` BEFORE UPDATE ON `orders` FOR EACH ROW SET NEW.updated_at = NOW()",
+			},
+			"trg_complex": {
+				Table: "orders", Timing: "AFTER", Events: []string{"INSERT"},
+				Body:       "CALL notify(NEW.id)",
+				Definition: "CREATE TRIGGER `trg_complex` AFTER INSERT ON `orders` FOR EACH ROW CALL notify(NEW.id)",
+			},
+		},
+	}
+	cfg := &MigrationConfig{Schema: "app", TypeMapping: defaultTypeMappingConfig()}
+	report := buildPlanReport(schema, objs, mysqlSrc, cfg, effectiveTypeMapping(cfg))
+
+	if len(report.TriggerTranslations) != 2 {
+		t.Fatalf("trigger translations = %d, want 2", len(report.TriggerTranslations))
+	}
+	if !report.TriggerTranslations[0].Translated || report.TriggerTranslations[1].Translated {
+		t.Fatalf("trigger translations = %+v", report.TriggerTranslations)
+	}
+
+	dir := t.TempDir()
+	if err := writeHookSkeletons(dir, report, "app"); err != nil {
+		t.Fatalf("writeHookSkeletons: %v", err)
+	}
+
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("read %s: %v", name, err)
+		}
+		return string(data)
+	}
+
+	routine := read("routine_procedure_sync_data.sql")
+	for _, want := range []string{
+		"--   CREATE PROCEDURE `sync_data`()",
+		"--     DELETE FROM stats;",
+		`-- CREATE OR REPLACE PROCEDURE "{{schema}}"."sync_data"() AS $fn$`,
+	} {
+		if !strings.Contains(routine, want) {
+			t.Errorf("routine skeleton missing %q:\n%s", want, routine)
+		}
+	}
+
+	touch := read("trigger_trg_touch.sql")
+	for _, want := range []string{
+		"--   CREATE TRIGGER `trg_touch`",
+		`NEW."updated_at" := LOCALTIMESTAMP;`,
+		"\nCREATE TRIGGER \"trg_touch\" BEFORE UPDATE ON \"{{schema}}\".\"orders\"",
+	} {
+		if !strings.Contains(touch, want) {
+			t.Errorf("translated trigger skeleton missing %q:\n%s", want, touch)
+		}
+	}
+
+	untranslated := read("trigger_trg_complex.sql")
+	for _, want := range []string{
+		"-- Not translated: unsupported statement CALL",
+		`-- CREATE TRIGGER "trg_complex" AFTER INSERT ON "{{schema}}"."orders"`,
+	} {
+		if !strings.Contains(untranslated, want) {
+			t.Errorf("untranslated trigger skeleton missing %q:\n%s", want, untranslated)
+		}
+	}
+
+	afterAll := read("after_all.sql")
+	for _, want := range []string{
+		"(stub in routine_procedure_sync_data.sql)",
+		"trg_touch: translated to PL/pgSQL in trigger_trg_touch.sql",
+		"(stub in trigger_trg_complex.sql)",
+	} {
+		if !strings.Contains(afterAll, want) {
+			t.Errorf("after_all.sql missing %q", want)
+		}
+	}
+}
+
+func TestBuildPlanReport_SkippedPartitioning(t *testing.T) {
+	cfg := &MigrationConfig{TypeMapping: defaultTypeMappingConfig()}
+	schema := &Schema{
+		Tables: []Table{
+			partitionTestTable(&Partitioning{Method: "RANGE", Columns: []string{"id"}, Partitions: partitionList("100", "MAXVALUE")}),
+			{
+				PGName:       "logs",
+				Columns:      []Column{{SourceName: "created_at", PGName: "created_at", DataType: "datetime", ColumnType: "datetime"}},
+				PrimaryKey:   &Index{IsPrimary: true, Columns: []string{"created_at"}},
+				Partitioning: &Partitioning{Method: "RANGE", Expression: "year(`created_at`)", Partitions: partitionList("2024")},
+			},
+		},
+	}
+
+	report := buildPlanReport(schema, nil, mysqlSrc, cfg, effectiveTypeMapping(cfg))
+	if len(report.SkippedPartitioning) != 1 {
+		t.Fatalf("skipped partitioning = %d, want 1", len(report.SkippedPartitioning))
+	}
+	sp := report.SkippedPartitioning[0]
+	if sp.Table != "logs" || sp.Partitions != 1 || !strings.Contains(sp.Reason, "primary keys") {
+		t.Fatalf("skipped partitioning = %+v", sp)
+	}
+
+	var buf bytes.Buffer
+	writePlanText(&buf, report)
+	if !strings.Contains(buf.String(), "## Skipped Partitioning (1)") {
+		t.Fatalf("plan text missing partitioning section:\n%s", buf.String())
+	}
+}
+
+func TestBuildPlanReport_PartitioningChanges(t *testing.T) {
+	cfg := &MigrationConfig{TypeMapping: defaultTypeMappingConfig()}
+	p := &Partitioning{
+		Method:             "RANGE",
+		Columns:            []string{"created_at"},
+		PGKey:              `"created_at"`,
+		ExtendedKeys:       []string{"PRIMARY KEY"},
+		DroppedForeignKeys: []string{"event_notes.fk_notes_event"},
+	}
+	schema := &Schema{Tables: []Table{{PGName: "events", Partitioning: p}}}
+
+	report := buildPlanReport(schema, nil, mysqlSrc, cfg, effectiveTypeMapping(cfg))
+	if len(report.PartitioningChanges) != 2 {
+		t.Fatalf("partitioning changes = %+v, want 2", report.PartitioningChanges)
+	}
+	if pc := report.PartitioningChanges[0]; pc.Kind != "extended_key" || pc.Object != "PRIMARY KEY" {
+		t.Errorf("partitioning change 0 = %+v", pc)
+	}
+	if pc := report.PartitioningChanges[1]; pc.Kind != "dropped_foreign_key" || pc.Object != "event_notes.fk_notes_event" {
+		t.Errorf("partitioning change 1 = %+v", pc)
+	}
+
+	var buf bytes.Buffer
+	writePlanText(&buf, report)
+	if !strings.Contains(buf.String(), "## Partitioning Integrity Changes (2)") {
+		t.Fatalf("plan text missing partitioning changes section:\n%s", buf.String())
+	}
+}
+
+func TestBuildPlanReport_ForeignKeyCycles(t *testing.T) {
+	cfg := &MigrationConfig{TypeMapping: defaultTypeMappingConfig(), DeferrableForeignKeys: "cycles"}
+	schema := &Schema{
+		Tables: []Table{
+			{PGName: "users", ForeignKeys: []ForeignKey{{Name: "fk_users_org", RefPGTable: "orgs"}}},
+			{PGName: "orgs", ForeignKeys: []ForeignKey{{Name: "fk_orgs_owner", RefPGTable: "users"}}},
+		},
+	}
+
+	report := buildPlanReport(schema, nil, mysqlSrc, cfg, effectiveTypeMapping(cfg))
+	if len(report.ForeignKeyCycles) != 1 {
+		t.Fatalf("foreign key cycles = %d, want 1", len(report.ForeignKeyCycles))
+	}
+	if fc := report.ForeignKeyCycles[0]; !fc.Deferrable || len(fc.ForeignKeys) != 2 {
+		t.Fatalf("foreign key cycle = %+v", fc)
+	}
+
+	var buf bytes.Buffer
+	writePlanText(&buf, report)
+	if !strings.Contains(buf.String(), "## Foreign Key Cycles (1)") || !strings.Contains(buf.String(), "users ↔ orgs") {
+		t.Fatalf("plan text missing foreign key cycle section:\n%s", buf.String())
+	}
+}
@@ -13,7 +13,7 @@ import (
 )
 
 // postMigrate runs all post-migration steps in order:
-// 1. SET LOGGED, 2. PKs, 3. Indexes, 4. before_fk hooks, 5. orphan cleanup, 6. FKs, 7. Sequences, 8. optional triggers, 9. after_all hooks
+// 1. SET LOGGED, 2. PKs, 3. Indexes, 4. before_fk hooks, 5. orphan cleanup, 6. FKs, 7. Sequences, 8. CHECK constraints, 9. optional triggers, 10. views, 11. after_all hooks
 func postMigrate(ctx context.Context, pool *pgxpool.Pool, schema *Schema, cfg *MigrationConfig) error {
 	pgSchema := cfg.Schema
 	typeMap := effectiveTypeMapping(cfg)
@@ -21,7 +21,7 @@ func postMigrate(ctx context.Context, pool *pgxpool.Pool, schema *Schema, cfg *M
 	// data_only: skip all DDL steps, only reset sequences + after_all hooks
 	if cfg.DataOnly {
 		log.Printf("  sequences...")
-		if err := resetSequences(ctx, pool, schema, pgSchema); err != nil {
+		if err := resetSequences(ctx, pool, schema, pgSchema, cfg.IdentityMode); err != nil {
 			return fmt.Errorf("sequences: %w", err)
 		}
 		if err := loadAndExecSQLFiles(ctx, pool, cfg, cfg.Hooks.AfterAll, "after_all"); err != nil {
@@ -44,7 +44,11 @@ func postMigrate(ctx context.Context, pool *pgxpool.Pool, schema *Schema, cfg *M
 	}
 
 	log.Printf("  indexes...")
-	if err := addIndexes(ctx, pool, schema, pgSchema, cfg.IndexWorkers, typeMap); err != nil {
+	serverVersion, err := queryServerVersionNum(ctx, pool)
+	if err != nil {
+		return fmt.Errorf("indexes: %w", err)
+	}
+	if err := addIndexes(ctx, pool, schema, pgSchema, cfg.UniqueMode, cfg.IndexWorkers, typeMap, serverVersion); err != nil {
 		return fmt.Errorf("indexes: %w", err)
 	}
 
@@ -64,15 +68,20 @@ func postMigrate(ctx context.Context, pool *pgxpool.Pool, schema *Schema, cfg *M
 	}
 
 	log.Printf("  foreign keys...")
-	if err := addForeignKeys(ctx, pool, schema, pgSchema); err != nil {
+	if err := addForeignKeys(ctx, pool, schema, pgSchema, cfg.FKValidation, cfg.DeferrableForeignKeys, cfg.IndexWorkers); err != nil {
 		return fmt.Errorf("foreign keys: %w", err)
 	}
 
 	log.Printf("  sequences...")
-	if err := resetSequences(ctx, pool, schema, pgSchema); err != nil {
+	if err := resetSequences(ctx, pool, schema, pgSchema, cfg.IdentityMode); err != nil {
 		return fmt.Errorf("sequences: %w", err)
 	}
 
+	log.Printf("  check constraints...")
+	if err := addCheckConstraints(ctx, pool, schema, pgSchema, cfg.Source.Type, typeMap); err != nil {
+		return fmt.Errorf("check constraints: %w", err)
+	}
+
 	if cfg.AddUnsignedChecks {
 		log.Printf("  unsigned checks...")
 		if err := addUnsignedChecks(ctx, pool, schema, pgSchema, typeMap); err != nil {
@@ -91,6 +100,13 @@ func postMigrate(ctx context.Context, pool *pgxpool.Pool, schema *Schema, cfg *M
 		log.Printf("  triggers skipped (replicate_on_update_current_timestamp=false)")
 	}
 
+	if len(schema.Views) > 0 {
+		log.Printf("  views...")
+		if err := createViews(ctx, pool, schema.Views, pgSchema); err != nil {
+			return fmt.Errorf("views: %w", err)
+		}
+	}
+
 	// after_all hooks
 	if err := loadAndExecSQLFiles(ctx, pool, cfg, cfg.Hooks.AfterAll, "after_all"); err != nil {
 		return fmt.Errorf("after_all hooks: %w", err)
@@ -99,6 +115,53 @@ func postMigrate(ctx context.Context, pool *pgxpool.Pool, schema *Schema, cfg *M
 	return nil
 }
 
+// addCheckConstraints recreates translatable source CHECK constraints. Each is
+// added NOT VALID and then validated, matching the unsigned checks.
+func addCheckConstraints(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema, sourceType string, typeMap TypeMappingConfig) error {
+	var src SourceDB
+	added, skipped := 0, 0
+	for _, t := range schema.Tables {
+		for _, ck := range t.CheckConstraints {
+			if src == nil {
+				var err error
+				if src, err = newSourceDB(sourceType); err != nil {
+					return err
+				}
+			}
+			expr, err := translateCheckExpression(t, ck.Expression, src, typeMap)
+			if err != nil {
+				log.Printf("    skipping check constraint %s on %s.%s: %v", ck.SourceName, pgSchema, t.PGName, err)
+				skipped++
+				continue
+			}
+
+			constraintName := generatedCheckConstraintName(ck)
+			addSQL := fmt.Sprintf(
+				"ALTER TABLE %s.%s ADD CONSTRAINT %s CHECK (%s) NOT VALID",
+				pgIdent(pgSchema), pgIdent(t.PGName), pgIdent(constraintName), expr,
+			)
+			if err := execSQL(ctx, pool, constraintName, addSQL); err != nil {
+				return err
+			}
+
+			validateSQL := fmt.Sprintf(
+				"ALTER TABLE %s.%s VALIDATE CONSTRAINT %s",
+				pgIdent(pgSchema), pgIdent(t.PGName), pgIdent(constraintName),
+			)
+			if err := execSQL(ctx, pool, constraintName, validateSQL); err != nil {
+				return err
+			}
+
+			log.Printf("    check %s on %s.%s CHECK (%s)", constraintName, pgSchema, t.PGName, expr)
+			added++
+		}
+	}
+	if added == 0 {
+		log.Printf("    no check constraints to create (%d skipped)", skipped)
+	}
+	return nil
+}
+
 func addUnsignedChecks(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string, typeMap TypeMappingConfig) error {
 	for _, t := range schema.Tables {
 		for _, col := range t.Columns {
@@ -170,6 +233,10 @@ func generatedForeignKeyName(fk ForeignKey) string {
 	return truncateGeneratedIdentifier(fk.Name)
 }
 
+func generatedCheckConstraintName(ck CheckConstraint) string {
+	return truncateGeneratedIdentifier(ck.Name)
+}
+
 func generatedSequenceName(t Table, col Column) string {
 	return truncateGeneratedIdentifier(fmt.Sprintf("%s_%s_seq", t.PGName, col.PGName))
 }
@@ -210,12 +277,22 @@ func execSQL(ctx context.Context, pool *pgxpool.Pool, desc, query string) error
 	return nil
 }
 
-// setLogged converts all UNLOGGED tables back to LOGGED.
+// setLogged converts all UNLOGGED tables back to LOGGED. Partitioned tables
+// are converted partition by partition.
 func setLogged(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string) error {
 	for _, t := range schema.Tables {
-		q := fmt.Sprintf("ALTER TABLE %s.%s SET LOGGED", pgIdent(pgSchema), pgIdent(t.PGName))
-		if err := execSQL(ctx, pool, t.PGName, q); err != nil {
-			return err
+		names := []string{t.PGName}
+		if isPartitionedTable(t) {
+			names = names[:0]
+			for _, part := range t.Partitioning.Partitions {
+				names = append(names, part.Name)
+			}
+		}
+		for _, name := range names {
+			q := fmt.Sprintf("ALTER TABLE %s.%s SET LOGGED", pgIdent(pgSchema), pgIdent(name))
+			if err := execSQL(ctx, pool, name, q); err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -227,7 +304,7 @@ func addPrimaryKeys(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgS
 		if t.PrimaryKey == nil {
 			continue
 		}
-		cols := quotedColumnList(t.PrimaryKey.Columns)
+		cols := quotedColumnList(withPartitionKeyColumns(t, t.PrimaryKey.Columns))
 		q := fmt.Sprintf("ALTER TABLE %s.%s ADD PRIMARY KEY (%s)",
 			pgIdent(pgSchema), pgIdent(t.PGName), cols)
 		if err := execSQL(ctx, pool, t.PGName+" PK", q); err != nil {
@@ -240,8 +317,9 @@ func addPrimaryKeys(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgS
 
 // indexJob represents a single index creation task for the parallel worker pool.
 type indexJob struct {
-	table Table
-	index Index
+	table      Table
+	index      Index
+	constraint bool // create as a UNIQUE constraint (unique_mode = "constraint")
 }
 
 // planIndexJobs collects all supported index creation jobs and logs skipped indexes.
@@ -259,8 +337,65 @@ func planIndexJobs(schema *Schema, pgSchema string, typeMap TypeMappingConfig) (
 	return jobs, skipped
 }
 
-// createIndex executes a single CREATE INDEX statement.
-func createIndex(ctx context.Context, pool *pgxpool.Pool, pgSchema string, t Table, idx Index, typeMap TypeMappingConfig) error {
+// pgNullsNotDistinctVersion is the first server_version_num that accepts
+// NULLS NOT DISTINCT on unique indexes.
+const pgNullsNotDistinctVersion = 150000
+
+// queryServerVersionNum returns the target server_version_num (e.g. 150004).
+func queryServerVersionNum(ctx context.Context, pool *pgxpool.Pool) (int, error) {
+	var version int
+	if err := pool.QueryRow(ctx, "SELECT current_setting('server_version_num')::int").Scan(&version); err != nil {
+		return 0, fmt.Errorf("query server version: %w", err)
+	}
+	return version, nil
+}
+
+// uniqueConstraintUnsupportedReason reports why a unique index cannot back a
+// UNIQUE constraint: PostgreSQL constraints only cover plain ascending btree
+// columns without a predicate.
+func uniqueConstraintUnsupportedReason(idx Index) (string, bool) {
+	switch {
+	case idx.Type == "FULLTEXT" || idx.Type == "SPATIAL" || idx.Type == "HASH":
+		return fmt.Sprintf("%s index", strings.ToLower(idx.Type)), true
+	case idx.HasExpression || idx.HasPrefix:
+		return "expression or prefix key-parts", true
+	case idx.PGFilter != "":
+		return "partial index", true
+	}
+	for _, order := range idx.ColumnOrders {
+		if strings.EqualFold(order, "DESC") {
+			return "descending key-parts", true
+		}
+	}
+	return "", false
+}
+
+// uniqueConstraintStatement returns the ALTER TABLE statement that turns the
+// unique index idx into a UNIQUE constraint of the same name. Partitioned
+// tables do not support USING INDEX, so their constraint builds its own index.
+func uniqueConstraintStatement(pgSchema string, t Table, idx Index, serverVersion int) string {
+	name := pgIdent(generatedIndexName(t, idx))
+	table := fmt.Sprintf("%s.%s", pgIdent(pgSchema), pgIdent(t.PGName))
+	if !isPartitionedTable(t) {
+		return fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE USING INDEX %s", table, name, name)
+	}
+	q := fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s UNIQUE", table, name)
+	if idx.NullsNotDistinct && serverVersion >= pgNullsNotDistinctVersion {
+		q += " NULLS NOT DISTINCT"
+	}
+	q += fmt.Sprintf(" (%s)", quotedColumnList(withPartitionKeyColumns(t, idx.Columns)))
+	if len(idx.Include) > 0 {
+		q += fmt.Sprintf(" INCLUDE (%s)", quotedColumnList(idx.Include))
+	}
+	return q
+}
+
+// createIndex executes a single CREATE INDEX statement, and with constraint
+// set attaches the index as a UNIQUE constraint.
+func createIndex(ctx context.Context, pool *pgxpool.Pool, pgSchema string, t Table, idx Index, constraint bool, typeMap TypeMappingConfig, serverVersion int) error {
+	if idx.Type == "FULLTEXT" {
+		return createFullTextIndex(ctx, pool, pgSchema, t, idx, typeMap)
+	}
 	idxName := generatedIndexName(t, idx)
 
 	var q string
@@ -271,14 +406,30 @@ func createIndex(ctx context.Context, pool *pgxpool.Pool, pgSchema string, t Tab
 			pgIdent(idxName), pgIdent(pgSchema), pgIdent(t.PGName), col)
 		detail = fmt.Sprintf("USING GIST (%s)", col)
 	} else {
-		cols := quotedOrderedColumnList(idx.Columns, idx.ColumnOrders)
+		var err error
+		detail, err = indexDefinition(t, idx, typeMap, serverVersion)
+		if err != nil {
+			return fmt.Errorf("index %s: %w", idxName, err)
+		}
 		unique := ""
 		if idx.Unique {
 			unique = "UNIQUE "
 		}
-		q = fmt.Sprintf("CREATE %sINDEX %s ON %s.%s (%s)",
-			unique, pgIdent(idxName), pgIdent(pgSchema), pgIdent(t.PGName), cols)
-		detail = fmt.Sprintf("(%s)", cols)
+		q = fmt.Sprintf("CREATE %sINDEX %s ON %s.%s %s",
+			unique, pgIdent(idxName), pgIdent(pgSchema), pgIdent(t.PGName), detail)
+	}
+
+	if constraint {
+		if !isPartitionedTable(t) {
+			if err := execSQL(ctx, pool, idxName, q); err != nil {
+				return err
+			}
+		}
+		if err := execSQL(ctx, pool, idxName+" constraint", uniqueConstraintStatement(pgSchema, t, idx, serverVersion)); err != nil {
+			return err
+		}
+		log.Printf("    unique constraint %s on %s.%s %s", idxName, pgSchema, t.PGName, detail)
+		return nil
 	}
 
 	if err := execSQL(ctx, pool, idxName, q); err != nil {
@@ -288,19 +439,71 @@ func createIndex(ctx context.Context, pool *pgxpool.Pool, pgSchema string, t Tab
 	return nil
 }
 
-// addIndexes adds all non-primary indexes with bounded parallelism.
-func addIndexes(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string, workers int, typeMap TypeMappingConfig) error {
+// indexDefinition returns the part of a btree CREATE INDEX statement after
+// the table name: key list, INCLUDE columns, NULLS NOT DISTINCT (when the
+// server supports it) and WHERE predicate.
+func indexDefinition(t Table, idx Index, typeMap TypeMappingConfig, serverVersion int) (string, error) {
+	keys, err := indexKeys(t, idx, typeMap)
+	if err != nil {
+		return "", err
+	}
+	if idx.Unique {
+		for _, name := range withPartitionKeyColumns(t, idx.Columns)[len(idx.Columns):] {
+			keys = append(keys, pgIdent(name))
+		}
+	}
+	def := fmt.Sprintf("(%s)", strings.Join(keys, ", "))
+	if len(idx.Include) > 0 {
+		include := make([]string, len(idx.Include))
+		for i, name := range idx.Include {
+			include[i] = pgIdent(name)
+		}
+		def += fmt.Sprintf(" INCLUDE (%s)", strings.Join(include, ", "))
+	}
+	if idx.Unique && idx.NullsNotDistinct && serverVersion >= pgNullsNotDistinctVersion {
+		def += " NULLS NOT DISTINCT"
+	}
+	if idx.PGFilter != "" {
+		def += " WHERE " + idx.PGFilter
+	}
+	return def, nil
+}
+
+// addIndexes adds all non-primary indexes with bounded parallelism. With
+// uniqueMode "constraint", eligible unique indexes are attached as UNIQUE
+// constraints once built.
+func addIndexes(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema, uniqueMode string, workers int, typeMap TypeMappingConfig, serverVersion int) error {
 	jobs, skipped := planIndexJobs(schema, pgSchema, typeMap)
 	if len(jobs) == 0 {
 		log.Printf("    no indexes to create (%d skipped)", skipped)
 		return nil
 	}
+	if uniqueMode == "constraint" {
+		for i, j := range jobs {
+			if !j.index.Unique {
+				continue
+			}
+			if reason, unsupported := uniqueConstraintUnsupportedReason(j.index); unsupported {
+				log.Printf("    unique index %s on %s.%s stays an index: %s", j.index.SourceName, pgSchema, j.table.PGName, reason)
+				continue
+			}
+			jobs[i].constraint = true
+		}
+	}
+	if serverVersion < pgNullsNotDistinctVersion {
+		for _, j := range jobs {
+			if j.index.Unique && j.index.NullsNotDistinct {
+				log.Printf("    WARN: unique index %s on %s.%s covers nullable columns; PostgreSQL %d allows duplicate NULLs (NULLS NOT DISTINCT requires PostgreSQL 15+)",
+					j.index.SourceName, pgSchema, j.table.PGName, serverVersion/10000)
+			}
+		}
+	}
 
 	log.Printf("    creating %d index(es) with %d worker(s) (%d skipped)...", len(jobs), workers, skipped)
 	start := time.Now()
 
 	err := execIndexJobs(ctx, jobs, workers, func(ctx context.Context, j indexJob) error {
-		return createIndex(ctx, pool, pgSchema, j.table, j.index, typeMap)
+		return createIndex(ctx, pool, pgSchema, j.table, j.index, j.constraint, typeMap, serverVersion)
 	})
 
 	elapsed := time.Since(start).Round(time.Millisecond)
@@ -312,10 +515,11 @@ func addIndexes(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchem
 	return err
 }
 
-// execIndexJobs runs index creation jobs with bounded parallelism.
-// The exec callback is invoked for each job. When workers <= 1, jobs run
-// sequentially; otherwise they run in parallel with a semaphore.
-func execIndexJobs(ctx context.Context, jobs []indexJob, workers int, exec func(ctx context.Context, j indexJob) error) error {
+// execIndexJobs runs index creation (or foreign key validation) jobs with
+// bounded parallelism. The exec callback is invoked for each job. When
+// workers <= 1, jobs run sequentially; otherwise they run in parallel with a
+// semaphore.
+func execIndexJobs[J any](ctx context.Context, jobs []J, workers int, exec func(ctx context.Context, j J) error) error {
 	if workers <= 1 {
 		for _, job := range jobs {
 			if err := exec(ctx, job); err != nil {
@@ -341,7 +545,7 @@ func execIndexJobs(ctx context.Context, jobs []indexJob, workers int, exec func(
 
 	for _, job := range jobs {
 		wg.Add(1)
-		go func(j indexJob) {
+		go func(j J) {
 			defer wg.Done()
 			select {
 			case sem <- struct{}{}:
@@ -366,38 +570,128 @@ func execIndexJobs(ctx context.Context, jobs []indexJob, workers int, exec func(
 }
 
 // addForeignKeys adds all foreign key constraints from introspected data.
-func addForeignKeys(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string) error {
+// With fkValidation = "parallel" the constraints are added NOT VALID, which
+// skips the scan, and then validated table by table across workers
+// connections. VALIDATE CONSTRAINT only takes SHARE UPDATE EXCLUSIVE, which
+// does not block other tables' validation; it conflicts with itself, so
+// each job validates all constraints of one table. deferrableMode ("none",
+// "cycles", "all") selects the constraints created DEFERRABLE INITIALLY
+// DEFERRED.
+func addForeignKeys(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema, fkValidation, deferrableMode string, workers int) error {
+	deferred := deferrableForeignKeys(schema, deferrableMode)
+	var jobs []Table
 	for _, t := range schema.Tables {
+		// PostgreSQL rejects NOT VALID foreign keys on partitioned tables.
+		notValid := fkValidation == "parallel" && !isPartitionedTable(t)
 		for _, fk := range t.ForeignKeys {
-			localCols := quotedColumnList(fk.Columns)
-			refCols := quotedColumnList(fk.RefColumns)
 			fkName := generatedForeignKeyName(fk)
-			q := fmt.Sprintf(
-				"ALTER TABLE %s.%s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s.%s(%s) ON UPDATE %s ON DELETE %s",
-				pgIdent(pgSchema), pgIdent(t.PGName),
-				pgIdent(fkName),
-				localCols,
-				pgIdent(pgSchema), pgIdent(fk.RefPGTable),
-				refCols,
-				fk.UpdateRule, fk.DeleteRule,
-			)
-			if err := execSQL(ctx, pool, fkName, q); err != nil {
+			deferrable := deferred[t.PGName+"."+fk.Name]
+			if err := execSQL(ctx, pool, fkName, foreignKeyStatement(pgSchema, t, fk, deferrable, notValid)); err != nil {
 				return err
 			}
-			log.Printf("    fk %s on %s.%s → %s", fkName, pgSchema, t.PGName, fk.RefPGTable)
+			var notes []string
+			if deferrable {
+				notes = append(notes, "DEFERRABLE")
+			}
+			if notValid {
+				notes = append(notes, "NOT VALID")
+			}
+			if len(notes) > 0 {
+				log.Printf("    fk %s on %s.%s → %s (%s)", fkName, pgSchema, t.PGName, fk.RefPGTable, strings.Join(notes, ", "))
+			} else {
+				log.Printf("    fk %s on %s.%s → %s", fkName, pgSchema, t.PGName, fk.RefPGTable)
+			}
+		}
+		if notValid && len(t.ForeignKeys) > 0 {
+			jobs = append(jobs, t)
 		}
 	}
-	return nil
+	if len(jobs) == 0 {
+		return nil
+	}
+
+	log.Printf("    validating foreign keys of %d table(s) with %d worker(s)...", len(jobs), workers)
+	start := time.Now()
+	err := execIndexJobs(ctx, jobs, workers, func(ctx context.Context, t Table) error {
+		for _, fk := range t.ForeignKeys {
+			fkName := generatedForeignKeyName(fk)
+			q := fmt.Sprintf("ALTER TABLE %s.%s VALIDATE CONSTRAINT %s",
+				pgIdent(pgSchema), pgIdent(t.PGName), pgIdent(fkName))
+			if err := execSQL(ctx, pool, fkName+" validate", q); err != nil {
+				return err
+			}
+			log.Printf("    validated fk %s on %s.%s", fkName, pgSchema, t.PGName)
+		}
+		return nil
+	})
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		log.Printf("    foreign key validation failed after %s", elapsed)
+	} else {
+		log.Printf("    foreign key validation completed in %s", elapsed)
+	}
+	return err
+}
+
+// foreignKeyStatement returns the ALTER TABLE ... ADD CONSTRAINT statement
+// for fk, optionally DEFERRABLE INITIALLY DEFERRED and NOT VALID.
+func foreignKeyStatement(pgSchema string, t Table, fk ForeignKey, deferrable, notValid bool) string {
+	q := fmt.Sprintf(
+		"ALTER TABLE %s.%s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s.%s(%s) ON UPDATE %s ON DELETE %s",
+		pgIdent(pgSchema), pgIdent(t.PGName),
+		pgIdent(generatedForeignKeyName(fk)),
+		quotedColumnList(fk.Columns),
+		pgIdent(pgSchema), pgIdent(fk.RefPGTable),
+		quotedColumnList(fk.RefColumns),
+		fk.UpdateRule, fk.DeleteRule,
+	)
+	if deferrable {
+		q += " DEFERRABLE INITIALLY DEFERRED"
+	}
+	if notValid {
+		q += " NOT VALID"
+	}
+	return q
 }
 
 // resetSequences resets auto-increment sequences by finding columns with auto_increment
-// and setting the sequence to max(col)+1.
-func resetSequences(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema string) error {
+// and setting the sequence to max(col)+1. With identity_mode = "identity" the
+// columns become identity columns instead of using a free-standing sequence.
+// Standalone source sequences continue from their last source value.
+func resetSequences(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgSchema, identityMode string) error {
 	for _, t := range schema.Tables {
 		for _, col := range t.Columns {
 			if !strings.Contains(col.Extra, "auto_increment") {
 				continue
 			}
+			if t.AutoIncrementStep < 0 {
+				log.Printf("    WARN: %s.%s counts down (increment %d); the PostgreSQL sequence counts up from MAX+1",
+					t.PGName, col.PGName, t.AutoIncrementStep)
+			}
+			useIdentity := identityMode == "identity"
+			if useIdentity && col.Nullable {
+				// Identity columns must be NOT NULL; a nullable column that
+				// already holds NULLs keeps a plain sequence.
+				hasNulls, err := columnHasNulls(ctx, pool, pgSchema, t, col)
+				if err != nil {
+					return fmt.Errorf("check NULLs in %s.%s: %w", t.PGName, col.PGName, err)
+				}
+				if hasNulls {
+					log.Printf("    WARN: %s.%s contains NULLs and cannot become an identity column; using a sequence instead",
+						t.PGName, col.PGName)
+					useIdentity = false
+				}
+			}
+			if useIdentity {
+				desc := fmt.Sprintf("identity %s.%s", t.PGName, col.PGName)
+				for _, q := range identityColumnStatements(pgSchema, t, col) {
+					if err := execSQL(ctx, pool, desc, q); err != nil {
+						return err
+					}
+				}
+				log.Printf("    identity %s.%s.%s reset", pgSchema, t.PGName, col.PGName)
+				continue
+			}
 			seqName := generatedSequenceName(t, col)
 			stmts := resetSequenceStatements(pgSchema, t, col)
 			for _, q := range stmts {
@@ -408,21 +702,91 @@ func resetSequences(ctx context.Context, pool *pgxpool.Pool, schema *Schema, pgS
 			log.Printf("    sequence %s.%s reset", pgSchema, seqName)
 		}
 	}
+	for _, seq := range schema.Sequences {
+		if seq.LastValue == nil {
+			continue
+		}
+		if err := execSQL(ctx, pool, seq.PGName, standaloneSequenceSetval(pgSchema, seq)); err != nil {
+			return err
+		}
+		log.Printf("    sequence %s.%s set to %d", pgSchema, seq.PGName, *seq.LastValue)
+	}
 	return nil
 }
 
+// standaloneSequenceSetval carries the last value handed out by a source
+// sequence over, so the next nextval() continues after it.
+func standaloneSequenceSetval(pgSchema string, seq Sequence) string {
+	return fmt.Sprintf("SELECT setval(%s, %d, true)", pgQualifiedRegclassLiteral(pgSchema, seq.PGName), *seq.LastValue)
+}
+
 func resetSequenceStatements(pgSchema string, t Table, col Column) []string {
 	seqName := generatedSequenceName(t, col)
 	seqRef := pgQualifiedRegclassLiteral(pgSchema, seqName)
 
-	return []string{
+	stmts := []string{
 		fmt.Sprintf("CREATE SEQUENCE IF NOT EXISTS %s", pgQualifiedIdent(pgSchema, seqName)),
-		fmt.Sprintf("SELECT setval(%s, COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
-			seqRef,
-			pgIdent(col.PGName), pgQualifiedIdent(pgSchema, t.PGName)),
+	}
+	if step := autoIncrementStep(t); step > 1 {
+		stmts = append(stmts, fmt.Sprintf("ALTER SEQUENCE %s INCREMENT BY %d", pgQualifiedIdent(pgSchema, seqName), step))
+	}
+	return append(stmts,
+		fmt.Sprintf("SELECT setval(%s, %s, false)", seqRef, autoIncrementNextValue(pgSchema, t, col)),
 		fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET DEFAULT nextval(%s)",
 			pgQualifiedIdent(pgSchema, t.PGName), pgIdent(col.PGName), seqRef),
+	)
+}
+
+// columnHasNulls reports whether the target column holds any NULL.
+func columnHasNulls(ctx context.Context, pool *pgxpool.Pool, pgSchema string, t Table, col Column) (bool, error) {
+	var found bool
+	err := pool.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE %s IS NULL)",
+		pgQualifiedIdent(pgSchema, t.PGName), pgIdent(col.PGName))).Scan(&found)
+	return found, err
+}
+
+// identityColumnStatements turns col into a GENERATED BY DEFAULT AS IDENTITY
+// column, unless it already is one (schema_only then data_only runs), and
+// restarts its sequence at the next source value. Nullable columns (MySQL
+// allows them when a unique key covers the column) are made NOT NULL first.
+func identityColumnStatements(pgSchema string, t Table, col Column) []string {
+	table := pgQualifiedIdent(pgSchema, t.PGName)
+	setNotNull := ""
+	if col.Nullable {
+		setNotNull = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL; ", table, pgIdent(col.PGName))
+	}
+	stmts := []string{
+		fmt.Sprintf(`DO $$ BEGIN IF NOT EXISTS (SELECT 1 FROM pg_attribute WHERE attrelid = %s AND attname = %s AND attidentity <> '') THEN %sALTER TABLE %s ALTER COLUMN %s ADD GENERATED BY DEFAULT AS IDENTITY; END IF; END $$`,
+			pgQualifiedRegclassLiteral(pgSchema, t.PGName), pgLiteral(col.PGName), setNotNull, table, pgIdent(col.PGName)),
+	}
+	if step := autoIncrementStep(t); step > 1 {
+		stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET INCREMENT BY %d", table, pgIdent(col.PGName), step))
+	}
+	return append(stmts,
+		fmt.Sprintf("SELECT setval(pg_get_serial_sequence(%s, %s), %s, false)",
+			pgLiteral(table), pgLiteral(col.PGName), autoIncrementNextValue(pgSchema, t, col)),
+	)
+}
+
+// autoIncrementNextValue is the SQL for the next value the sequence of col
+// hands out: MAX(col)+1, or the source counter when it is ahead (rows were
+// deleted), so IDs are never reused.
+func autoIncrementNextValue(pgSchema string, t Table, col Column) string {
+	next := fmt.Sprintf("COALESCE((SELECT MAX(%s) FROM %s), 0) + 1",
+		pgIdent(col.PGName), pgQualifiedIdent(pgSchema, t.PGName))
+	if t.AutoIncrementNext > 0 && autoIncrementStep(t) > 0 {
+		next = fmt.Sprintf("GREATEST(%s, %d)", next, t.AutoIncrementNext)
+	}
+	return next
+}
+
+// autoIncrementStep returns the source increment, 1 when unknown. Negative
+// MSSQL increments are returned as is; callers only apply positive steps.
+func autoIncrementStep(t Table) int64 {
+	if t.AutoIncrementStep == 0 {
+		return 1
 	}
+	return t.AutoIncrementStep
 }
 
 func pgQualifiedIdent(schema, name string) string {
@@ -0,0 +1,32 @@
+package main
+
+import "testing"
+
+func TestForeignKeyStatement(t *testing.T) {
+	table := Table{PGName: "order_items"}
+	fk := ForeignKey{
+		Name:       "fk_items_order",
+		Columns:    []string{"order_id"},
+		RefPGTable: "orders",
+		RefColumns: []string{"id"},
+		UpdateRule: "CASCADE",
+		DeleteRule: "RESTRICT",
+	}
+	name := generatedForeignKeyName(fk)
+
+	got := foreignKeyStatement("app", table, fk, false, false)
+	want := `ALTER TABLE "app"."order_items" ADD CONSTRAINT "` + name + `" FOREIGN KEY ("order_id") REFERENCES "app"."orders"("id") ON UPDATE CASCADE ON DELETE RESTRICT`
+	if got != want {
+		t.Errorf("foreignKeyStatement() = %s, want %s", got, want)
+	}
+
+	got = foreignKeyStatement("app", table, fk, false, true)
+	if got != want+" NOT VALID" {
+		t.Errorf("foreignKeyStatement(notValid) = %s, want %s NOT VALID", got, want)
+	}
+
+	got = foreignKeyStatement("app", table, fk, true, true)
+	if got != want+" DEFERRABLE INITIALLY DEFERRED NOT VALID" {
+		t.Errorf("foreignKeyStatement(deferrable, notValid) = %s, want %s DEFERRABLE INITIALLY DEFERRED NOT VALID", got, want)
+	}
+}
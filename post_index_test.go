@@ -12,10 +12,11 @@ func TestPlanIndexJobs_SkipsUnsupported(t *testing.T) {
 		{
 			SourceName: "posts",
 			PGName:     "posts",
+			Columns:    []Column{{PGName: "rank", DataType: "int", ColumnType: "int"}},
 			Indexes: []Index{
 				{Name: "idx_title", SourceName: "idx_title", Type: "BTREE", Columns: []string{"title"}},
 				{Name: "idx_body_ft", SourceName: "idx_body_ft", Type: "FULLTEXT", Columns: []string{"body"}},
-				{Name: "idx_prefix", SourceName: "idx_prefix", Type: "BTREE", Columns: []string{"name"}, HasPrefix: true},
+				{Name: "idx_prefix", SourceName: "idx_prefix", Type: "BTREE", Columns: []string{"rank"}, HasPrefix: true, PrefixLengths: []int{4}},
 			},
 		},
 		{
@@ -241,3 +242,97 @@ func TestExecIndexJobs_ContextAlreadyCancelled(t *testing.T) {
 		t.Fatalf("expected context.Canceled, got: %v", err)
 	}
 }
+
+func TestIndexDefinition(t *testing.T) {
+	table := Table{
+		PGName: "orders",
+		Columns: []Column{
+			{PGName: "account_id", DataType: "int"},
+			{PGName: "code", DataType: "nvarchar"},
+			{PGName: "status", DataType: "int"},
+		},
+	}
+	tests := []struct {
+		name    string
+		idx     Index
+		version int
+		want    string
+	}{
+		{
+			name:    "include and filter",
+			idx:     Index{Columns: []string{"account_id"}, Include: []string{"status"}, PGFilter: `("status" > (0))`},
+			version: 160002,
+			want:    `("account_id") INCLUDE ("status") WHERE ("status" > (0))`,
+		},
+		{
+			name:    "nulls not distinct",
+			idx:     Index{Columns: []string{"code"}, Unique: true, NullsNotDistinct: true},
+			version: 150004,
+			want:    `("code") NULLS NOT DISTINCT`,
+		},
+		{
+			name:    "nulls not distinct before 15",
+			idx:     Index{Columns: []string{"code"}, Unique: true, NullsNotDistinct: true},
+			version: 140011,
+			want:    `("code")`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := indexDefinition(table, tt.idx, defaultTypeMappingConfig(), tt.version)
+			if err != nil {
+				t.Fatalf("indexDefinition() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("indexDefinition() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUniqueConstraintUnsupportedReason(t *testing.T) {
+	tests := []struct {
+		name        string
+		idx         Index
+		unsupported bool
+	}{
+		{"plain", Index{Type: "BTREE", Unique: true, Columns: []string{"email"}, Include: []string{"name"}}, false},
+		{"hash", Index{Type: "HASH", Unique: true, Columns: []string{"email"}}, true},
+		{"expression", Index{Type: "BTREE", Unique: true, HasExpression: true}, true},
+		{"prefix", Index{Type: "BTREE", Unique: true, HasPrefix: true}, true},
+		{"partial", Index{Type: "BTREE", Unique: true, Columns: []string{"email"}, PGFilter: "deleted_at IS NULL"}, true},
+		{"descending", Index{Type: "BTREE", Unique: true, Columns: []string{"a", "b"}, ColumnOrders: []string{"ASC", "DESC"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, unsupported := uniqueConstraintUnsupportedReason(tt.idx); unsupported != tt.unsupported {
+				t.Errorf("uniqueConstraintUnsupportedReason() unsupported = %v, want %v", unsupported, tt.unsupported)
+			}
+		})
+	}
+}
+
+func TestUniqueConstraintStatement(t *testing.T) {
+	idx := Index{Name: "uq_email", Type: "BTREE", Unique: true, Columns: []string{"email"}, NullsNotDistinct: true}
+	table := Table{PGName: "users"}
+
+	got := uniqueConstraintStatement("app", table, idx, 150000)
+	want := `ALTER TABLE "app"."users" ADD CONSTRAINT "users_uq_email" UNIQUE USING INDEX "users_uq_email"`
+	if got != want {
+		t.Errorf("uniqueConstraintStatement() = %s, want %s", got, want)
+	}
+
+	table.Partitioning = &Partitioning{Method: "HASH", Columns: []string{"tenant_id"}, PGKey: `"tenant_id"`}
+	idx.Include = []string{"name"}
+	got = uniqueConstraintStatement("app", table, idx, 150000)
+	want = `ALTER TABLE "app"."users" ADD CONSTRAINT "users_uq_email" UNIQUE NULLS NOT DISTINCT ("email", "tenant_id") INCLUDE ("name")`
+	if got != want {
+		t.Errorf("uniqueConstraintStatement(partitioned) = %s, want %s", got, want)
+	}
+
+	got = uniqueConstraintStatement("app", table, idx, 140000)
+	want = `ALTER TABLE "app"."users" ADD CONSTRAINT "users_uq_email" UNIQUE ("email", "tenant_id") INCLUDE ("name")`
+	if got != want {
+		t.Errorf("uniqueConstraintStatement(partitioned, PG14) = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,383 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/cobra"
+)
+
+var repairConfigPath string
+var repairFrom string
+var repairTables []string
+var repairMode string
+var repairDryRun bool
+
+var repairCmd = &cobra.Command{
+	Use:   "repair [migration.toml]",
+	Short: "Re-copy tables or chunks that failed validation",
+	Long: `Delete and re-copy the target rows of every mismatched table or checksum
+chunk, using the same type mapping as the migration, then re-validate what
+was repaired. Each table or chunk is replaced in one transaction, so a failed
+copy leaves the existing rows in place.
+
+Mismatches are read from a validation report (pgferry validate --format json)
+given with --from, or found by running validation first (checksum mode by
+default, which pinpoints individual chunks).
+
+Triggers on repaired tables are disabled while rows are replaced. Exits with a
+non-zero status if anything still mismatches after repair.`,
+	Args:         cobra.MaximumNArgs(1),
+	RunE:         runRepair,
+	SilenceUsage: true,
+}
+
+func init() {
+	repairCmd.Flags().StringVar(&repairConfigPath, "config", "", "path to migration TOML config file")
+	repairCmd.Flags().StringVar(&repairFrom, "from", "", "validation report JSON from 'pgferry validate --format json' (default: run validation now)")
+	repairCmd.Flags().StringSliceVar(&repairTables, "tables", nil, "comma-separated source or PostgreSQL table names to consider (default: all)")
+	repairCmd.Flags().StringVar(&repairMode, "mode", "checksum", "validation mode used to find mismatches when --from is not given")
+	repairCmd.Flags().BoolVar(&repairDryRun, "dry-run", false, "print what would be repaired without changing the target")
+}
+
+// repairTarget is one unit of repair work: a whole table, or specific
+// checksum ranges of a chunkable table.
+type repairTarget struct {
+	Table  Table
+	Key    *ChunkKey       // nil for whole-table repair
+	Ranges []checksumRange // empty for whole-table repair
+}
+
+func (rt repairTarget) describe() string {
+	if rt.Key == nil {
+		return fmt.Sprintf("%s: full table", rt.Table.SourceName)
+	}
+	return fmt.Sprintf("%s: %d chunk(s) by %s", rt.Table.SourceName, len(rt.Ranges), rt.Key.SourceColumn)
+}
+
+func runRepair(cmd *cobra.Command, args []string) error {
+	cfgPath := repairConfigPath
+	if len(args) > 0 {
+		cfgPath = args[0]
+	}
+	if cfgPath == "" {
+		return fmt.Errorf("config file required: pgferry repair <migration.toml> or pgferry repair --config <migration.toml>")
+	}
+
+	cfg, err := loadConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+
+	mode, err := resolveValidateMode(repairMode, cfg.Validation)
+	if err != nil {
+		return err
+	}
+
+	var report *ValidationReport
+	if repairFrom != "" {
+		report, err = readValidationReport(repairFrom)
+		if err != nil {
+			return err
+		}
+	}
+
+	return runRepairWithConfig(cfg, mode, report, repairTables, repairDryRun, cmd.OutOrStdout())
+}
+
+func readValidationReport(path string) (*ValidationReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read validation report: %w", err)
+	}
+	var report ValidationReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("parse validation report: %w", err)
+	}
+	return &report, nil
+}
+
+func runRepairWithConfig(cfg *MigrationConfig, mode string, report *ValidationReport, tables []string, dryRun bool, out io.Writer) error {
+	ctx := context.Background()
+
+	src, err := newConfiguredSourceDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("pgferry repair — %s → PostgreSQL", src.Name())
+
+	schema, err := introspectSourceSchema(ctx, src, cfg)
+	if err != nil {
+		return err
+	}
+	schema, err = filterSchemaTables(schema, tables)
+	if err != nil {
+		return err
+	}
+
+	pgPool, err := pgxpool.New(ctx, cfg.Target.DSN)
+	if err != nil {
+		return fmt.Errorf("connect postgres: %w", err)
+	}
+	defer pgPool.Close()
+
+	if err := pgPool.Ping(ctx); err != nil {
+		return fmt.Errorf("ping postgres: %w", err)
+	}
+	if err := loadTargetPartitions(ctx, pgPool, cfg.Schema, schema); err != nil {
+		return err
+	}
+	if err := matchTargetGeneratedColumns(ctx, pgPool, schema, cfg.Schema); err != nil {
+		return err
+	}
+
+	typeMap := effectiveTypeMapping(cfg)
+	vcfg := validationConfig{
+		Src:        src,
+		SrcDSN:     cfg.Source.DSN,
+		Pool:       pgPool,
+		Schema:     schema,
+		PGSchema:   cfg.Schema,
+		Mode:       mode,
+		Workers:    cfg.Workers,
+		TypeMap:    typeMap,
+		ChunkSize:  cfg.ChunkSize,
+		SampleSize: cfg.ValidationSampleSize,
+		Quiet:      true,
+	}
+
+	if report == nil {
+		log.Printf("running validation (mode=%s) to find mismatches...", mode)
+		results, err := validateMigration(ctx, vcfg)
+		if results == nil && err != nil {
+			return err
+		}
+		report = buildValidationReport(mode, cfg.Schema, results)
+	} else {
+		if report.Schema != "" && report.Schema != cfg.Schema {
+			return fmt.Errorf("validation report is for schema %q, config targets %q", report.Schema, cfg.Schema)
+		}
+		if report.Mode != "" {
+			vcfg.Mode = report.Mode
+		}
+	}
+
+	targets := buildRepairTargets(schema, report.Tables, src)
+	if len(targets) == 0 {
+		fmt.Fprintln(out, "nothing to repair: no mismatches found")
+		return nil
+	}
+
+	for _, rt := range targets {
+		fmt.Fprintf(out, "repair %s\n", rt.describe())
+	}
+	if dryRun {
+		return nil
+	}
+
+	srcDB, err := src.OpenDB(cfg.Source.DSN)
+	if err != nil {
+		return fmt.Errorf("open source for repair: %w", err)
+	}
+	defer srcDB.Close()
+	srcDB.SetMaxOpenConns(1)
+
+	affected := &Schema{}
+	for _, rt := range targets {
+		affected.Tables = append(affected.Tables, rt.Table)
+	}
+	log.Printf("disabling triggers on %d table(s)...", len(affected.Tables))
+	if err := setTriggers(ctx, pgPool, affected, cfg.Schema, false); err != nil {
+		return fmt.Errorf("disable triggers: %w", err)
+	}
+	defer func() {
+		log.Printf("re-enabling triggers...")
+		if err := setTriggers(ctx, pgPool, affected, cfg.Schema, true); err != nil {
+			log.Printf("WARN: enable triggers: %v", err)
+		}
+	}()
+
+	failed := 0
+	for _, rt := range targets {
+		if err := repairTable(ctx, src, srcDB, pgPool, cfg.Schema, typeMap, rt); err != nil {
+			return fmt.Errorf("repair %s: %w", rt.Table.SourceName, err)
+		}
+		ok, err := revalidateRepairTarget(ctx, vcfg, srcDB, rt)
+		if err != nil {
+			return fmt.Errorf("re-validate %s: %w", rt.Table.SourceName, err)
+		}
+		if ok {
+			fmt.Fprintf(out, "OK: %s\n", rt.describe())
+		} else {
+			failed++
+			fmt.Fprintf(out, "STILL MISMATCHED: %s\n", rt.describe())
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("repair: %d of %d target(s) still mismatch after re-copy", failed, len(targets))
+	}
+	return nil
+}
+
+// buildRepairTargets turns failed validation results into repair work. Tables
+// with chunk-level checksum mismatches are repaired per chunk; any other
+// mismatch (row count, sample, aggregate) re-copies the whole table.
+func buildRepairTargets(schema *Schema, results []ValidationResult, src SourceDB) []repairTarget {
+	bySource := make(map[string]Table, len(schema.Tables))
+	for _, t := range schema.Tables {
+		bySource[t.SourceName] = t
+	}
+
+	var targets []repairTarget
+	for _, r := range results {
+		if r.passed() {
+			continue
+		}
+		t, ok := bySource[r.Table]
+		if !ok {
+			// Filtered out by --tables, or no longer present in the source.
+			continue
+		}
+
+		rt := repairTarget{Table: t}
+		key := chunkKeyForTable(t, src)
+		if key != nil && r.ChunkKey == key.SourceColumn && len(r.ChunkMismatches) > 0 && r.chunksExplainMismatch() {
+			rt.Key = key
+			for _, m := range r.ChunkMismatches {
+				rt.Ranges = append(rt.Ranges, m.checksumRange())
+			}
+		}
+		targets = append(targets, rt)
+	}
+	return targets
+}
+
+// chunksExplainMismatch reports whether every difference in r is covered by
+// its chunk mismatches, so repairing only those chunks is sufficient.
+func (r ValidationResult) chunksExplainMismatch() bool {
+	return len(r.RowDiffs) == 0 && len(r.AggregateMismatches) == 0
+}
+
+// checksumRange converts a reported mismatch back into the range it covers.
+func (m ChunkChecksumMismatch) checksumRange() checksumRange {
+	r := checksumRange{Index: m.ChunkIndex}
+	if m.LowerBound != nil {
+		r.Lower, r.HasLower = *m.LowerBound, true
+	}
+	if m.UpperBound != nil {
+		r.Upper, r.HasUpper = *m.UpperBound, true
+	}
+	return r
+}
+
+// repairChunk maps a checksum range onto a copy chunk. Open ends become the
+// int64 limits so the re-copy covers the same keys the delete removed.
+func repairChunk(r checksumRange) Chunk {
+	c := Chunk{Index: r.Index, LowerBound: math.MinInt64, UpperBound: math.MaxInt64, IsLast: true}
+	if r.HasLower {
+		c.LowerBound = r.Lower
+	}
+	if r.HasUpper {
+		c.UpperBound = r.Upper
+		c.IsLast = false
+	}
+	return c
+}
+
+func buildRepairDeleteQuery(pgSchema string, table Table, key *ChunkKey, r checksumRange) string {
+	q := "DELETE FROM " + pgQualifiedIdent(pgSchema, table.PGName)
+	if key != nil {
+		if where := checksumRangePredicate(pgIdent(key.PGColumn), r); where != "" {
+			q += " WHERE " + where
+		}
+	}
+	return q
+}
+
+// txBeginner starts a target transaction; *pgxpool.Pool satisfies it.
+type txBeginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// repairTable deletes and re-copies the target rows covered by rt. Each table
+// or range is replaced in its own transaction, so a failed copy rolls back
+// the delete and leaves the existing rows in place.
+func repairTable(ctx context.Context, src SourceDB, source dbQuerier, target txBeginner, pgSchema string, typeMap TypeMappingConfig, rt repairTarget) error {
+	if rt.Key == nil {
+		return replaceTargetRows(ctx, target, buildRepairDeleteQuery(pgSchema, rt.Table, nil, checksumRange{}), func(tx pgx.Tx) error {
+			_, err := migrateTableFromSourceFull(ctx, src, source, tx, rt.Table, pgSchema, typeMap)
+			return err
+		})
+	}
+
+	for _, r := range rt.Ranges {
+		err := replaceTargetRows(ctx, target, buildRepairDeleteQuery(pgSchema, rt.Table, rt.Key, r), func(tx pgx.Tx) error {
+			_, err := migrateChunkFromSource(ctx, src, source, tx, rt.Table, pgSchema, typeMap, *rt.Key, repairChunk(r))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("chunk %d: %w", r.Index, err)
+		}
+	}
+	return nil
+}
+
+// replaceTargetRows runs deleteQuery and then copyRows in one transaction.
+func replaceTargetRows(ctx context.Context, target txBeginner, deleteQuery string, copyRows func(tx pgx.Tx) error) error {
+	tx, err := target.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, deleteQuery); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	if err := copyRows(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit: %w", err)
+	}
+	return nil
+}
+
+// revalidateRepairTarget checks only what was repaired: the re-copied checksum
+// ranges, or the whole table with the original validation mode.
+func revalidateRepairTarget(ctx context.Context, cfg validationConfig, source validationSource, rt repairTarget) (bool, error) {
+	if rt.Key == nil {
+		result, err := validateTable(ctx, cfg, source, rt.Table)
+		if err != nil {
+			return false, err
+		}
+		logValidationResult(result)
+		return result.passed(), nil
+	}
+
+	families, err := checksumColumnFamilies(rt.Table, cfg.Src, cfg.TypeMap)
+	if err != nil {
+		return false, err
+	}
+	ok := true
+	for _, r := range rt.Ranges {
+		srcDigest, tgtDigest, err := checksumRangeDigests(ctx, cfg, source, rt.Table, families, rt.Key, r)
+		if err != nil {
+			return false, err
+		}
+		if srcDigest != tgtDigest {
+			m := newChunkChecksumMismatch(r, srcDigest, tgtDigest)
+			log.Printf("  MISMATCH: %s — %s: source rows=%d, target rows=%d", rt.Table.SourceName, m.describe(rt.Key.SourceColumn), srcDigest.Rows, tgtDigest.Rows)
+			ok = false
+		}
+	}
+	return ok, nil
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func repairTestSchema() *Schema {
+	return &Schema{Tables: []Table{
+		{
+			SourceName: "users",
+			PGName:     "users",
+			Columns:    []Column{{SourceName: "id", PGName: "id", DataType: "int"}},
+			PrimaryKey: &Index{Columns: []string{"id"}, IsPrimary: true},
+		},
+		{
+			SourceName: "tags",
+			PGName:     "tags",
+			Columns:    []Column{{SourceName: "name", PGName: "name", DataType: "varchar"}},
+		},
+	}}
+}
+
+func TestBuildRepairTargets(t *testing.T) {
+	lower, upper := int64(100), int64(200)
+	results := []ValidationResult{
+		{
+			Table:           "users",
+			CountMatch:      true,
+			ChunkKey:        "id",
+			ChunkMismatches: []ChunkChecksumMismatch{{ChunkIndex: 1, LowerBound: &lower, UpperBound: &upper}, {ChunkIndex: 2, LowerBound: &upper}},
+		},
+		{Table: "tags", SourceCount: 5, TargetCount: 4},
+		{Table: "dropped", SourceCount: 1, TargetCount: 0},
+	}
+
+	targets := buildRepairTargets(repairTestSchema(), results, &mysqlSourceDB{})
+	if len(targets) != 2 {
+		t.Fatalf("len(targets) = %d, want 2", len(targets))
+	}
+
+	users := targets[0]
+	if users.Key == nil || users.Key.SourceColumn != "id" {
+		t.Fatalf("users should be repaired by chunk, got key %+v", users.Key)
+	}
+	if len(users.Ranges) != 2 {
+		t.Fatalf("users ranges = %d, want 2", len(users.Ranges))
+	}
+	if r := users.Ranges[1]; !r.HasLower || r.HasUpper || r.Lower != 200 {
+		t.Errorf("open-ended range = %+v, want lower 200 with no upper bound", r)
+	}
+
+	if tags := targets[1]; tags.Key != nil || tags.describe() != "tags: full table" {
+		t.Errorf("tags should be a full-table repair, got %q", tags.describe())
+	}
+}
+
+func TestBuildRepairTargets_SampleMismatchRepairsWholeTable(t *testing.T) {
+	results := []ValidationResult{{
+		Table:      "users",
+		CountMatch: true,
+		RowDiffs:   []SampleRowDiff{{Key: "id=1", Column: "id", Source: "'1'", Target: "'2'"}},
+	}}
+
+	targets := buildRepairTargets(repairTestSchema(), results, &mysqlSourceDB{})
+	if len(targets) != 1 || targets[0].Key != nil {
+		t.Fatalf("expected one full-table repair, got %+v", targets)
+	}
+}
+
+func TestRepairChunk(t *testing.T) {
+	tests := []struct {
+		r    checksumRange
+		want Chunk
+	}{
+		{checksumRange{Index: 1, Lower: 10, Upper: 20, HasLower: true, HasUpper: true}, Chunk{Index: 1, LowerBound: 10, UpperBound: 20}},
+		{checksumRange{Index: 0, Upper: 20, HasUpper: true}, Chunk{Index: 0, LowerBound: math.MinInt64, UpperBound: 20}},
+		{checksumRange{Index: 3, Lower: 30, HasLower: true}, Chunk{Index: 3, LowerBound: 30, UpperBound: math.MaxInt64, IsLast: true}},
+	}
+	for _, tt := range tests {
+		if got := repairChunk(tt.r); got != tt.want {
+			t.Errorf("repairChunk(%+v) = %+v, want %+v", tt.r, got, tt.want)
+		}
+	}
+}
+
+func TestBuildRepairDeleteQuery(t *testing.T) {
+	table := repairTestSchema().Tables[0]
+	key := &ChunkKey{SourceColumn: "id", PGColumn: "id"}
+
+	got := buildRepairDeleteQuery("app", table, key, checksumRange{Lower: 100, Upper: 200, HasLower: true, HasUpper: true})
+	if want := `DELETE FROM "app"."users" WHERE "id" >= 100 AND "id" < 200`; got != want {
+		t.Errorf("ranged delete = %q, want %q", got, want)
+	}
+
+	got = buildRepairDeleteQuery("app", table, nil, checksumRange{})
+	if want := `DELETE FROM "app"."users"`; got != want {
+		t.Errorf("full delete = %q, want %q", got, want)
+	}
+}
+
+func TestReadValidationReport_RoundTrip(t *testing.T) {
+	lower := int64(5)
+	report := buildValidationReport("checksum", "app", []ValidationResult{{
+		Table:           "users",
+		CountMatch:      true,
+		ChunkKey:        "id",
+		ChunkMismatches: []ChunkChecksumMismatch{{ChunkIndex: 4, LowerBound: &lower}},
+	}})
+
+	var buf bytes.Buffer
+	if err := writeValidationJSON(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := os.WriteFile(path, buf.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := readValidationReport(path)
+	if err != nil {
+		t.Fatalf("readValidationReport() error: %v", err)
+	}
+	if got.Mode != "checksum" || got.Schema != "app" || got.Passed {
+		t.Errorf("report header = %+v", got)
+	}
+	r := got.Tables[0].ChunkMismatches[0].checksumRange()
+	if r.Index != 4 || !r.HasLower || r.Lower != 5 || r.HasUpper {
+		t.Errorf("decoded range = %+v", r)
+	}
+}
+
+// fakeRepairTarget is a one-table target with transactional semantics: rows
+// change only when a transaction commits.
+type fakeRepairTarget struct {
+	rows      []string
+	copyErr   error
+	commits   int
+	rollbacks int
+}
+
+func (f *fakeRepairTarget) Begin(context.Context) (pgx.Tx, error) {
+	return &fakeRepairTx{target: f, rows: slices.Clone(f.rows)}, nil
+}
+
+type fakeRepairTx struct {
+	pgx.Tx
+	target *fakeRepairTarget
+	rows   []string
+	done   bool
+}
+
+func (tx *fakeRepairTx) Exec(_ context.Context, sql string, _ ...any) (pgconn.CommandTag, error) {
+	if strings.HasPrefix(sql, "DELETE") {
+		tx.rows = nil
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (tx *fakeRepairTx) CopyFrom(_ context.Context, _ pgx.Identifier, _ []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	var n int64
+	for rowSrc.Next() {
+		values, err := rowSrc.Values()
+		if err != nil {
+			return n, err
+		}
+		tx.rows = append(tx.rows, fmt.Sprint(values[0]))
+		n++
+	}
+	if err := rowSrc.Err(); err != nil {
+		return n, err
+	}
+	return n, tx.target.copyErr
+}
+
+func (tx *fakeRepairTx) Commit(context.Context) error {
+	tx.target.rows = tx.rows
+	tx.target.commits++
+	tx.done = true
+	return nil
+}
+
+func (tx *fakeRepairTx) Rollback(context.Context) error {
+	if !tx.done {
+		tx.target.rollbacks++
+		tx.done = true
+	}
+	return nil
+}
+
+func TestRepairTable_FailedCopyKeepsRows(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "source.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY); INSERT INTO users VALUES (1), (2), (3)`); err != nil {
+		t.Fatalf("seed source: %v", err)
+	}
+	table := Table{
+		SourceName: "users",
+		PGName:     "users",
+		Columns:    []Column{{SourceName: "id", PGName: "id", DataType: "integer", ColumnType: "INTEGER"}},
+	}
+	rt := repairTarget{Table: table}
+	src := &sqliteSourceDB{}
+	typeMap := defaultTypeMappingConfig()
+
+	target := &fakeRepairTarget{rows: []string{"1", "2"}, copyErr: errors.New("connection reset")}
+	if err := repairTable(context.Background(), src, db, target, "app", typeMap, rt); err == nil {
+		t.Fatal("repairTable() error = nil, want copy error")
+	}
+	if target.commits != 0 || target.rollbacks != 1 || strings.Join(target.rows, ",") != "1,2" {
+		t.Fatalf("after failed copy: rows=%v commits=%d rollbacks=%d, want rows 1,2 rolled back", target.rows, target.commits, target.rollbacks)
+	}
+
+	// A failing source read rolls back the delete the same way.
+	badTable := table
+	badTable.SourceName = "missing"
+	target = &fakeRepairTarget{rows: []string{"1", "2"}}
+	if err := repairTable(context.Background(), src, db, target, "app", typeMap, repairTarget{Table: badTable}); err == nil {
+		t.Fatal("repairTable() error = nil, want source read error")
+	}
+	if target.commits != 0 || strings.Join(target.rows, ",") != "1,2" {
+		t.Fatalf("after failed read: rows=%v commits=%d, want rows 1,2 kept", target.rows, target.commits)
+	}
+
+	target = &fakeRepairTarget{rows: []string{"1", "2"}}
+	if err := repairTable(context.Background(), src, db, target, "app", typeMap, rt); err != nil {
+		t.Fatalf("repairTable() error: %v", err)
+	}
+	if target.commits != 1 || strings.Join(target.rows, ",") != "1,2,3" {
+		t.Fatalf("after repair: rows=%v commits=%d, want rows 1,2,3 committed", target.rows, target.commits)
+	}
+}
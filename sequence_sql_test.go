@@ -53,3 +53,70 @@ func TestResetSequenceStatements_QuotesReservedColumnName(t *testing.T) {
 		t.Fatalf("nextval statement = %q", stmts[2])
 	}
 }
+
+func TestIdentityColumnStatements(t *testing.T) {
+	table := Table{PGName: "events"}
+	col := Column{PGName: "id", Extra: "auto_increment"}
+
+	stmts := identityColumnStatements("order", table, col)
+	if len(stmts) != 2 {
+		t.Fatalf("statement count = %d, want 2", len(stmts))
+	}
+	if !strings.Contains(stmts[0], `attrelid = '"order"."events"'::regclass AND attname = 'id' AND attidentity <> ''`) ||
+		!strings.Contains(stmts[0], `ALTER TABLE "order"."events" ALTER COLUMN "id" ADD GENERATED BY DEFAULT AS IDENTITY`) {
+		t.Fatalf("identity statement = %q", stmts[0])
+	}
+	if !strings.Contains(stmts[1], `SELECT setval(pg_get_serial_sequence('"order"."events"', 'id'), COALESCE((SELECT MAX("id") FROM "order"."events"), 0) + 1, false)`) {
+		t.Fatalf("setval statement = %q", stmts[1])
+	}
+}
+
+func TestIdentityColumnStatements_NullableColumn(t *testing.T) {
+	table := Table{PGName: "events"}
+	col := Column{PGName: "seq", Extra: "auto_increment", Nullable: true}
+
+	stmts := identityColumnStatements("app", table, col)
+	want := `THEN ALTER TABLE "app"."events" ALTER COLUMN "seq" SET NOT NULL; ALTER TABLE "app"."events" ALTER COLUMN "seq" ADD GENERATED BY DEFAULT AS IDENTITY;`
+	if !strings.Contains(stmts[0], want) {
+		t.Fatalf("identity statement = %q, want SET NOT NULL before ADD IDENTITY", stmts[0])
+	}
+
+	col.Nullable = false
+	if stmts := identityColumnStatements("app", table, col); strings.Contains(stmts[0], "SET NOT NULL") {
+		t.Fatalf("NOT NULL column should not be altered: %q", stmts[0])
+	}
+}
+
+func TestResetSequenceStatements_SourceCounterAndStep(t *testing.T) {
+	table := Table{PGName: "events", AutoIncrementNext: 1045, AutoIncrementStep: 5}
+	col := Column{PGName: "id", Extra: "auto_increment"}
+
+	stmts := resetSequenceStatements("app", table, col)
+	if len(stmts) != 4 {
+		t.Fatalf("statement count = %d, want 4", len(stmts))
+	}
+	if stmts[1] != `ALTER SEQUENCE "app"."events_id_seq" INCREMENT BY 5` {
+		t.Fatalf("increment statement = %q", stmts[1])
+	}
+	if !strings.Contains(stmts[2], `GREATEST(COALESCE((SELECT MAX("id") FROM "app"."events"), 0) + 1, 1045), false)`) {
+		t.Fatalf("setval statement = %q", stmts[2])
+	}
+
+	stmts = identityColumnStatements("app", table, col)
+	if len(stmts) != 3 || stmts[1] != `ALTER TABLE "app"."events" ALTER COLUMN "id" SET INCREMENT BY 5` {
+		t.Fatalf("identity statements = %q", stmts)
+	}
+	if !strings.Contains(stmts[2], `GREATEST(`) {
+		t.Fatalf("identity setval statement = %q", stmts[2])
+	}
+}
+
+func TestResetSequenceStatements_NegativeStepIgnoresCounter(t *testing.T) {
+	table := Table{PGName: "events", AutoIncrementNext: -20, AutoIncrementStep: -1}
+	col := Column{PGName: "id", Extra: "auto_increment"}
+
+	stmts := resetSequenceStatements("app", table, col)
+	if len(stmts) != 3 || strings.Contains(stmts[1], "GREATEST") {
+		t.Fatalf("statements = %q, want MAX+1 without source counter", stmts)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"database/sql"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"log"
 	"net/url"
@@ -154,14 +155,36 @@ func (m *mssqlSourceDB) IntrospectSchema(db *sql.DB, _ string) (*Schema, error)
 		return nil, fmt.Errorf("introspect indexes for schema %s: %w", m.sourceSchema, err)
 	}
 
+	fullTextByTable, err := introspectMSSQLFullTextIndexesByTable(db, m.sourceSchema, m.identName)
+	if err != nil {
+		return nil, fmt.Errorf("introspect full-text indexes for schema %s: %w", m.sourceSchema, err)
+	}
+
 	foreignKeysByTable, err := introspectMSSQLForeignKeysByTable(db, m.sourceSchema, m.identName)
 	if err != nil {
 		return nil, fmt.Errorf("introspect foreign keys for schema %s: %w", m.sourceSchema, err)
 	}
 
+	checksByTable, err := introspectMSSQLCheckConstraintsByTable(db, m.sourceSchema, m.identName)
+	if err != nil {
+		return nil, fmt.Errorf("introspect check constraints for schema %s: %w", m.sourceSchema, err)
+	}
+
+	sequences, err := introspectMSSQLSequences(db, m.sourceSchema, m.identName)
+	if err != nil {
+		return nil, fmt.Errorf("introspect sequences for schema %s: %w", m.sourceSchema, err)
+	}
+	sequenceNames := make(map[string]string, len(sequences))
+	for _, seq := range sequences {
+		sequenceNames[strings.ToLower(seq.SourceName)] = seq.PGName
+	}
+
 	for i := range tables {
 		t := &tables[i]
 		t.Columns = columnsByTable[t.SourceName]
+		for j := range t.Columns {
+			resolveMSSQLSequenceDefault(t, &t.Columns[j], m.sourceSchema, sequenceNames)
+		}
 		for _, idx := range indexesByTable[t.SourceName] {
 			if idx.IsPrimary {
 				pk := idx
@@ -170,17 +193,26 @@ func (m *mssqlSourceDB) IntrospectSchema(db *sql.DB, _ string) (*Schema, error)
 				t.Indexes = append(t.Indexes, idx)
 			}
 		}
+		t.Indexes = append(t.Indexes, fullTextByTable[t.SourceName]...)
 		t.ForeignKeys = foreignKeysByTable[t.SourceName]
+		t.CheckConstraints = checksByTable[t.SourceName]
 	}
 
-	return &Schema{Tables: tables}, nil
+	return &Schema{Tables: tables, Sequences: sequences}, nil
 }
 
 func introspectMSSQLTables(db *sql.DB, schema string, identName func(string) string) ([]Table, error) {
 	rows, err := db.Query(`
-		SELECT t.name
+		SELECT t.name, COALESCE(CAST(ep.value AS nvarchar(max)), '') AS comment,
+			CAST(idc.seed_value AS bigint), CAST(idc.increment_value AS bigint),
+			CAST(idc.last_value AS bigint)
 		FROM sys.tables t
 		JOIN sys.schemas s ON t.schema_id = s.schema_id
+		LEFT JOIN sys.identity_columns idc ON idc.object_id = t.object_id
+		LEFT JOIN sys.extended_properties ep ON ep.class = 1
+			AND ep.major_id = t.object_id
+			AND ep.minor_id = 0
+			AND ep.name = 'MS_Description'
 		WHERE s.name = @p1
 		  AND t.is_ms_shipped = 0
 		ORDER BY t.name`,
@@ -193,18 +225,154 @@ func introspectMSSQLTables(db *sql.DB, schema string, identName func(string) str
 
 	var tables []Table
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
+		var name, comment string
+		var seed, increment, last sql.NullInt64
+		if err := rows.Scan(&name, &comment, &seed, &increment, &last); err != nil {
 			return nil, err
 		}
-		tables = append(tables, Table{
+		t := Table{
 			SourceName: name,
 			PGName:     identName(name),
-		})
+			Comment:    comment,
+		}
+		// last_value is NULL until the first row is inserted; the first
+		// value handed out is then the seed.
+		if increment.Valid {
+			t.AutoIncrementStep = increment.Int64
+			t.AutoIncrementNext = seed.Int64
+			if last.Valid {
+				t.AutoIncrementNext = last.Int64 + increment.Int64
+			}
+		}
+		tables = append(tables, t)
 	}
 	return tables, rows.Err()
 }
 
+// mssqlErrInvalidColumn is "Invalid column name", returned for
+// sys.sequences.last_used_value on servers older than SQL Server 2017.
+const mssqlErrInvalidColumn = 207
+
+const mssqlSequencesQuery = `
+	SELECT sq.name, TYPE_NAME(sq.system_type_id),
+		CAST(sq.start_value AS bigint), CAST(sq.increment AS bigint),
+		CASE WHEN CAST(sq.minimum_value AS decimal(38, 0)) >= -9223372036854775808
+			THEN CAST(sq.minimum_value AS bigint) END,
+		CASE WHEN CAST(sq.maximum_value AS decimal(38, 0)) <= 9223372036854775807
+			THEN CAST(sq.maximum_value AS bigint) END,
+		sq.is_cycling,
+		%s
+	FROM sys.sequences sq
+	JOIN sys.schemas s ON sq.schema_id = s.schema_id
+	WHERE s.name = @p1
+	ORDER BY sq.name`
+
+// introspectMSSQLSequences reads the standalone sequences of schema.
+func introspectMSSQLSequences(db *sql.DB, schema string, identName func(string) string) ([]Sequence, error) {
+	rows, err := db.Query(fmt.Sprintf(mssqlSequencesQuery, "CAST(sq.last_used_value AS bigint)"), schema)
+	if err != nil {
+		var numbered interface{ SQLErrorNumber() int32 }
+		if !errors.As(err, &numbered) || numbered.SQLErrorNumber() != mssqlErrInvalidColumn {
+			return nil, err
+		}
+		// Without last_used_value the sequence restarts at its START WITH.
+		rows, err = db.Query(fmt.Sprintf(mssqlSequencesQuery, "NULL"), schema)
+		if err != nil {
+			return nil, err
+		}
+	}
+	defer rows.Close()
+
+	var sequences []Sequence
+	for rows.Next() {
+		var seq Sequence
+		var minValue, maxValue, lastValue sql.NullInt64
+		if err := rows.Scan(&seq.SourceName, &seq.DataType, &seq.Start, &seq.Increment,
+			&minValue, &maxValue, &seq.Cycle, &lastValue); err != nil {
+			return nil, err
+		}
+		seq.PGName = identName(seq.SourceName)
+		if minValue.Valid {
+			seq.MinValue = &minValue.Int64
+		}
+		if maxValue.Valid {
+			seq.MaxValue = &maxValue.Int64
+		}
+		if lastValue.Valid {
+			seq.LastValue = &lastValue.Int64
+		}
+		sequences = append(sequences, seq)
+	}
+	return sequences, rows.Err()
+}
+
+// resolveMSSQLSequenceDefault points a NEXT VALUE FOR default at the migrated
+// sequence. Sequences outside the migrated schema cannot be referenced; their
+// defaults are dropped with a warning.
+func resolveMSSQLSequenceDefault(t *Table, col *Column, sourceSchema string, sequenceNames map[string]string) {
+	if col.Default == nil {
+		return
+	}
+	seqSchema, seqName, ok := parseMSSQLNextValueFor(*col.Default)
+	if !ok {
+		return
+	}
+	if seqSchema == "" || strings.EqualFold(seqSchema, sourceSchema) {
+		if pgName, found := sequenceNames[strings.ToLower(seqName)]; found {
+			col.DefaultSequence = pgName
+			return
+		}
+	}
+	log.Printf("    WARN: default NEXT VALUE FOR %s on %s.%s references a sequence outside schema %s; default dropped",
+		seqName, t.SourceName, col.SourceName, sourceSchema)
+}
+
+// parseMSSQLNextValueFor extracts the sequence of a "NEXT VALUE FOR
+// [schema].[seq]" default expression.
+func parseMSSQLNextValueFor(def string) (schema, name string, ok bool) {
+	fields := strings.Fields(mssqlStripParens(strings.TrimSpace(def)))
+	if len(fields) < 4 || !strings.EqualFold(fields[0], "next") ||
+		!strings.EqualFold(fields[1], "value") || !strings.EqualFold(fields[2], "for") {
+		return "", "", false
+	}
+	ref := strings.Join(fields[3:], " ")
+
+	var parts []string
+	var b strings.Builder
+	inBracket := false
+	for i := 0; i < len(ref); i++ {
+		c := ref[i]
+		switch {
+		case inBracket && c == ']':
+			if i+1 < len(ref) && ref[i+1] == ']' {
+				b.WriteByte(']')
+				i++
+				continue
+			}
+			inBracket = false
+		case !inBracket && c == '[':
+			inBracket = true
+		case !inBracket && c == '.':
+			parts = append(parts, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	if inBracket {
+		return "", "", false
+	}
+	parts = append(parts, b.String())
+	switch len(parts) {
+	case 1:
+		return "", parts[0], parts[0] != ""
+	case 2:
+		return parts[0], parts[1], parts[1] != ""
+	default:
+		return "", "", false
+	}
+}
+
 func introspectMSSQLColumnsByTable(db *sql.DB, schema string, identName func(string) string) (map[string][]Column, error) {
 	rows, err := db.Query(`
 		SELECT
@@ -219,7 +387,8 @@ func introspectMSSQLColumnsByTable(db *sql.DB, schema string, identName func(str
 			c.is_identity,
 			CASE WHEN cc.column_id IS NOT NULL THEN 1 ELSE 0 END AS is_computed,
 			COALESCE(cc.definition, '') AS computed_def,
-			c.column_id
+			c.column_id,
+			COALESCE(CAST(ep.value AS nvarchar(max)), '') AS comment
 		FROM sys.columns c
 		JOIN sys.tables t ON c.object_id = t.object_id
 		JOIN sys.schemas s ON t.schema_id = s.schema_id
@@ -229,6 +398,10 @@ func introspectMSSQLColumnsByTable(db *sql.DB, schema string, identName func(str
 		LEFT JOIN sys.default_constraints dc ON c.default_object_id = dc.object_id
 		LEFT JOIN sys.computed_columns cc ON c.object_id = cc.object_id
 			AND c.column_id = cc.column_id
+		LEFT JOIN sys.extended_properties ep ON ep.class = 1
+			AND ep.major_id = c.object_id
+			AND ep.minor_id = c.column_id
+			AND ep.name = 'MS_Description'
 		WHERE s.name = @p1
 		  AND c.is_hidden = 0
 		ORDER BY t.name, c.column_id`,
@@ -254,11 +427,12 @@ func introspectMSSQLColumnsByTable(db *sql.DB, schema string, identName func(str
 			isComputed  int
 			computedDef string
 			columnID    int
+			comment     string
 		)
 		if err := rows.Scan(
 			&tableName, &name, &baseType, &maxLength, &precision, &scale,
 			&isNullable, &defaultDef, &isIdentity, &isComputed,
-			&computedDef, &columnID,
+			&computedDef, &columnID, &comment,
 		); err != nil {
 			return nil, err
 		}
@@ -272,6 +446,7 @@ func introspectMSSQLColumnsByTable(db *sql.DB, schema string, identName func(str
 			Scale:      int64(scale),
 			Nullable:   isNullable,
 			OrdinalPos: columnID,
+			Comment:    comment,
 		}
 
 		// Handle max_length for character/binary types
@@ -323,9 +498,10 @@ func introspectMSSQLIndexesByTable(db *sql.DB, schema string, identName func(str
 			i.is_unique,
 			i.is_primary_key,
 			i.type_desc,
-			i.has_filter,
+			i.filter_definition,
 			ic.key_ordinal,
 			c.name AS column_name,
+			c.is_nullable,
 			ic.is_descending_key,
 			ic.is_included_column
 		FROM sys.indexes i
@@ -353,15 +529,16 @@ func introspectMSSQLIndexesByTable(db *sql.DB, schema string, identName func(str
 			isUnique      bool
 			isPrimary     bool
 			typeDesc      string
-			hasFilter     bool
+			filter        sql.NullString
 			keyOrdinal    int
 			colName       string
+			isNullable    bool
 			isDescending  bool
 			isIncludedCol bool
 		)
 		if err := rows.Scan(
 			&tableName, &idxName, &isUnique, &isPrimary, &typeDesc,
-			&hasFilter, &keyOrdinal, &colName, &isDescending,
+			&filter, &keyOrdinal, &colName, &isNullable, &isDescending,
 			&isIncludedCol,
 		); err != nil {
 			return nil, err
@@ -392,18 +569,23 @@ func introspectMSSQLIndexesByTable(db *sql.DB, schema string, identName func(str
 				log.Printf("    WARN: %s index %q on %s will be skipped (not supported in PostgreSQL)", typeDesc, idxName, tableName)
 			}
 
-			// Filtered indexes → skip
-			if hasFilter {
-				idx.HasExpression = true
-				log.Printf("    WARN: filtered index %q on %s will be skipped (WHERE clause not migrated)", idxName, tableName)
+			// Filtered indexes → partial indexes; the predicate is
+			// translated before index creation.
+			if filter.Valid {
+				idx.Filter = filter.String
 			}
 		}
 
-		// Skip included columns (not key columns)
 		if isIncludedCol {
+			idx.Include = append(idx.Include, identName(colName))
 			continue
 		}
 
+		// SQL Server unique indexes admit a single NULL; PostgreSQL needs
+		// NULLS NOT DISTINCT for the same behaviour.
+		if isUnique && !isPrimary && isNullable {
+			idx.NullsNotDistinct = true
+		}
 		idx.Columns = append(idx.Columns, identName(colName))
 		if isDescending {
 			idx.ColumnOrders = append(idx.ColumnOrders, "DESC")
@@ -426,6 +608,61 @@ func introspectMSSQLIndexesByTable(db *sql.DB, schema string, identName func(str
 	return indexesByTable, nil
 }
 
+// introspectMSSQLFullTextIndexesByTable reads full-text catalog indexes, one
+// per table at most, as FULLTEXT indexes over their character columns.
+// Document columns (varbinary, image, xml) hold files that PostgreSQL cannot
+// parse and are left out with a warning.
+func introspectMSSQLFullTextIndexesByTable(db *sql.DB, schema string, identName func(string) string) (map[string][]Index, error) {
+	rows, err := db.Query(`
+		SELECT t.name AS table_name, c.name AS column_name, TYPE_NAME(c.system_type_id) AS type_name
+		FROM sys.fulltext_indexes fi
+		JOIN sys.fulltext_index_columns fic ON fi.object_id = fic.object_id
+		JOIN sys.columns c ON fic.object_id = c.object_id AND fic.column_id = c.column_id
+		JOIN sys.tables t ON fi.object_id = t.object_id
+		JOIN sys.schemas s ON t.schema_id = s.schema_id
+		WHERE s.name = @p1
+		ORDER BY t.name, fic.column_id`,
+		schema,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byTable := make(map[string]*Index)
+	var order []string
+	for rows.Next() {
+		var tableName, colName, typeName string
+		if err := rows.Scan(&tableName, &colName, &typeName); err != nil {
+			return nil, err
+		}
+		idx := byTable[tableName]
+		if idx == nil {
+			name := "FT_" + tableName
+			idx = &Index{Name: identName(name), SourceName: name, Type: "FULLTEXT"}
+			byTable[tableName] = idx
+			order = append(order, tableName)
+		}
+		switch strings.ToLower(typeName) {
+		case "char", "varchar", "nchar", "nvarchar", "text", "ntext":
+		default:
+			log.Printf("    WARN: full-text column %s.%s (%s) will not be searchable (document columns are not migrated)", tableName, colName, typeName)
+			continue
+		}
+		idx.Columns = append(idx.Columns, identName(colName))
+		idx.ColumnOrders = append(idx.ColumnOrders, "ASC")
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	indexesByTable := make(map[string][]Index, len(order))
+	for _, tableName := range order {
+		indexesByTable[tableName] = []Index{*byTable[tableName]}
+	}
+	return indexesByTable, nil
+}
+
 type mssqlForeignKeysForTable struct {
 	fkMap map[string]*ForeignKey
 	order []string
@@ -507,16 +744,50 @@ func introspectMSSQLForeignKeysByTable(db *sql.DB, schema string, identName func
 	return fksByTable, nil
 }
 
+// introspectMSSQLCheckConstraintsByTable reads enabled CHECK constraints.
+// Disabled constraints are skipped because existing rows may violate them.
+func introspectMSSQLCheckConstraintsByTable(db *sql.DB, schema string, identName func(string) string) (map[string][]CheckConstraint, error) {
+	rows, err := db.Query(`
+		SELECT t.name AS table_name, cc.name, cc.definition
+		FROM sys.check_constraints cc
+		JOIN sys.tables t ON cc.parent_object_id = t.object_id
+		JOIN sys.schemas s ON t.schema_id = s.schema_id
+		WHERE s.name = @p1
+		  AND cc.is_disabled = 0
+		ORDER BY t.name, cc.name`,
+		schema,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checksByTable := make(map[string][]CheckConstraint)
+	for rows.Next() {
+		var tableName, name, definition string
+		if err := rows.Scan(&tableName, &name, &definition); err != nil {
+			return nil, err
+		}
+		checksByTable[tableName] = append(checksByTable[tableName], CheckConstraint{
+			Name:       identName(name),
+			SourceName: name,
+			Expression: definition,
+		})
+	}
+	return checksByTable, rows.Err()
+}
+
 // --- Source objects introspection ---
 
 func (m *mssqlSourceDB) IntrospectSourceObjects(db *sql.DB, _ string) (*SourceObjects, error) {
-	objs := &SourceObjects{}
+	objs := &SourceObjects{ViewDefinitions: map[string]string{}, SchemaName: m.sourceSchema}
 
-	// Views
+	// Views; definition is NULL for encrypted views
 	viewRows, err := db.Query(`
-		SELECT v.name
+		SELECT v.name, COALESCE(sm.definition, '') AS definition
 		FROM sys.views v
 		JOIN sys.schemas s ON v.schema_id = s.schema_id
+		LEFT JOIN sys.sql_modules sm ON sm.object_id = v.object_id
 		WHERE s.name = @p1
 		ORDER BY v.name`,
 		m.sourceSchema,
@@ -526,21 +797,26 @@ func (m *mssqlSourceDB) IntrospectSourceObjects(db *sql.DB, _ string) (*SourceOb
 	}
 	defer viewRows.Close()
 	for viewRows.Next() {
-		var name string
-		if err := viewRows.Scan(&name); err != nil {
+		var name, definition string
+		if err := viewRows.Scan(&name, &definition); err != nil {
 			return nil, err
 		}
 		objs.Views = append(objs.Views, name)
+		if definition != "" {
+			objs.ViewDefinitions[name] = definition
+		}
 	}
 	if err := viewRows.Err(); err != nil {
 		return nil, err
 	}
 
 	// Procedures and functions
+	objs.RoutineDefinitions = map[string]string{}
 	routineRows, err := db.Query(`
-		SELECT o.type_desc, o.name
+		SELECT o.type_desc, o.name, COALESCE(sm.definition, '') AS definition
 		FROM sys.objects o
 		JOIN sys.schemas s ON o.schema_id = s.schema_id
+		LEFT JOIN sys.sql_modules sm ON sm.object_id = o.object_id
 		WHERE s.name = @p1
 		  AND o.type IN ('P', 'FN', 'IF', 'TF')
 		ORDER BY o.type, o.name`,
@@ -551,22 +827,33 @@ func (m *mssqlSourceDB) IntrospectSourceObjects(db *sql.DB, _ string) (*SourceOb
 	}
 	defer routineRows.Close()
 	for routineRows.Next() {
-		var typeDesc, name string
-		if err := routineRows.Scan(&typeDesc, &name); err != nil {
+		var typeDesc, name, definition string
+		if err := routineRows.Scan(&typeDesc, &name, &definition); err != nil {
 			return nil, err
 		}
-		objs.Routines = append(objs.Routines, fmt.Sprintf("%s %s", typeDesc, name))
+		label := fmt.Sprintf("%s %s", typeDesc, name)
+		objs.Routines = append(objs.Routines, label)
+		if definition != "" {
+			objs.RoutineDefinitions[label] = definition
+		}
 	}
 	if err := routineRows.Err(); err != nil {
 		return nil, err
 	}
 
-	// Triggers
+	// Triggers; T-SQL triggers fire once per statement and may handle
+	// several events.
+	objs.TriggerDefinitions = map[string]TriggerDefinition{}
 	triggerRows, err := db.Query(`
-		SELECT tr.name
+		SELECT tr.name, o.name, tr.is_instead_of_trigger,
+			OBJECTPROPERTY(tr.object_id, 'ExecIsInsertTrigger'),
+			OBJECTPROPERTY(tr.object_id, 'ExecIsUpdateTrigger'),
+			OBJECTPROPERTY(tr.object_id, 'ExecIsDeleteTrigger'),
+			COALESCE(sm.definition, '') AS definition
 		FROM sys.triggers tr
 		JOIN sys.objects o ON tr.parent_id = o.object_id
 		JOIN sys.schemas s ON o.schema_id = s.schema_id
+		LEFT JOIN sys.sql_modules sm ON sm.object_id = tr.object_id
 		WHERE s.name = @p1
 		ORDER BY tr.name`,
 		m.sourceSchema,
@@ -577,10 +864,29 @@ func (m *mssqlSourceDB) IntrospectSourceObjects(db *sql.DB, _ string) (*SourceOb
 	defer triggerRows.Close()
 	for triggerRows.Next() {
 		var name string
-		if err := triggerRows.Scan(&name); err != nil {
+		var def TriggerDefinition
+		var insteadOf bool
+		var onInsert, onUpdate, onDelete sql.NullInt64
+		if err := triggerRows.Scan(&name, &def.Table, &insteadOf, &onInsert, &onUpdate, &onDelete, &def.Definition); err != nil {
 			return nil, err
 		}
 		objs.Triggers = append(objs.Triggers, name)
+		if def.Definition == "" {
+			continue
+		}
+		def.Timing = "AFTER"
+		if insteadOf {
+			def.Timing = "INSTEAD OF"
+		}
+		for _, ev := range []struct {
+			name string
+			set  sql.NullInt64
+		}{{"INSERT", onInsert}, {"UPDATE", onUpdate}, {"DELETE", onDelete}} {
+			if ev.set.Int64 == 1 {
+				def.Events = append(def.Events, ev.name)
+			}
+		}
+		objs.TriggerDefinitions[name] = def
 	}
 	if err := triggerRows.Err(); err != nil {
 		return nil, err
@@ -756,6 +1062,12 @@ func mssqlMapDefault(col Column, pgType string, _ TypeMappingConfig) (string, er
 
 	lower := strings.ToLower(raw)
 
+	// NEXT VALUE FOR defaults are emitted from Column.DefaultSequence, which
+	// knows the target schema; unresolved ones are dropped.
+	if _, _, ok := parseMSSQLNextValueFor(raw); ok {
+		return "", nil
+	}
+
 	// Function mapping
 	switch lower {
 	case "getdate()", "sysdatetime()", "sysutcdatetime()", "sysdatetimeoffset()", "getutcdate()":
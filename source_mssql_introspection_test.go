@@ -105,46 +105,55 @@ func (s *mssqlIntrospectionStub) query(query string, args []driver.NamedValue) (
 	switch {
 	case strings.Contains(normalized, "FROM sys.tables t"):
 		return &mssqlStubRows{
-			columns: []string{"name"},
+			columns: []string{"name", "comment", "seed_value", "increment_value", "last_value"},
 			data: [][]driver.Value{
-				{"Accounts"},
-				{"AuditTrail"},
-				{"OrderVersions"},
+				{"Accounts", "Customer login accounts", int64(1000), int64(5), int64(1040)},
+				{"AuditTrail", "", nil, nil, nil},
+				{"OrderVersions", "", nil, nil, nil},
 			},
 		}, nil
 	case strings.Contains(normalized, "FROM sys.columns c"):
 		return &mssqlStubRows{
 			columns: []string{
 				"table_name", "name", "base_type", "max_length", "precision", "scale",
-				"is_nullable", "default_def", "is_identity", "is_computed", "computed_def", "column_id",
+				"is_nullable", "default_def", "is_identity", "is_computed", "computed_def", "column_id", "comment",
 			},
 			data: [][]driver.Value{
-				{"Accounts", "AccountID", "int", int64(4), int64(10), int64(0), false, nil, true, int64(0), "", int64(1)},
-				{"Accounts", "UserName", "nvarchar", int64(128), int64(0), int64(0), false, nil, false, int64(0), "", int64(2)},
-				{"AuditTrail", "OrderID", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(1)},
-				{"AuditTrail", "VersionNo", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(2)},
-				{"AuditTrail", "ActorAccountID", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(3)},
-				{"OrderVersions", "OrderID", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(1)},
-				{"OrderVersions", "VersionNo", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(2)},
-				{"OrderVersions", "AccountID", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(3)},
-				{"OrderVersions", "StatusText", "nvarchar", int64(64), int64(0), int64(0), false, "('new')", false, int64(0), "", int64(4)},
-				{"OrderVersions", "DisplayLabel", "nvarchar", int64(128), int64(0), int64(0), true, nil, false, int64(1), "([OrderID]+'-'+[VersionNo])", int64(5)},
+				{"Accounts", "AccountID", "int", int64(4), int64(10), int64(0), false, nil, true, int64(0), "", int64(1), ""},
+				{"Accounts", "UserName", "nvarchar", int64(128), int64(0), int64(0), false, nil, false, int64(0), "", int64(2), "Login name"},
+				{"AuditTrail", "OrderID", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(1), ""},
+				{"AuditTrail", "VersionNo", "int", int64(4), int64(10), int64(0), false, "(NEXT VALUE FOR [dbo].[AuditVersionSeq])", false, int64(0), "", int64(2), ""},
+				{"AuditTrail", "ActorAccountID", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(3), ""},
+				{"OrderVersions", "OrderID", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(1), ""},
+				{"OrderVersions", "VersionNo", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(2), ""},
+				{"OrderVersions", "AccountID", "int", int64(4), int64(10), int64(0), false, nil, false, int64(0), "", int64(3), ""},
+				{"OrderVersions", "StatusText", "nvarchar", int64(64), int64(0), int64(0), false, "('new')", false, int64(0), "", int64(4), ""},
+				{"OrderVersions", "DisplayLabel", "nvarchar", int64(128), int64(0), int64(0), true, nil, false, int64(1), "([OrderID]+'-'+[VersionNo])", int64(5), ""},
 			},
 		}, nil
 	case strings.Contains(normalized, "FROM sys.indexes i"):
 		return &mssqlStubRows{
 			columns: []string{
-				"table_name", "index_name", "is_unique", "is_primary_key", "type_desc", "has_filter",
-				"key_ordinal", "column_name", "is_descending_key", "is_included_column",
+				"table_name", "index_name", "is_unique", "is_primary_key", "type_desc", "filter_definition",
+				"key_ordinal", "column_name", "is_nullable", "is_descending_key", "is_included_column",
 			},
 			data: [][]driver.Value{
-				{"Accounts", "PK_Accounts", true, true, "CLUSTERED", false, int64(1), "AccountID", false, false},
-				{"OrderVersions", "PK_OrderVersions", true, true, "CLUSTERED", false, int64(1), "OrderID", false, false},
-				{"OrderVersions", "PK_OrderVersions", true, true, "CLUSTERED", false, int64(2), "VersionNo", false, false},
-				{"OrderVersions", "IX_OrderVersions_Filtered", false, false, "NONCLUSTERED", true, int64(1), "AccountID", false, false},
-				{"OrderVersions", "IX_OrderVersions_Sort", false, false, "NONCLUSTERED", false, int64(1), "AccountID", true, false},
-				{"OrderVersions", "IX_OrderVersions_Sort", false, false, "NONCLUSTERED", false, int64(2), "VersionNo", false, false},
-				{"OrderVersions", "IX_OrderVersions_Sort", false, false, "NONCLUSTERED", false, int64(0), "DisplayLabel", false, true},
+				{"Accounts", "PK_Accounts", true, true, "CLUSTERED", nil, int64(1), "AccountID", false, false, false},
+				{"OrderVersions", "PK_OrderVersions", true, true, "CLUSTERED", nil, int64(1), "OrderID", false, false, false},
+				{"OrderVersions", "PK_OrderVersions", true, true, "CLUSTERED", nil, int64(2), "VersionNo", false, false, false},
+				{"OrderVersions", "IX_OrderVersions_Filtered", false, false, "NONCLUSTERED", "([VersionNo]>(1))", int64(1), "AccountID", false, false, false},
+				{"OrderVersions", "IX_OrderVersions_Sort", false, false, "NONCLUSTERED", nil, int64(1), "AccountID", false, true, false},
+				{"OrderVersions", "IX_OrderVersions_Sort", false, false, "NONCLUSTERED", nil, int64(2), "VersionNo", false, false, false},
+				{"OrderVersions", "IX_OrderVersions_Sort", false, false, "NONCLUSTERED", nil, int64(0), "DisplayLabel", true, false, true},
+				{"OrderVersions", "UX_OrderVersions_Label", true, false, "NONCLUSTERED", nil, int64(1), "DisplayLabel", true, false, false},
+			},
+		}, nil
+	case strings.Contains(normalized, "FROM sys.fulltext_indexes fi"):
+		return &mssqlStubRows{
+			columns: []string{"table_name", "column_name", "type_name"},
+			data: [][]driver.Value{
+				{"OrderVersions", "StatusText", "nvarchar"},
+				{"OrderVersions", "DisplayLabel", "nvarchar"},
 			},
 		}, nil
 	case strings.Contains(normalized, "FROM sys.foreign_keys fk"):
@@ -157,6 +166,21 @@ func (s *mssqlIntrospectionStub) query(query string, args []driver.NamedValue) (
 				{"OrderVersions", "FK_OrderVersions_Accounts", "AccountID", "Accounts", "AccountID", "CASCADE", "NO_ACTION", "dbo"},
 			},
 		}, nil
+	case strings.Contains(normalized, "FROM sys.check_constraints cc"):
+		return &mssqlStubRows{
+			columns: []string{"table_name", "name", "definition"},
+			data: [][]driver.Value{
+				{"OrderVersions", "CK_OrderVersions_VersionNo", "([VersionNo]>(0))"},
+			},
+		}, nil
+	case strings.Contains(normalized, "FROM sys.sequences sq"):
+		return &mssqlStubRows{
+			columns: []string{"name", "type_name", "start_value", "increment", "minimum_value", "maximum_value", "is_cycling", "last_used_value"},
+			data: [][]driver.Value{
+				{"AuditVersionSeq", "int", int64(100), int64(10), int64(1), int64(2147483647), false, int64(250)},
+				{"TicketSeq", "decimal", int64(1), int64(1), int64(1), nil, true, nil},
+			},
+		}, nil
 	default:
 		return nil, fmt.Errorf("unexpected query: %s", normalized)
 	}
@@ -186,8 +210,8 @@ func TestMSSQLIntrospectSchemaBatchesSchemaQueries(t *testing.T) {
 		t.Fatalf("IntrospectSchema: %v", err)
 	}
 
-	if len(stub.queries) != 4 {
-		t.Fatalf("query count = %d, want 4", len(stub.queries))
+	if len(stub.queries) != 7 {
+		t.Fatalf("query count = %d, want 7", len(stub.queries))
 	}
 	for i, call := range stub.queries {
 		if len(call.args) != 1 || call.args[0] != "dbo" {
@@ -206,6 +230,25 @@ func TestMSSQLIntrospectSchemaBatchesSchemaQueries(t *testing.T) {
 	if accounts.PrimaryKey == nil {
 		t.Fatal("Accounts primary key = nil")
 	}
+	if accounts.AutoIncrementNext != 1045 || accounts.AutoIncrementStep != 5 {
+		t.Fatalf("Accounts identity = next %d step %d, want 1045 step 5", accounts.AutoIncrementNext, accounts.AutoIncrementStep)
+	}
+	if audit := findSchemaTable(t, schema, "AuditTrail"); audit.AutoIncrementNext != 0 || audit.AutoIncrementStep != 0 {
+		t.Fatalf("AuditTrail identity = next %d step %d, want none", audit.AutoIncrementNext, audit.AutoIncrementStep)
+	}
+	if audit := findSchemaTable(t, schema, "AuditTrail"); audit.Columns[1].DefaultSequence != "audit_version_seq" {
+		t.Fatalf("AuditTrail.VersionNo DefaultSequence = %q, want audit_version_seq", audit.Columns[1].DefaultSequence)
+	}
+	if len(schema.Sequences) != 2 {
+		t.Fatalf("sequences = %d, want 2", len(schema.Sequences))
+	}
+	if seq := schema.Sequences[0]; seq.PGName != "audit_version_seq" || seq.Start != 100 || seq.Increment != 10 ||
+		seq.MaxValue == nil || *seq.MaxValue != 2147483647 || seq.LastValue == nil || *seq.LastValue != 250 {
+		t.Fatalf("AuditVersionSeq = %+v", seq)
+	}
+	if seq := schema.Sequences[1]; seq.MaxValue != nil || seq.LastValue != nil || !seq.Cycle {
+		t.Fatalf("TicketSeq = %+v, want no bigint-range max, never used, cycling", seq)
+	}
 	if got := strings.Join(accounts.PrimaryKey.Columns, ","); got != "account_id" {
 		t.Fatalf("Accounts PK columns = %v, want [account_id]", accounts.PrimaryKey.Columns)
 	}
@@ -215,6 +258,9 @@ func TestMSSQLIntrospectSchemaBatchesSchemaQueries(t *testing.T) {
 	if accounts.Columns[1].CharMaxLen != 64 {
 		t.Fatalf("Accounts.UserName CharMaxLen = %d, want 64", accounts.Columns[1].CharMaxLen)
 	}
+	if accounts.Comment != "Customer login accounts" || accounts.Columns[1].Comment != "Login name" {
+		t.Fatalf("Accounts comments = %q/%q", accounts.Comment, accounts.Columns[1].Comment)
+	}
 
 	orderVersions := findSchemaTable(t, schema, "OrderVersions")
 	if orderVersions.PrimaryKey == nil {
@@ -223,11 +269,23 @@ func TestMSSQLIntrospectSchemaBatchesSchemaQueries(t *testing.T) {
 	if got := strings.Join(orderVersions.PrimaryKey.Columns, ","); got != "order_id,version_no" {
 		t.Fatalf("OrderVersions PK columns = %v, want [order_id version_no]", orderVersions.PrimaryKey.Columns)
 	}
-	if len(orderVersions.Indexes) != 2 {
-		t.Fatalf("OrderVersions indexes = %d, want 2", len(orderVersions.Indexes))
+	if len(orderVersions.Indexes) != 4 {
+		t.Fatalf("OrderVersions indexes = %d, want 4", len(orderVersions.Indexes))
+	}
+	if ft := orderVersions.Indexes[3]; ft.Type != "FULLTEXT" || ft.Name != "ft_order_versions" || strings.Join(ft.Columns, ",") != "status_text,display_label" {
+		t.Fatalf("full-text index = %+v", ft)
 	}
-	if !orderVersions.Indexes[0].HasExpression {
-		t.Fatal("filtered index should be marked unsupported")
+	if filtered := orderVersions.Indexes[0]; filtered.HasExpression || filtered.Filter != "([VersionNo]>(1))" {
+		t.Fatalf("IX_OrderVersions_Filtered = %+v, want filter ([VersionNo]>(1))", filtered)
+	}
+	if got := strings.Join(orderVersions.Indexes[1].Include, ","); got != "display_label" {
+		t.Fatalf("IX_OrderVersions_Sort include = %v, want [display_label]", orderVersions.Indexes[1].Include)
+	}
+	if orderVersions.Indexes[1].NullsNotDistinct {
+		t.Fatal("non-unique IX_OrderVersions_Sort should not be NULLS NOT DISTINCT")
+	}
+	if !orderVersions.Indexes[2].NullsNotDistinct {
+		t.Fatal("unique UX_OrderVersions_Label on a nullable column should be NULLS NOT DISTINCT")
 	}
 	if got := strings.Join(orderVersions.Indexes[1].ColumnOrders, ","); got != "DESC,ASC" {
 		t.Fatalf("IX_OrderVersions_Sort orders = %v, want [DESC ASC]", orderVersions.Indexes[1].ColumnOrders)
@@ -245,6 +303,13 @@ func TestMSSQLIntrospectSchemaBatchesSchemaQueries(t *testing.T) {
 		t.Fatalf("DisplayLabel expression = %q", orderVersions.Columns[4].GenerationExpression)
 	}
 
+	if len(orderVersions.CheckConstraints) != 1 {
+		t.Fatalf("OrderVersions check constraints = %d, want 1", len(orderVersions.CheckConstraints))
+	}
+	if ck := orderVersions.CheckConstraints[0]; ck.Name != "ck_order_versions_version_no" || ck.Expression != "([VersionNo]>(0))" {
+		t.Fatalf("OrderVersions check = %+v", ck)
+	}
+
 	auditTrail := findSchemaTable(t, schema, "AuditTrail")
 	if len(auditTrail.ForeignKeys) != 2 {
 		t.Fatalf("AuditTrail foreign keys = %d, want 2", len(auditTrail.ForeignKeys))
@@ -144,6 +144,9 @@ func TestMSSQLMapDefault(t *testing.T) {
 		// Bytea/JSON defaults not supported
 		{"bytea default", "0x00", "bytea", ""},
 		{"json default", "{}", "json", ""},
+
+		// Sequence defaults come from Column.DefaultSequence
+		{"next value for", "NEXT VALUE FOR [dbo].[TicketSeq]", "varchar(20)", ""},
 	}
 
 	for _, tt := range tests {
@@ -160,6 +163,26 @@ func TestMSSQLMapDefault(t *testing.T) {
 	}
 }
 
+func TestParseMSSQLNextValueFor(t *testing.T) {
+	tests := []struct {
+		in           string
+		schema, name string
+		ok           bool
+	}{
+		{"(NEXT VALUE FOR [dbo].[TicketSeq])", "dbo", "TicketSeq", true},
+		{"next value for TicketSeq", "", "TicketSeq", true},
+		{"NEXT VALUE FOR [sales].[Odd]]Name]", "sales", "Odd]Name", true},
+		{"NEXT VALUE FOR [db].[dbo].[TicketSeq]", "", "", false},
+		{"(getdate())", "", "", false},
+	}
+	for _, tt := range tests {
+		schema, name, ok := parseMSSQLNextValueFor(tt.in)
+		if schema != tt.schema || name != tt.name || ok != tt.ok {
+			t.Errorf("parseMSSQLNextValueFor(%q) = %q, %q, %v; want %q, %q, %v", tt.in, schema, name, ok, tt.schema, tt.name, tt.ok)
+		}
+	}
+}
+
 func TestMSSQLStripParens(t *testing.T) {
 	tests := []struct {
 		in, want string
@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"encoding/binary"
+	"errors"
 	"fmt"
+	"log"
 	"regexp"
 	"strconv"
 	"strings"
@@ -145,8 +148,9 @@ func introspectMySQLSchema(db *sql.DB, dbName string, identName func(string) str
 		return nil, fmt.Errorf("introspect tables: %w", err)
 	}
 
-	// Batch schema-scoped INFORMATION_SCHEMA queries so startup stays at four
-	// round trips total: tables, columns, indexes, and foreign keys.
+	// Batch schema-scoped INFORMATION_SCHEMA queries so startup stays at six
+	// round trips total: tables, columns, indexes, foreign keys, checks, and
+	// partitions.
 	columnsByTable, err := introspectMySQLColumnsByTable(db, dbName, identName)
 	if err != nil {
 		return nil, fmt.Errorf("introspect columns for schema %s: %w", dbName, err)
@@ -162,6 +166,16 @@ func introspectMySQLSchema(db *sql.DB, dbName string, identName func(string) str
 		return nil, fmt.Errorf("introspect foreign keys for schema %s: %w", dbName, err)
 	}
 
+	checksByTable, err := introspectMySQLCheckConstraintsByTable(db, dbName, identName)
+	if err != nil {
+		return nil, fmt.Errorf("introspect check constraints for schema %s: %w", dbName, err)
+	}
+
+	partitioningByTable, err := introspectMySQLPartitionsByTable(db, dbName, identName)
+	if err != nil {
+		return nil, fmt.Errorf("introspect partitions for schema %s: %w", dbName, err)
+	}
+
 	for i := range tables {
 		t := &tables[i]
 		t.Columns = columnsByTable[t.SourceName]
@@ -174,14 +188,48 @@ func introspectMySQLSchema(db *sql.DB, dbName string, identName func(string) str
 			}
 		}
 		t.ForeignKeys = foreignKeysByTable[t.SourceName]
+		t.CheckConstraints = checksByTable[t.SourceName]
+		if p := partitioningByTable[t.SourceName]; p != nil {
+			for j := range p.Partitions {
+				p.Partitions[j].Name = truncateGeneratedIdentifier(t.PGName + "_" + identName(p.Partitions[j].SourceName))
+			}
+			// KEY() without columns partitions by the primary key.
+			if p.Method == "HASH" && p.Expression == "" && t.PrimaryKey != nil {
+				p.Columns = append([]string(nil), t.PrimaryKey.Columns...)
+			}
+			t.Partitioning = p
+		}
 	}
 
 	return &Schema{Tables: tables}, nil
 }
 
+// mysqlErrUnknownSystemVariable is returned for SET on a variable the server
+// does not have.
+const mysqlErrUnknownSystemVariable = 1193
+
 func introspectMySQLTables(db *sql.DB, dbName string, identName func(string) string) ([]Table, error) {
-	rows, err := db.Query(
-		`SELECT TABLE_NAME FROM INFORMATION_SCHEMA.TABLES
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// MySQL 8 caches INFORMATION_SCHEMA.TABLES.AUTO_INCREMENT for
+	// information_schema_stats_expiry seconds; read the live counter instead.
+	// Older servers and MariaDB have no cache and no such variable.
+	if _, err := conn.ExecContext(ctx, "SET SESSION information_schema_stats_expiry = 0"); err != nil {
+		var mysqlErr *mysql.MySQLError
+		if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlErrUnknownSystemVariable {
+			return nil, err
+		}
+	}
+
+	rows, err := conn.QueryContext(ctx,
+		`SELECT TABLE_NAME, COALESCE(TABLE_COMMENT, ''), COALESCE(AUTO_INCREMENT, 0),
+		        @@auto_increment_increment
+		 FROM INFORMATION_SCHEMA.TABLES
 		 WHERE TABLE_SCHEMA = ? AND TABLE_TYPE = 'BASE TABLE'
 		 ORDER BY TABLE_NAME`,
 		dbName,
@@ -192,17 +240,29 @@ func introspectMySQLTables(db *sql.DB, dbName string, identName func(string) str
 	defer rows.Close()
 
 	var tables []Table
+	var increment int64
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
+		var name, comment string
+		var next int64
+		if err := rows.Scan(&name, &comment, &next, &increment); err != nil {
 			return nil, err
 		}
 		tables = append(tables, Table{
-			SourceName: name,
-			PGName:     identName(name),
+			SourceName:        name,
+			PGName:            identName(name),
+			Comment:           comment,
+			AutoIncrementNext: next,
 		})
 	}
-	return tables, rows.Err()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	// auto_increment_increment is a session/global server setting, not a
+	// property of the table, so sequences keep a step of 1.
+	if increment > 1 {
+		log.Printf("    WARN: source auto_increment_increment is %d; sequences are created with INCREMENT BY 1", increment)
+	}
+	return tables, nil
 }
 
 func introspectMySQLColumnsByTable(db *sql.DB, dbName string, identName func(string) string) (map[string][]Column, error) {
@@ -214,7 +274,8 @@ func introspectMySQLColumnsByTable(db *sql.DB, dbName string, identName func(str
 		        IS_NULLABLE, COLUMN_DEFAULT, EXTRA, ORDINAL_POSITION,
 		        COALESCE(CHARACTER_SET_NAME, ''),
 		        COALESCE(COLLATION_NAME, ''),
-		        COALESCE(GENERATION_EXPRESSION, '')
+		        COALESCE(GENERATION_EXPRESSION, ''),
+		        COALESCE(COLUMN_COMMENT, '')
 		 FROM INFORMATION_SCHEMA.COLUMNS
 		 WHERE TABLE_SCHEMA = ?
 		 ORDER BY TABLE_NAME, ORDINAL_POSITION`,
@@ -236,7 +297,7 @@ func introspectMySQLColumnsByTable(db *sql.DB, dbName string, identName func(str
 			&c.CharMaxLen, &c.Precision, &c.Scale,
 			&nullable, &dflt, &c.Extra, &c.OrdinalPos,
 			&c.Charset, &c.Collation,
-			&c.GenerationExpression,
+			&c.GenerationExpression, &c.Comment,
 		); err != nil {
 			return nil, err
 		}
@@ -261,16 +322,27 @@ type mysqlIndexesForTable struct {
 	order    []string
 }
 
+// mysqlErrBadField is ER_BAD_FIELD_ERROR, returned for
+// INFORMATION_SCHEMA.STATISTICS.EXPRESSION on servers older than MySQL 8.0.13.
+const mysqlErrBadField = 1054
+
+const mysqlIndexesQuery = `SELECT TABLE_NAME, INDEX_NAME, COLUMN_NAME, NON_UNIQUE, SEQ_IN_INDEX, INDEX_TYPE, COLLATION, SUB_PART, %s
+	 FROM INFORMATION_SCHEMA.STATISTICS
+	 WHERE TABLE_SCHEMA = ?
+	 ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX`
+
 func introspectMySQLIndexesByTable(db *sql.DB, dbName string, identName func(string) string) (map[string][]Index, error) {
-	rows, err := db.Query(
-		`SELECT TABLE_NAME, INDEX_NAME, COLUMN_NAME, NON_UNIQUE, SEQ_IN_INDEX, INDEX_TYPE, COLLATION, SUB_PART
-		 FROM INFORMATION_SCHEMA.STATISTICS
-		 WHERE TABLE_SCHEMA = ?
-		 ORDER BY TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX`,
-		dbName,
-	)
+	rows, err := db.Query(fmt.Sprintf(mysqlIndexesQuery, "EXPRESSION"), dbName)
 	if err != nil {
-		return nil, err
+		var mysqlErr *mysql.MySQLError
+		if !errors.As(err, &mysqlErr) || mysqlErr.Number != mysqlErrBadField {
+			return nil, err
+		}
+		// Functional key parts do not exist before 8.0.13.
+		rows, err = db.Query(fmt.Sprintf(mysqlIndexesQuery, "NULL AS EXPRESSION"), dbName)
+		if err != nil {
+			return nil, err
+		}
 	}
 	defer rows.Close()
 
@@ -278,10 +350,10 @@ func introspectMySQLIndexesByTable(db *sql.DB, dbName string, identName func(str
 
 	for rows.Next() {
 		var tableName, idxName, indexType string
-		var colName, collation sql.NullString
+		var colName, collation, expression sql.NullString
 		var subPart sql.NullInt64
 		var nonUnique, seqInIndex int
-		if err := rows.Scan(&tableName, &idxName, &colName, &nonUnique, &seqInIndex, &indexType, &collation, &subPart); err != nil {
+		if err := rows.Scan(&tableName, &idxName, &colName, &nonUnique, &seqInIndex, &indexType, &collation, &subPart, &expression); err != nil {
 			return nil, err
 		}
 
@@ -304,15 +376,24 @@ func introspectMySQLIndexesByTable(db *sql.DB, dbName string, identName func(str
 			group.order = append(group.order, idxName)
 		}
 
+		prefix := 0
 		if mysqlIndexHasPrefix(indexType, subPart) {
 			idx.HasPrefix = true
+			prefix = int(subPart.Int64)
 		}
 		if !colName.Valid {
 			idx.HasExpression = true
-			continue
+			if !expression.Valid {
+				continue
+			}
+			// Functional key part: keep its position with an empty column.
+			idx.Columns = append(idx.Columns, "")
+			idx.Expressions = append(idx.Expressions, expression.String)
+		} else {
+			idx.Columns = append(idx.Columns, identName(colName.String))
+			idx.Expressions = append(idx.Expressions, "")
 		}
-
-		idx.Columns = append(idx.Columns, identName(colName.String))
+		idx.PrefixLengths = append(idx.PrefixLengths, prefix)
 		if collation.Valid && strings.EqualFold(collation.String, "D") {
 			idx.ColumnOrders = append(idx.ColumnOrders, "DESC")
 		} else {
@@ -412,20 +493,163 @@ func introspectMySQLForeignKeysByTable(db *sql.DB, dbName string, identName func
 	return fksByTable, nil
 }
 
+// mysqlErrUnknownTable is ER_UNKNOWN_TABLE, returned for
+// INFORMATION_SCHEMA.CHECK_CONSTRAINTS on servers older than MySQL 8.0.16.
+const mysqlErrUnknownTable = 1109
+
+func introspectMySQLCheckConstraintsByTable(db *sql.DB, dbName string, identName func(string) string) (map[string][]CheckConstraint, error) {
+	rows, err := db.Query(
+		`SELECT tc.TABLE_NAME, cc.CONSTRAINT_NAME, cc.CHECK_CLAUSE
+		 FROM INFORMATION_SCHEMA.TABLE_CONSTRAINTS tc
+		 JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS cc
+		   ON tc.CONSTRAINT_SCHEMA = cc.CONSTRAINT_SCHEMA
+		   AND tc.CONSTRAINT_NAME = cc.CONSTRAINT_NAME
+		 WHERE tc.TABLE_SCHEMA = ?
+		   AND tc.CONSTRAINT_TYPE = 'CHECK'
+		 ORDER BY tc.TABLE_NAME, cc.CONSTRAINT_NAME`,
+		dbName,
+	)
+	if err != nil {
+		var mysqlErr *mysql.MySQLError
+		if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrUnknownTable {
+			return map[string][]CheckConstraint{}, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	checksByTable := make(map[string][]CheckConstraint)
+	for rows.Next() {
+		var tableName, name, clause string
+		if err := rows.Scan(&tableName, &name, &clause); err != nil {
+			return nil, err
+		}
+		checksByTable[tableName] = append(checksByTable[tableName], CheckConstraint{
+			Name:       identName(name),
+			SourceName: name,
+			Expression: clause,
+		})
+	}
+	return checksByTable, rows.Err()
+}
+
+func introspectMySQLPartitionsByTable(db *sql.DB, dbName string, identName func(string) string) (map[string]*Partitioning, error) {
+	rows, err := db.Query(
+		`SELECT TABLE_NAME, PARTITION_NAME, PARTITION_METHOD,
+		        COALESCE(PARTITION_EXPRESSION, ''),
+		        COALESCE(PARTITION_DESCRIPTION, ''),
+		        COALESCE(SUBPARTITION_METHOD, '')
+		 FROM INFORMATION_SCHEMA.PARTITIONS
+		 WHERE TABLE_SCHEMA = ? AND PARTITION_NAME IS NOT NULL
+		 ORDER BY TABLE_NAME, PARTITION_ORDINAL_POSITION, SUBPARTITION_ORDINAL_POSITION`,
+		dbName,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	partitioningByTable := make(map[string]*Partitioning)
+	for rows.Next() {
+		var tableName, name, method, expr, desc, subMethod string
+		if err := rows.Scan(&tableName, &name, &method, &expr, &desc, &subMethod); err != nil {
+			return nil, err
+		}
+		p := partitioningByTable[tableName]
+		if p == nil {
+			p = &Partitioning{Expression: expr}
+			// LINEAR only changes how MySQL assigns hash buckets, and KEY is
+			// hashing over a column list.
+			method = strings.TrimPrefix(method, "LINEAR ")
+			listed := strings.HasSuffix(method, " COLUMNS") || method == "KEY"
+			switch strings.TrimSuffix(method, " COLUMNS") {
+			case "RANGE":
+				p.Method = "RANGE"
+			case "LIST":
+				p.Method = "LIST"
+			case "HASH", "KEY":
+				p.Method = "HASH"
+			default:
+				p.Method = method
+				p.Reason = fmt.Sprintf("partitioning method %s is not supported", method)
+			}
+			if cols, ok := mysqlPartitionColumns(expr, identName); ok {
+				p.Columns = cols
+			} else if listed && expr != "" {
+				p.Reason = fmt.Sprintf("cannot parse partition column list %s", expr)
+			}
+			if subMethod != "" {
+				p.Reason = "subpartitioning is not supported"
+			}
+			partitioningByTable[tableName] = p
+		}
+		// Subpartitioned tables report one row per subpartition.
+		if n := len(p.Partitions); n > 0 && p.Partitions[n-1].SourceName == name {
+			continue
+		}
+		p.Partitions = append(p.Partitions, Partition{SourceName: name, Description: desc})
+	}
+	return partitioningByTable, rows.Err()
+}
+
+// mysqlPartitionColumns parses a partition expression that is a plain list of
+// column names, as reported for COLUMNS and KEY partitioning or a RANGE/LIST/
+// HASH over a single column, into PG column names.
+func mysqlPartitionColumns(expr string, identName func(string) string) ([]string, bool) {
+	tokens, err := tokenizeCheckExpression(expr, "mysql")
+	if err != nil || len(tokens) == 0 {
+		return nil, false
+	}
+	var cols []string
+	for i, tok := range tokens {
+		if i%2 == 1 {
+			if tok.kind != checkTokenComma {
+				return nil, false
+			}
+			continue
+		}
+		if tok.kind != checkTokenIdent && tok.kind != checkTokenWord {
+			return nil, false
+		}
+		cols = append(cols, identName(tok.text))
+	}
+	if len(tokens)%2 == 0 {
+		return nil, false
+	}
+	return cols, true
+}
+
 // --- Source objects introspection (moved from source_objects.go) ---
 
 func introspectMySQLSourceObjects(db *sql.DB, dbName string) (*SourceObjects, error) {
-	objs := &SourceObjects{}
+	objs := &SourceObjects{ViewDefinitions: map[string]string{}, SchemaName: dbName}
 
-	if err := collectStringRows(db, `
-		SELECT TABLE_NAME
+	viewRows, err := db.Query(`
+		SELECT TABLE_NAME, COALESCE(VIEW_DEFINITION, '')
 		FROM INFORMATION_SCHEMA.VIEWS
 		WHERE TABLE_SCHEMA = ?
 		ORDER BY TABLE_NAME
-	`, dbName, &objs.Views); err != nil {
+	`, dbName)
+	if err != nil {
 		return nil, fmt.Errorf("introspect views: %w", err)
 	}
+	defer viewRows.Close()
+	for viewRows.Next() {
+		var name, definition string
+		if err := viewRows.Scan(&name, &definition); err != nil {
+			return nil, fmt.Errorf("scan views: %w", err)
+		}
+		objs.Views = append(objs.Views, name)
+		if definition != "" {
+			objs.ViewDefinitions[name] = definition
+		}
+	}
+	if err := viewRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate views: %w", err)
+	}
 
+	type routine struct{ kind, name string }
+	var routines []routine
 	rows, err := db.Query(`
 		SELECT ROUTINE_TYPE, ROUTINE_NAME
 		FROM INFORMATION_SCHEMA.ROUTINES
@@ -441,24 +665,96 @@ func introspectMySQLSourceObjects(db *sql.DB, dbName string) (*SourceObjects, er
 		if err := rows.Scan(&routineType, &routineName); err != nil {
 			return nil, fmt.Errorf("scan routines: %w", err)
 		}
+		routines = append(routines, routine{strings.ToUpper(routineType), routineName})
 		objs.Routines = append(objs.Routines, fmt.Sprintf("%s %s", strings.ToUpper(routineType), routineName))
 	}
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("iterate routines: %w", err)
 	}
+	rows.Close()
 
-	if err := collectStringRows(db, `
-		SELECT TRIGGER_NAME
+	triggerRows, err := db.Query(`
+		SELECT TRIGGER_NAME, EVENT_OBJECT_TABLE, ACTION_TIMING, EVENT_MANIPULATION,
+			COALESCE(ACTION_STATEMENT, '')
 		FROM INFORMATION_SCHEMA.TRIGGERS
 		WHERE TRIGGER_SCHEMA = ?
 		ORDER BY TRIGGER_NAME
-	`, dbName, &objs.Triggers); err != nil {
+	`, dbName)
+	if err != nil {
 		return nil, fmt.Errorf("introspect triggers: %w", err)
 	}
+	defer triggerRows.Close()
+	objs.TriggerDefinitions = make(map[string]TriggerDefinition)
+	for triggerRows.Next() {
+		var name string
+		var def TriggerDefinition
+		var event string
+		if err := triggerRows.Scan(&name, &def.Table, &def.Timing, &event, &def.Body); err != nil {
+			return nil, fmt.Errorf("scan triggers: %w", err)
+		}
+		objs.Triggers = append(objs.Triggers, name)
+		if def.Body == "" {
+			continue
+		}
+		def.Events = []string{strings.ToUpper(event)}
+		def.Definition = fmt.Sprintf("CREATE TRIGGER `%s` %s %s ON `%s` FOR EACH ROW %s",
+			name, def.Timing, event, def.Table, def.Body)
+		def.Body = triggerBodyWithoutBlock(def.Body)
+		objs.TriggerDefinitions[name] = def
+	}
+	if err := triggerRows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate triggers: %w", err)
+	}
+	triggerRows.Close()
+
+	// SHOW CREATE needs its own round trip per routine; run it after the
+	// listing queries are closed so a single connection suffices.
+	objs.RoutineDefinitions = make(map[string]string, len(routines))
+	for _, r := range routines {
+		def, err := mysqlShowCreate(db, r.kind, dbName, r.name)
+		if err != nil {
+			return nil, fmt.Errorf("show create %s %s: %w", strings.ToLower(r.kind), r.name, err)
+		}
+		if def != "" {
+			objs.RoutineDefinitions[fmt.Sprintf("%s %s", r.kind, r.name)] = def
+		}
+	}
 
 	return objs, nil
 }
 
+// mysqlShowCreate returns the CREATE statement reported by SHOW CREATE
+// PROCEDURE/FUNCTION. It is empty when the user lacks the privilege to see
+// the routine body.
+func mysqlShowCreate(db *sql.DB, kind, dbName, name string) (string, error) {
+	quote := func(s string) string { return "`" + strings.ReplaceAll(s, "`", "``") + "`" }
+	rows, err := db.Query(fmt.Sprintf("SHOW CREATE %s %s.%s", kind, quote(dbName), quote(name)))
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	cols, err := rows.Columns()
+	if err != nil {
+		return "", err
+	}
+	if !rows.Next() {
+		return "", rows.Err()
+	}
+	values := make([]sql.NullString, len(cols))
+	dest := make([]any, len(cols))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := rows.Scan(dest...); err != nil {
+		return "", err
+	}
+	// Columns: name, sql_mode, Create Procedure/Function, ...
+	if len(values) < 3 {
+		return "", nil
+	}
+	return values[2].String, nil
+}
+
 // --- Type mapping (moved from transform.go / type_compat.go) ---
 
 func isBinary16Column(col Column) bool {
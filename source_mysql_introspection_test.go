@@ -14,6 +14,7 @@ import (
 type mysqlIntrospectionStub struct {
 	mu      sync.Mutex
 	queries []mysqlStubQueryCall
+	execs   []string
 }
 
 type mysqlStubQueryCall struct {
@@ -71,7 +72,13 @@ func (s *mysqlStubStmt) Close() error { return nil }
 func (s *mysqlStubStmt) NumInput() int { return -1 }
 
 func (s *mysqlStubStmt) Exec([]driver.Value) (driver.Result, error) {
-	return nil, fmt.Errorf("exec not supported")
+	if !strings.HasPrefix(s.query, "SET SESSION ") {
+		return nil, fmt.Errorf("exec not supported")
+	}
+	s.conn.stub.mu.Lock()
+	s.conn.stub.execs = append(s.conn.stub.execs, s.query)
+	s.conn.stub.mu.Unlock()
+	return driver.RowsAffected(0), nil
 }
 
 func (s *mysqlStubStmt) Query(args []driver.Value) (driver.Rows, error) {
@@ -105,11 +112,11 @@ func (s *mysqlIntrospectionStub) query(query string, args []driver.NamedValue) (
 	switch {
 	case strings.Contains(normalized, "FROM INFORMATION_SCHEMA.TABLES"):
 		return &mysqlStubRows{
-			columns: []string{"TABLE_NAME"},
+			columns: []string{"TABLE_NAME", "TABLE_COMMENT", "AUTO_INCREMENT", "@@auto_increment_increment"},
 			data: [][]driver.Value{
-				{"Accounts"},
-				{"AuditTrail"},
-				{"OrderVersions"},
+				{"Accounts", "Customer login accounts", int64(120), int64(2)},
+				{"AuditTrail", "", int64(0), int64(2)},
+				{"OrderVersions", "", int64(0), int64(2)},
 			},
 		}, nil
 	case strings.Contains(normalized, "FROM INFORMATION_SCHEMA.COLUMNS"):
@@ -118,32 +125,33 @@ func (s *mysqlIntrospectionStub) query(query string, args []driver.NamedValue) (
 				"TABLE_NAME", "COLUMN_NAME", "DATA_TYPE", "COLUMN_TYPE",
 				"CHARACTER_MAXIMUM_LENGTH", "NUMERIC_PRECISION", "NUMERIC_SCALE",
 				"IS_NULLABLE", "COLUMN_DEFAULT", "EXTRA", "ORDINAL_POSITION",
-				"CHARACTER_SET_NAME", "COLLATION_NAME", "GENERATION_EXPRESSION",
+				"CHARACTER_SET_NAME", "COLLATION_NAME", "GENERATION_EXPRESSION", "COLUMN_COMMENT",
 			},
 			data: [][]driver.Value{
-				{"Accounts", "AccountID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "auto_increment", int64(1), "", "", ""},
-				{"Accounts", "UserName", "varchar", "varchar(64)", int64(64), int64(0), int64(0), "NO", nil, "", int64(2), "utf8mb4", "utf8mb4_unicode_ci", ""},
-				{"AuditTrail", "OrderID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(1), "", "", ""},
-				{"AuditTrail", "VersionNo", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(2), "", "", ""},
-				{"AuditTrail", "ActorAccountID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(3), "", "", ""},
-				{"OrderVersions", "OrderID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(1), "", "", ""},
-				{"OrderVersions", "VersionNo", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(2), "", "", ""},
-				{"OrderVersions", "AccountID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(3), "", "", ""},
-				{"OrderVersions", "StatusCode", "varchar", "varchar(16)", int64(16), int64(0), int64(0), "NO", "new", "", int64(4), "utf8mb4", "utf8mb4_bin", ""},
-				{"OrderVersions", "DisplayLabel", "varchar", "varchar(64)", int64(64), int64(0), int64(0), "YES", nil, "VIRTUAL GENERATED", int64(5), "utf8mb4", "utf8mb4_unicode_ci", "concat(`OrderID`,'-',`VersionNo`)"},
+				{"Accounts", "AccountID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "auto_increment", int64(1), "", "", "", ""},
+				{"Accounts", "UserName", "varchar", "varchar(64)", int64(64), int64(0), int64(0), "NO", nil, "", int64(2), "utf8mb4", "utf8mb4_unicode_ci", "", "Login name, unique per tenant"},
+				{"AuditTrail", "OrderID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(1), "", "", "", ""},
+				{"AuditTrail", "VersionNo", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(2), "", "", "", ""},
+				{"AuditTrail", "ActorAccountID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(3), "", "", "", ""},
+				{"OrderVersions", "OrderID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(1), "", "", "", ""},
+				{"OrderVersions", "VersionNo", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(2), "", "", "", ""},
+				{"OrderVersions", "AccountID", "int", "int(11)", int64(0), int64(10), int64(0), "NO", nil, "", int64(3), "", "", "", ""},
+				{"OrderVersions", "StatusCode", "varchar", "varchar(16)", int64(16), int64(0), int64(0), "NO", "new", "", int64(4), "utf8mb4", "utf8mb4_bin", "", ""},
+				{"OrderVersions", "DisplayLabel", "varchar", "varchar(64)", int64(64), int64(0), int64(0), "YES", nil, "VIRTUAL GENERATED", int64(5), "utf8mb4", "utf8mb4_unicode_ci", "concat(`OrderID`,'-',`VersionNo`)", ""},
 			},
 		}, nil
 	case strings.Contains(normalized, "FROM INFORMATION_SCHEMA.STATISTICS"):
 		return &mysqlStubRows{
-			columns: []string{"TABLE_NAME", "INDEX_NAME", "COLUMN_NAME", "NON_UNIQUE", "SEQ_IN_INDEX", "INDEX_TYPE", "COLLATION", "SUB_PART"},
+			columns: []string{"TABLE_NAME", "INDEX_NAME", "COLUMN_NAME", "NON_UNIQUE", "SEQ_IN_INDEX", "INDEX_TYPE", "COLLATION", "SUB_PART", "EXPRESSION"},
 			data: [][]driver.Value{
-				{"Accounts", "PRIMARY", "AccountID", int64(0), int64(1), "BTREE", "A", nil},
-				{"OrderVersions", "PRIMARY", "OrderID", int64(0), int64(1), "BTREE", "A", nil},
-				{"OrderVersions", "PRIMARY", "VersionNo", int64(0), int64(2), "BTREE", "A", nil},
-				{"OrderVersions", "ft_status", "StatusCode", int64(1), int64(1), "FULLTEXT", "A", nil},
-				{"OrderVersions", "idx_account_status", "AccountID", int64(1), int64(1), "BTREE", "D", nil},
-				{"OrderVersions", "idx_account_status", "StatusCode", int64(1), int64(2), "BTREE", "A", int64(4)},
-				{"OrderVersions", "idx_label_expr", nil, int64(1), int64(1), "BTREE", nil, nil},
+				{"Accounts", "PRIMARY", "AccountID", int64(0), int64(1), "BTREE", "A", nil, nil},
+				{"OrderVersions", "PRIMARY", "OrderID", int64(0), int64(1), "BTREE", "A", nil, nil},
+				{"OrderVersions", "PRIMARY", "VersionNo", int64(0), int64(2), "BTREE", "A", nil, nil},
+				{"OrderVersions", "ft_status", "StatusCode", int64(1), int64(1), "FULLTEXT", "A", nil, nil},
+				{"OrderVersions", "idx_account_status", "AccountID", int64(1), int64(1), "BTREE", "D", nil, nil},
+				{"OrderVersions", "idx_account_status", "StatusCode", int64(1), int64(2), "BTREE", "A", int64(4), nil},
+				{"OrderVersions", "idx_label_expr", nil, int64(1), int64(1), "BTREE", nil, nil, "lower(`StatusCode`)"},
+				{"OrderVersions", "idx_label_expr", "VersionNo", int64(1), int64(2), "BTREE", "A", nil, nil},
 			},
 		}, nil
 	case strings.Contains(normalized, "FROM INFORMATION_SCHEMA.KEY_COLUMN_USAGE"):
@@ -156,6 +164,21 @@ func (s *mysqlIntrospectionStub) query(query string, args []driver.NamedValue) (
 				{"OrderVersions", "fk_order_versions_account", "AccountID", "Accounts", "AccountID", "CASCADE", "RESTRICT"},
 			},
 		}, nil
+	case strings.Contains(normalized, "JOIN INFORMATION_SCHEMA.CHECK_CONSTRAINTS"):
+		return &mysqlStubRows{
+			columns: []string{"TABLE_NAME", "CONSTRAINT_NAME", "CHECK_CLAUSE"},
+			data: [][]driver.Value{
+				{"OrderVersions", "OrderVersions_chk_1", "(`VersionNo` > 0)"},
+			},
+		}, nil
+	case strings.Contains(normalized, "FROM INFORMATION_SCHEMA.PARTITIONS"):
+		return &mysqlStubRows{
+			columns: []string{"TABLE_NAME", "PARTITION_NAME", "PARTITION_METHOD", "PARTITION_EXPRESSION", "PARTITION_DESCRIPTION", "SUBPARTITION_METHOD"},
+			data: [][]driver.Value{
+				{"OrderVersions", "p0", "RANGE COLUMNS", "`OrderID`", "1000", ""},
+				{"OrderVersions", "pMax", "RANGE COLUMNS", "`OrderID`", "MAXVALUE", ""},
+			},
+		}, nil
 	default:
 		return nil, fmt.Errorf("unexpected query: %s", normalized)
 	}
@@ -196,8 +219,8 @@ func TestMySQLIntrospectSchemaBatchesSchemaQueries(t *testing.T) {
 		t.Fatalf("introspectMySQLSchema: %v", err)
 	}
 
-	if len(stub.queries) != 4 {
-		t.Fatalf("query count = %d, want 4", len(stub.queries))
+	if len(stub.queries) != 6 {
+		t.Fatalf("query count = %d, want 6", len(stub.queries))
 	}
 	for i, call := range stub.queries {
 		if len(call.args) != 1 || call.args[0] != "appdb" {
@@ -219,6 +242,20 @@ func TestMySQLIntrospectSchemaBatchesSchemaQueries(t *testing.T) {
 	if accounts.PGName != "accounts" {
 		t.Fatalf("Accounts PGName = %q, want accounts", accounts.PGName)
 	}
+	if accounts.Comment != "Customer login accounts" {
+		t.Fatalf("Accounts comment = %q", accounts.Comment)
+	}
+	// The server-wide auto_increment_increment (2 in the stub) is not a
+	// per-table step.
+	if accounts.AutoIncrementNext != 120 || accounts.AutoIncrementStep != 0 {
+		t.Fatalf("Accounts auto-increment = %d step %d, want 120 step 0", accounts.AutoIncrementNext, accounts.AutoIncrementStep)
+	}
+	if len(stub.execs) != 1 || stub.execs[0] != "SET SESSION information_schema_stats_expiry = 0" {
+		t.Fatalf("session setup = %v, want information_schema_stats_expiry = 0", stub.execs)
+	}
+	if got := accounts.Columns[1].Comment; got != "Login name, unique per tenant" {
+		t.Fatalf("Accounts.UserName comment = %q", got)
+	}
 	if accounts.PrimaryKey == nil {
 		t.Fatal("Accounts primary key = nil")
 	}
@@ -264,6 +301,36 @@ func TestMySQLIntrospectSchemaBatchesSchemaQueries(t *testing.T) {
 	if !orderVersions.Indexes[2].HasExpression {
 		t.Fatal("idx_label_expr HasExpression = false, want true")
 	}
+	if got := orderVersions.Indexes[2]; strings.Join(got.Columns, ",") != ",version_no" || strings.Join(got.Expressions, ",") != "lower(`StatusCode`)," {
+		t.Fatalf("idx_label_expr columns/expressions = %q/%q", got.Columns, got.Expressions)
+	}
+
+	if len(orderVersions.CheckConstraints) != 1 {
+		t.Fatalf("OrderVersions check constraints = %d, want 1", len(orderVersions.CheckConstraints))
+	}
+	if ck := orderVersions.CheckConstraints[0]; ck.Name != "order_versions_chk_1" || ck.Expression != "(`VersionNo` > 0)" {
+		t.Fatalf("OrderVersions check = %+v", ck)
+	}
+	if len(accounts.CheckConstraints) != 0 {
+		t.Fatalf("Accounts check constraints = %v, want none", accounts.CheckConstraints)
+	}
+
+	if accounts.Partitioning != nil {
+		t.Fatalf("Accounts partitioning = %+v, want nil", accounts.Partitioning)
+	}
+	p := orderVersions.Partitioning
+	if p == nil {
+		t.Fatal("OrderVersions partitioning = nil")
+	}
+	if p.Method != "RANGE" || strings.Join(p.Columns, ",") != "order_id" {
+		t.Fatalf("OrderVersions partitioning = %s %v, want RANGE [order_id]", p.Method, p.Columns)
+	}
+	if len(p.Partitions) != 2 || p.Partitions[0].Name != "order_versions_p0" || p.Partitions[1].Name != "order_versions_p_max" {
+		t.Fatalf("OrderVersions partitions = %+v", p.Partitions)
+	}
+	if p.Partitions[1].Description != "MAXVALUE" {
+		t.Fatalf("pMax description = %q, want MAXVALUE", p.Partitions[1].Description)
+	}
 
 	statusCode := orderVersions.Columns[3]
 	if statusCode.PGName != "status_code" {
@@ -7,25 +7,69 @@ type SourceObjects struct {
 	Views    []string
 	Routines []string
 	Triggers []string
+
+	// ViewDefinitions maps view names to their source SQL: the SELECT for
+	// MySQL, the full CREATE VIEW statement for MSSQL and SQLite. Views the
+	// connection may not read are missing.
+	ViewDefinitions map[string]string
+	// SchemaName is the source database/schema that may qualify table
+	// names inside view definitions.
+	SchemaName string
+	// RoutineDefinitions maps entries of Routines to their CREATE
+	// statements. Routines the connection may not read are missing.
+	RoutineDefinitions map[string]string
+	// TriggerDefinitions maps trigger names to their definitions.
+	TriggerDefinitions map[string]TriggerDefinition
+}
+
+// TriggerDefinition is the introspected definition of a source trigger.
+type TriggerDefinition struct {
+	Table      string   // source table name
+	Timing     string   // BEFORE, AFTER, or INSTEAD OF
+	Events     []string // INSERT, UPDATE, DELETE
+	UpdateOf   []string // SQLite UPDATE OF column list
+	When       string   // SQLite WHEN condition, empty when none
+	Body       string   // trigger statements, without BEGIN/END
+	Definition string   // full source SQL
 }
 
-func sourceObjectWarnings(objs *SourceObjects) []string {
+// sourceObjectWarnings lists the non-table objects that need manual
+// migration. Views with a translated definition are created by pgferry and
+// are left out.
+func sourceObjectWarnings(objs *SourceObjects, views []View) []string {
 	if objs == nil {
 		return nil
 	}
 
+	untranslated := make([]string, 0, len(objs.Views))
+	reasons := make(map[string]string, len(views))
+	translated := make(map[string]bool, len(views))
+	for _, v := range views {
+		translated[v.SourceName] = v.SQL != ""
+		reasons[v.SourceName] = v.Reason
+	}
+	for _, v := range objs.Views {
+		if !translated[v] {
+			untranslated = append(untranslated, v)
+		}
+	}
+
 	var warnings []string
-	if len(objs.Views) == 0 && len(objs.Routines) == 0 && len(objs.Triggers) == 0 {
+	if len(untranslated) == 0 && len(objs.Routines) == 0 && len(objs.Triggers) == 0 {
 		return warnings
 	}
 
 	warnings = append(warnings,
 		fmt.Sprintf(
 			"source contains non-table objects not migrated automatically (%d views, %d routines, %d triggers)",
-			len(objs.Views), len(objs.Routines), len(objs.Triggers),
+			len(untranslated), len(objs.Routines), len(objs.Triggers),
 		),
 	)
-	for _, v := range objs.Views {
+	for _, v := range untranslated {
+		if reason := reasons[v]; reason != "" {
+			warnings = append(warnings, fmt.Sprintf("view: %s (%s)", v, reason))
+			continue
+		}
 		warnings = append(warnings, fmt.Sprintf("view: %s", v))
 	}
 	for _, r := range objs.Routines {
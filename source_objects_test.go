@@ -9,7 +9,7 @@ func TestSourceObjectWarnings(t *testing.T) {
 		Triggers: []string{"trg_users_touch"},
 	}
 
-	warnings := sourceObjectWarnings(objs)
+	warnings := sourceObjectWarnings(objs, nil)
 	if len(warnings) != 5 {
 		t.Fatalf("warnings len = %d, want 5 (%v)", len(warnings), warnings)
 	}
@@ -19,8 +19,24 @@ func TestSourceObjectWarnings(t *testing.T) {
 }
 
 func TestSourceObjectWarnings_Empty(t *testing.T) {
-	warnings := sourceObjectWarnings(&SourceObjects{})
+	warnings := sourceObjectWarnings(&SourceObjects{}, nil)
 	if len(warnings) != 0 {
 		t.Fatalf("warnings len = %d, want 0 (%v)", len(warnings), warnings)
 	}
 }
+
+func TestSourceObjectWarnings_SkipsTranslatedViews(t *testing.T) {
+	objs := &SourceObjects{Views: []string{"v_users", "v_report"}}
+	views := []View{
+		{SourceName: "v_users", SQL: `CREATE VIEW "app"."v_users" AS SELECT 1`},
+		{SourceName: "v_report", Reason: "unsupported TOP clause"},
+	}
+
+	warnings := sourceObjectWarnings(objs, views)
+	if len(warnings) != 2 {
+		t.Fatalf("warnings len = %d, want 2 (%v)", len(warnings), warnings)
+	}
+	if want := "view: v_report (unsupported TOP clause)"; warnings[1] != want {
+		t.Fatalf("warnings[1] = %q, want %q", warnings[1], want)
+	}
+}
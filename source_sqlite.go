@@ -80,7 +80,7 @@ func (s *sqliteSourceDB) SourceTableRef(table Table) string {
 }
 
 func (s *sqliteSourceDB) IntrospectSchema(db *sql.DB, _ string) (*Schema, error) {
-	tables, err := introspectSQLiteTables(db, s.identName)
+	tables, ftsColumns, err := introspectSQLiteTables(db, s.identName)
 	if err != nil {
 		return nil, fmt.Errorf("introspect tables: %w", err)
 	}
@@ -101,47 +101,123 @@ func (s *sqliteSourceDB) IntrospectSchema(db *sql.DB, _ string) (*Schema, error)
 		return nil, fmt.Errorf("introspect foreign keys across %d tables: %w", len(tableNames), err)
 	}
 
+	checksByTable, err := introspectSQLiteCheckConstraintsByTable(db, s.identName)
+	if err != nil {
+		return nil, fmt.Errorf("introspect check constraints: %w", err)
+	}
+
+	sequences, err := introspectSQLiteSequences(db)
+	if err != nil {
+		return nil, fmt.Errorf("introspect sqlite_sequence: %w", err)
+	}
+
 	for i := range tables {
 		t := &tables[i]
 		t.Columns = columnsByTable[t.SourceName]
 		t.PrimaryKey = primaryKeysByTable[t.SourceName]
 		t.Indexes = indexesByTable[t.SourceName]
 		t.ForeignKeys = foreignKeysByTable[t.SourceName]
+		t.CheckConstraints = checksByTable[t.SourceName]
+		if seq, ok := sequences[t.SourceName]; ok {
+			t.AutoIncrementNext = seq + 1
+		}
+		if cols, ok := ftsColumns[t.SourceName]; ok {
+			addSQLiteFTS5Index(t, cols, s.identName)
+		}
 	}
 
 	return &Schema{Tables: tables}, nil
 }
 
+// introspectSQLiteSequences reads the last AUTOINCREMENT value per table from
+// sqlite_sequence, which only exists once an AUTOINCREMENT table was created.
+func introspectSQLiteSequences(db *sql.DB) (map[string]int64, error) {
+	var exists int
+	if err := db.QueryRow("SELECT COUNT(*) FROM sqlite_master WHERE type='table' AND name='sqlite_sequence'").Scan(&exists); err != nil {
+		return nil, err
+	}
+	sequences := make(map[string]int64)
+	if exists == 0 {
+		return sequences, nil
+	}
+	rows, err := db.Query("SELECT name, seq FROM sqlite_sequence")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		var seq int64
+		if err := rows.Scan(&name, &seq); err != nil {
+			return nil, err
+		}
+		sequences[name] = seq
+	}
+	return sequences, rows.Err()
+}
+
+// addSQLiteFTS5Index types the untyped columns of an FTS5 table as text and
+// records its full-text index.
+func addSQLiteFTS5Index(t *Table, columns []string, identName func(string) string) {
+	for i := range t.Columns {
+		if t.Columns[i].ColumnType == "" {
+			t.Columns[i].DataType = "text"
+			t.Columns[i].ColumnType = "text"
+		}
+	}
+	if len(columns) == 0 {
+		return
+	}
+	idx := Index{Name: identName(t.SourceName + "_fts"), SourceName: t.SourceName, Type: "FULLTEXT"}
+	for _, col := range columns {
+		idx.Columns = append(idx.Columns, identName(col))
+		idx.ColumnOrders = append(idx.ColumnOrders, "ASC")
+	}
+	t.Indexes = append(t.Indexes, idx)
+}
+
 func (s *sqliteSourceDB) IntrospectSourceObjects(db *sql.DB, _ string) (*SourceObjects, error) {
-	objs := &SourceObjects{}
+	objs := &SourceObjects{ViewDefinitions: map[string]string{}, SchemaName: "main"}
 
-	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type='view' ORDER BY name")
+	rows, err := db.Query("SELECT name, COALESCE(sql, '') FROM sqlite_master WHERE type='view' ORDER BY name")
 	if err != nil {
 		return nil, fmt.Errorf("introspect views: %w", err)
 	}
 	defer rows.Close()
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
+		var name, definition string
+		if err := rows.Scan(&name, &definition); err != nil {
 			return nil, err
 		}
 		objs.Views = append(objs.Views, name)
+		if definition != "" {
+			objs.ViewDefinitions[name] = definition
+		}
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 
-	rows2, err := db.Query("SELECT name FROM sqlite_master WHERE type='trigger' ORDER BY name")
+	objs.TriggerDefinitions = map[string]TriggerDefinition{}
+	rows2, err := db.Query("SELECT name, tbl_name, COALESCE(sql, '') FROM sqlite_master WHERE type='trigger' ORDER BY name")
 	if err != nil {
 		return nil, fmt.Errorf("introspect triggers: %w", err)
 	}
 	defer rows2.Close()
 	for rows2.Next() {
-		var name string
-		if err := rows2.Scan(&name); err != nil {
+		var name, table, definition string
+		if err := rows2.Scan(&name, &table, &definition); err != nil {
 			return nil, err
 		}
 		objs.Triggers = append(objs.Triggers, name)
+		if definition == "" {
+			continue
+		}
+		// An unparsable header still keeps the source SQL for the plan
+		// skeleton; translation then fails on the missing events.
+		def, _ := parseSQLiteTrigger(definition)
+		def.Table = table
+		objs.TriggerDefinitions[name] = def
 	}
 	if err := rows2.Err(); err != nil {
 		return nil, err
@@ -264,25 +340,137 @@ func sqliteReadOnlyURI(dsn string) (string, error) {
 
 // --- Schema introspection ---
 
-func introspectSQLiteTables(db *sql.DB, identName func(string) string) ([]Table, error) {
-	rows, err := db.Query("SELECT name FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+// introspectSQLiteTables lists the tables to migrate. FTS5 virtual tables are
+// migrated as plain tables and their indexed columns returned by table name;
+// their shadow tables are internal to SQLite and left out.
+func introspectSQLiteTables(db *sql.DB, identName func(string) string) ([]Table, map[string][]string, error) {
+	rows, err := db.Query("SELECT name, COALESCE(sql, '') FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer rows.Close()
 
-	var tables []Table
+	type sqliteTableRow struct{ name, createSQL string }
+	var all []sqliteTableRow
+	ftsColumns := make(map[string][]string)
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
-			return nil, err
+		var row sqliteTableRow
+		if err := rows.Scan(&row.name, &row.createSQL); err != nil {
+			return nil, nil, err
+		}
+		all = append(all, row)
+		if cols, ok := parseSQLiteFTS5Table(row.createSQL); ok {
+			ftsColumns[row.name] = cols
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	var tables []Table
+	for _, row := range all {
+		if isSQLiteFTS5Shadow(row.name, ftsColumns) {
+			continue
 		}
+		comment, _ := parseSQLiteCreateTableComments(row.createSQL)
 		tables = append(tables, Table{
-			SourceName: name,
-			PGName:     identName(name),
+			SourceName: row.name,
+			PGName:     identName(row.name),
+			Comment:    comment,
 		})
 	}
-	return tables, rows.Err()
+	return tables, ftsColumns, nil
+}
+
+// sqliteFTS5ShadowSuffixes are the shadow tables FTS5 creates next to each
+// virtual table.
+var sqliteFTS5ShadowSuffixes = []string{"_data", "_idx", "_content", "_docsize", "_config"}
+
+func isSQLiteFTS5Shadow(name string, ftsColumns map[string][]string) bool {
+	for _, suffix := range sqliteFTS5ShadowSuffixes {
+		if base, ok := strings.CutSuffix(name, suffix); ok {
+			if _, isFTS := ftsColumns[base]; isFTS {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// parseSQLiteFTS5Table returns the full-text indexed columns of a
+// CREATE VIRTUAL TABLE ... USING fts5(...) statement. Options (key=value)
+// and UNINDEXED columns are left out. ok is false for any other table.
+func parseSQLiteFTS5Table(createSQL string) (columns []string, ok bool) {
+	upper := strings.ToUpper(createSQL)
+	if !strings.HasPrefix(strings.TrimSpace(upper), "CREATE VIRTUAL TABLE") {
+		return nil, false
+	}
+	using := findSQLKeyword(createSQL, "USING")
+	if using < 0 {
+		return nil, false
+	}
+	rest := strings.TrimSpace(createSQL[using+len("USING"):])
+	open := strings.IndexByte(rest, '(')
+	if open < 0 || !strings.EqualFold(strings.TrimSpace(rest[:open]), "fts5") {
+		return nil, false
+	}
+	body := rest[open+1:]
+	if close := strings.LastIndexByte(body, ')'); close >= 0 {
+		body = body[:close]
+	}
+
+	for _, arg := range splitSQLiteArgs(body) {
+		arg = strings.TrimSpace(arg)
+		if arg == "" || strings.Contains(arg, "=") {
+			continue
+		}
+		fields := strings.Fields(arg)
+		if len(fields) > 1 && strings.EqualFold(fields[len(fields)-1], "UNINDEXED") {
+			continue
+		}
+		columns = append(columns, unquoteSQLiteIdent(fields[0]))
+	}
+	return columns, true
+}
+
+// splitSQLiteArgs splits a module argument list at top-level commas.
+func splitSQLiteArgs(s string) []string {
+	var args []string
+	var quote byte
+	depth, start := 0, 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '[':
+			quote = ']'
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			args = append(args, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(args, s[start:])
+}
+
+func unquoteSQLiteIdent(name string) string {
+	if len(name) >= 2 {
+		switch {
+		case name[0] == '"' && name[len(name)-1] == '"',
+			name[0] == '`' && name[len(name)-1] == '`',
+			name[0] == '[' && name[len(name)-1] == ']':
+			return name[1 : len(name)-1]
+		}
+	}
+	return name
 }
 
 func sqliteTableNames(tables []Table) []string {
@@ -402,6 +590,10 @@ func introspectSQLiteColumnsByTable(db *sql.DB, tableNames []string, identName f
 				return nil, nil, err
 			}
 
+			if hidden == 1 {
+				// Hidden columns of virtual tables (FTS5 rank, ...).
+				continue
+			}
 			col := Column{
 				SourceName: name,
 				PGName:     identName(name),
@@ -434,6 +626,7 @@ func introspectSQLiteColumnsByTable(db *sql.DB, tableNames []string, identName f
 	}
 
 	autoIncrColsByTable := make(map[string]map[string]bool)
+	commentsByTable := make(map[string]map[string]string)
 	createSQLRows, err := db.Query("SELECT name, COALESCE(sql, '') FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
 	if err != nil {
 		return nil, nil, err
@@ -450,6 +643,9 @@ func introspectSQLiteColumnsByTable(db *sql.DB, tableNames []string, identName f
 		if colName := detectSQLiteAutoIncrementColumnFromSQL(createSQL); colName != "" {
 			autoIncrColsByTable[tableName] = map[string]bool{colName: true}
 		}
+		if _, colComments := parseSQLiteCreateTableComments(createSQL); len(colComments) > 0 {
+			commentsByTable[tableName] = colComments
+		}
 	}
 	if err := createSQLRows.Err(); err != nil {
 		return nil, nil, err
@@ -474,13 +670,17 @@ func introspectSQLiteColumnsByTable(db *sql.DB, tableNames []string, identName f
 
 	for tableName, cols := range colsByTable {
 		autoIncrCols := autoIncrColsByTable[tableName]
-		if len(autoIncrCols) == 0 {
+		comments := commentsByTable[tableName]
+		if len(autoIncrCols) == 0 && len(comments) == 0 {
 			continue
 		}
 		for i := range cols {
 			if autoIncrCols[cols[i].SourceName] {
 				cols[i].Extra = "auto_increment"
 			}
+			if comment, ok := comments[strings.ToLower(cols[i].SourceName)]; ok {
+				cols[i].Comment = comment
+			}
 		}
 		colsByTable[tableName] = cols
 	}
@@ -720,6 +920,32 @@ func introspectSQLiteForeignKeysByTable(db *sql.DB, tableNames []string, identNa
 	return fksByTable, nil
 }
 
+// introspectSQLiteCheckConstraintsByTable parses CHECK clauses out of each
+// table's CREATE TABLE statement; SQLite exposes no catalog view for them.
+func introspectSQLiteCheckConstraintsByTable(db *sql.DB, identName func(string) string) (map[string][]CheckConstraint, error) {
+	rows, err := db.Query("SELECT name, COALESCE(sql, '') FROM sqlite_master WHERE type='table' AND name NOT LIKE 'sqlite_%' ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	checksByTable := make(map[string][]CheckConstraint)
+	for rows.Next() {
+		var tableName, createSQL string
+		if err := rows.Scan(&tableName, &createSQL); err != nil {
+			return nil, err
+		}
+		checks := parseSQLiteCheckConstraints(tableName, createSQL)
+		for i := range checks {
+			checks[i].Name = identName(checks[i].SourceName)
+		}
+		if len(checks) > 0 {
+			checksByTable[tableName] = checks
+		}
+	}
+	return checksByTable, rows.Err()
+}
+
 // normalizeAffinity extracts the base type name for SQLite's flexible type system.
 func normalizeAffinity(declaredType string) string {
 	dt := strings.TrimSpace(declaredType)
@@ -776,6 +1002,108 @@ func detectSQLiteAutoIncrementColumnFromSQL(createSQL string) string {
 	return ""
 }
 
+// parseSQLiteCreateTableComments recovers documentation from the `--`
+// comments SQLite keeps verbatim in sqlite_master.sql. A comment on the line
+// of the opening parenthesis documents the table; a comment on the line where
+// a column definition starts documents that column. Column keys are
+// lowercased source names. Comments on their own line are ignored because
+// they cannot be attributed reliably.
+func parseSQLiteCreateTableComments(createSQL string) (string, map[string]string) {
+	var tableComment string
+	colComments := make(map[string]string)
+
+	depth := 0
+	line, openLine := 0, -1
+	segStart := false
+	currentCol, currentLine := "", -1
+
+	for i := 0; i < len(createSQL); {
+		c := createSQL[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case c == '-' && strings.HasPrefix(createSQL[i:], "--"):
+			end := strings.IndexByte(createSQL[i:], '\n')
+			if end < 0 {
+				end = len(createSQL) - i
+			}
+			text := strings.TrimSpace(createSQL[i+2 : i+end])
+			i += end
+			if depth != 1 || text == "" {
+				continue
+			}
+			switch {
+			case currentCol != "" && currentLine == line:
+				if prev := colComments[currentCol]; prev != "" {
+					text = prev + " " + text
+				}
+				colComments[currentCol] = text
+			case currentCol == "" && openLine == line:
+				tableComment = text
+			}
+		case c == '/' && strings.HasPrefix(createSQL[i:], "/*"):
+			end := strings.Index(createSQL[i+2:], "*/")
+			if end < 0 {
+				return tableComment, colComments
+			}
+			line += strings.Count(createSQL[i:i+2+end], "\n")
+			i += end + 4
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closeChar := c
+			if c == '[' {
+				closeChar = ']'
+			}
+			text, n, err := scanQuoted(createSQL[i:], c, closeChar, false)
+			if err != nil {
+				return tableComment, colComments
+			}
+			if segStart && depth == 1 && c != '\'' {
+				currentCol, currentLine = strings.ToLower(text), line
+				segStart = false
+			}
+			line += strings.Count(createSQL[i:i+n], "\n")
+			i += n
+		case c == '(':
+			depth++
+			if depth == 1 {
+				openLine = line
+				segStart = true
+			}
+			i++
+		case c == ')':
+			depth--
+			i++
+		case c == ',' && depth == 1:
+			segStart = true
+			i++
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+		default:
+			if segStart && depth == 1 {
+				j := i
+				for j < len(createSQL) && (createSQL[j] == '_' || createSQL[j] == '$' || createSQL[j] >= 'a' && createSQL[j] <= 'z' || createSQL[j] >= 'A' && createSQL[j] <= 'Z' || createSQL[j] >= '0' && createSQL[j] <= '9') {
+					j++
+				}
+				word := createSQL[i:j]
+				switch strings.ToUpper(word) {
+				case "CONSTRAINT", "PRIMARY", "UNIQUE", "CHECK", "FOREIGN":
+					currentCol = ""
+				default:
+					currentCol, currentLine = strings.ToLower(word), line
+				}
+				segStart = false
+				if j > i {
+					i = j
+					continue
+				}
+			}
+			i++
+		}
+	}
+	return tableComment, colComments
+}
+
 // --- Type mapping ---
 
 func sqliteMapType(col Column, typeMap TypeMappingConfig) (string, error) {
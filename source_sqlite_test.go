@@ -183,6 +183,8 @@ func TestSQLiteIntrospectSchema(t *testing.T) {
 			FOREIGN KEY (user_id) REFERENCES users(id)
 		)`,
 		`CREATE INDEX idx_posts_title ON posts(title)`,
+		`INSERT INTO users (id, name) VALUES (41, 'deleted')`,
+		`DELETE FROM users`,
 	}
 	for _, stmt := range stmts {
 		if _, err := db.Exec(stmt); err != nil {
@@ -210,6 +212,9 @@ func TestSQLiteIntrospectSchema(t *testing.T) {
 	if users == nil {
 		t.Fatal("users table not found")
 	}
+	if users.AutoIncrementNext != 42 {
+		t.Errorf("users AutoIncrementNext = %d, want 42 from sqlite_sequence", users.AutoIncrementNext)
+	}
 
 	if len(users.Columns) != 3 {
 		t.Fatalf("users: expected 3 columns, got %d", len(users.Columns))
@@ -294,6 +299,42 @@ func TestSQLiteIntrospectCompositePK(t *testing.T) {
 	}
 }
 
+func TestSQLiteIntrospectComments(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE "users" ( -- registered users
+		id INTEGER PRIMARY KEY, -- surrogate key
+		-- this line documents nothing
+		"Email" TEXT NOT NULL DEFAULT '--not a comment', -- login e-mail
+		bio TEXT /* inline, ignored */,
+		CONSTRAINT uq_email UNIQUE ("Email") -- constraint note
+	)`)
+	if err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	src := &sqliteSourceDB{}
+	schema, err := src.IntrospectSchema(db, "")
+	if err != nil {
+		t.Fatalf("IntrospectSchema: %v", err)
+	}
+
+	users := findSchemaTable(t, schema, "users")
+	if users.Comment != "registered users" {
+		t.Fatalf("table comment = %q, want registered users", users.Comment)
+	}
+	want := map[string]string{"id": "surrogate key", "Email": "login e-mail", "bio": ""}
+	for _, col := range users.Columns {
+		if col.Comment != want[col.SourceName] {
+			t.Errorf("column %s comment = %q, want %q", col.SourceName, col.Comment, want[col.SourceName])
+		}
+	}
+}
+
 func TestSQLiteIntrospectSchema_BatchedEdgeCases(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "batched.db")
@@ -447,9 +488,19 @@ func TestSQLiteIntrospectSourceObjects(t *testing.T) {
 	if len(objs.Views) != 1 || objs.Views[0] != "v_users" {
 		t.Errorf("views = %v, want [v_users]", objs.Views)
 	}
+	if def := objs.ViewDefinitions["v_users"]; def != "CREATE VIEW v_users AS SELECT id, name FROM users" {
+		t.Errorf("view definition = %q", def)
+	}
+	if objs.SchemaName != "main" {
+		t.Errorf("schema name = %q, want main", objs.SchemaName)
+	}
 	if len(objs.Triggers) != 1 || objs.Triggers[0] != "trg_users" {
 		t.Errorf("triggers = %v, want [trg_users]", objs.Triggers)
 	}
+	def := objs.TriggerDefinitions["trg_users"]
+	if def.Table != "users" || def.Timing != "AFTER" || len(def.Events) != 1 || def.Events[0] != "INSERT" || def.Body != "SELECT 1;" {
+		t.Errorf("trigger definition = %+v", def)
+	}
 	if len(objs.Routines) != 0 {
 		t.Errorf("routines = %v, want empty", objs.Routines)
 	}
@@ -572,3 +623,40 @@ func TestSQLiteOpenDB_ValidFile(t *testing.T) {
 		t.Fatalf("Ping() error: %v", err)
 	}
 }
+
+func TestSQLiteIntrospectFTS5(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE VIRTUAL TABLE docs USING fts5("Title", body, path UNINDEXED, tokenize = 'porter unicode61')`)
+	if err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	src := &sqliteSourceDB{snakeCaseIDs: true}
+	schema, err := src.IntrospectSchema(db, "")
+	if err != nil {
+		t.Fatalf("IntrospectSchema: %v", err)
+	}
+	if len(schema.Tables) != 1 {
+		t.Fatalf("tables = %d, want only docs (shadow tables skipped)", len(schema.Tables))
+	}
+
+	docs := findSchemaTable(t, schema, "docs")
+	var cols []string
+	for _, col := range docs.Columns {
+		cols = append(cols, col.PGName+":"+col.DataType)
+	}
+	if got := strings.Join(cols, ","); got != "title:text,body:text,path:text" {
+		t.Fatalf("columns = %s", got)
+	}
+	if len(docs.Indexes) != 1 {
+		t.Fatalf("indexes = %d, want 1", len(docs.Indexes))
+	}
+	if idx := docs.Indexes[0]; idx.Type != "FULLTEXT" || idx.Name != "docs_fts" || strings.Join(idx.Columns, ",") != "title,body" {
+		t.Fatalf("full-text index = %+v", idx)
+	}
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// translatedTrigger is a source trigger with its PL/pgSQL translation.
+type translatedTrigger struct {
+	SourceName string
+	PGName     string
+	Table      string // PG table name, empty when the table is unknown
+	Definition string
+	SQL        string // function and trigger DDL; empty when not translated
+	Reason     string // why SQL is empty
+}
+
+// translateTriggers translates every introspected trigger into a PL/pgSQL
+// function plus CREATE TRIGGER. Only simple row-level bodies are handled:
+// assignments to NEW columns and INSERT/UPDATE/DELETE statements on other
+// tables (history copies, counters). Anything with control flow is left for
+// manual porting.
+func translateTriggers(objs *SourceObjects, schema *Schema, src SourceDB, pgSchema string) []translatedTrigger {
+	if objs == nil || len(objs.Triggers) == 0 {
+		return nil
+	}
+	r := newViewRewriter(schema, src, objs.SchemaName, pgSchema)
+	triggers := make([]translatedTrigger, 0, len(objs.Triggers))
+	for _, name := range objs.Triggers {
+		tr := translatedTrigger{SourceName: name, PGName: r.identName(name)}
+		def, ok := objs.TriggerDefinitions[name]
+		if !ok {
+			tr.Reason = "definition unavailable (missing privileges or encrypted trigger)"
+			triggers = append(triggers, tr)
+			continue
+		}
+		tr.Definition = def.Definition
+		if t := findTableBySourceName(schema, def.Table); t != nil {
+			tr.Table = t.PGName
+		}
+		sql, err := r.translateTrigger(tr.PGName, def, schema)
+		if err != nil {
+			tr.Reason = err.Error()
+		}
+		tr.SQL = sql
+		triggers = append(triggers, tr)
+	}
+	return triggers
+}
+
+// findTableBySourceName looks a table up by its case-insensitive source name.
+func findTableBySourceName(schema *Schema, name string) *Table {
+	if schema == nil {
+		return nil
+	}
+	for i := range schema.Tables {
+		if strings.EqualFold(schema.Tables[i].SourceName, name) {
+			return &schema.Tables[i]
+		}
+	}
+	return nil
+}
+
+// translateTrigger returns the PL/pgSQL function and CREATE TRIGGER for one
+// source trigger.
+func (r *viewRewriter) translateTrigger(pgName string, def TriggerDefinition, schema *Schema) (string, error) {
+	if r.dialect == "mssql" {
+		return "", fmt.Errorf("T-SQL triggers run once per statement over the inserted/deleted tables; port them manually")
+	}
+	table := findTableBySourceName(schema, def.Table)
+	if table == nil {
+		return "", fmt.Errorf("table %s is not migrated", def.Table)
+	}
+	timing := strings.ToUpper(def.Timing)
+	if timing != "BEFORE" && timing != "AFTER" {
+		return "", fmt.Errorf("%s triggers are not translated", timing)
+	}
+	if len(def.Events) == 0 {
+		return "", fmt.Errorf("trigger has no events")
+	}
+
+	var stmts []string
+	for _, stmt := range splitStatements(stripSQLComments(def.Body)) {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	if len(stmts) == 0 {
+		return "", fmt.Errorf("empty trigger body")
+	}
+
+	var lines []string
+	// SQLite cannot assign to NEW, so "touch" triggers are written as an
+	// AFTER trigger that updates the same row again. In PostgreSQL that
+	// would recurse; the assignments move into a BEFORE trigger instead.
+	if timing == "AFTER" && len(stmts) == 1 && !triggerHasEvent(def, "DELETE") {
+		assigns, ok, err := r.selfUpdateAssignments(stmts[0], table)
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			timing, lines = "BEFORE", assigns
+		}
+	}
+	if lines == nil {
+		for _, stmt := range stmts {
+			out, err := r.triggerStatement(stmt, table, timing)
+			if err != nil {
+				return "", err
+			}
+			lines = append(lines, out...)
+		}
+	}
+
+	ret := "NULL"
+	if timing == "BEFORE" {
+		ret = "NEW"
+		if len(def.Events) == 1 && strings.EqualFold(def.Events[0], "DELETE") {
+			ret = "OLD"
+		}
+	}
+
+	events := make([]string, len(def.Events))
+	for i, ev := range def.Events {
+		events[i] = strings.ToUpper(ev)
+		if events[i] == "UPDATE" && len(def.UpdateOf) > 0 {
+			cols := make([]string, len(def.UpdateOf))
+			for j, c := range def.UpdateOf {
+				pg, ok := triggerColumn(table, c)
+				if !ok {
+					return "", fmt.Errorf("unknown column %s in UPDATE OF", c)
+				}
+				cols[j] = pg
+			}
+			events[i] += " OF " + strings.Join(cols, ", ")
+		}
+	}
+	when := ""
+	if def.When != "" {
+		tokens, err := tokenizeCheckExpression(def.When, r.dialect)
+		if err != nil {
+			return "", fmt.Errorf("WHEN clause: %w", err)
+		}
+		cond, err := r.rewrite(tokens)
+		if err != nil {
+			return "", fmt.Errorf("WHEN clause: %w", err)
+		}
+		when = "\nWHEN (" + cond + ")"
+	}
+
+	funcName := pgIdent(r.pgSchema) + "." + pgIdent(pgName+"_fn")
+	var b strings.Builder
+	fmt.Fprintf(&b, "CREATE OR REPLACE FUNCTION %s() RETURNS TRIGGER AS $fn$\nBEGIN\n", funcName)
+	for _, line := range lines {
+		fmt.Fprintf(&b, "    %s\n", line)
+	}
+	fmt.Fprintf(&b, "    RETURN %s;\nEND;\n$fn$ LANGUAGE plpgsql;\n\n", ret)
+	fmt.Fprintf(&b, "CREATE TRIGGER %s %s %s ON %s.%s\nFOR EACH ROW%s\nEXECUTE FUNCTION %s();\n",
+		pgIdent(pgName), timing, strings.Join(events, " OR "), pgIdent(r.pgSchema), pgIdent(table.PGName), when, funcName)
+	return b.String(), nil
+}
+
+func triggerHasEvent(def TriggerDefinition, event string) bool {
+	for _, ev := range def.Events {
+		if strings.EqualFold(ev, event) {
+			return true
+		}
+	}
+	return false
+}
+
+// triggerColumn returns the quoted PG name of a column of table.
+func triggerColumn(table *Table, name string) (string, bool) {
+	for _, c := range table.Columns {
+		if strings.EqualFold(c.SourceName, name) {
+			return pgIdent(c.PGName), true
+		}
+	}
+	return "", false
+}
+
+// triggerStatement translates one statement of a trigger body.
+func (r *viewRewriter) triggerStatement(stmt string, table *Table, timing string) ([]string, error) {
+	tokens, err := tokenizeCheckExpression(stmt, r.dialect)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 || tokens[0].kind != checkTokenWord {
+		return nil, fmt.Errorf("unsupported statement %q", stmt)
+	}
+	verb := strings.ToUpper(tokens[0].text)
+	switch verb {
+	case "SET":
+		if timing != "BEFORE" {
+			return nil, fmt.Errorf("SET NEW.column needs a BEFORE trigger")
+		}
+		var lines []string
+		for _, assign := range viewSplitArgs(tokens[1:]) {
+			col, expr, err := r.newAssignment(assign, table)
+			if err != nil {
+				return nil, err
+			}
+			lines = append(lines, fmt.Sprintf("NEW.%s := %s;", col, expr))
+		}
+		return lines, nil
+	case "INSERT", "UPDATE", "DELETE":
+		target, err := triggerTarget(tokens)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(target, table.SourceName) {
+			return nil, fmt.Errorf("%s on the trigger's own table is not translated", verb)
+		}
+		out, err := r.rewrite(tokens)
+		if err != nil {
+			return nil, err
+		}
+		return []string{out + ";"}, nil
+	}
+	return nil, fmt.Errorf("unsupported statement %s", verb)
+}
+
+// triggerTarget returns the unqualified table name an INSERT, UPDATE, or
+// DELETE statement writes to.
+func triggerTarget(tokens []checkToken) (string, error) {
+	i := 1
+	if i < len(tokens) && strings.EqualFold(tokens[i].text, "OR") {
+		return "", fmt.Errorf("%s OR ... is not translated", strings.ToUpper(tokens[0].text))
+	}
+	if i < len(tokens) && (strings.EqualFold(tokens[i].text, "INTO") || strings.EqualFold(tokens[i].text, "FROM")) {
+		i++
+	}
+	name := ""
+	for i < len(tokens) && (tokens[i].kind == checkTokenWord || tokens[i].kind == checkTokenIdent) {
+		name = tokens[i].text
+		if i+1 < len(tokens) && tokens[i+1].kind == checkTokenOp && tokens[i+1].text == "." {
+			i += 2
+			continue
+		}
+		break
+	}
+	if name == "" {
+		return "", fmt.Errorf("cannot find the target table of %s", strings.ToUpper(tokens[0].text))
+	}
+	return name, nil
+}
+
+// newAssignment parses "NEW.col = expr" and returns the quoted PG column and
+// the translated expression.
+func (r *viewRewriter) newAssignment(tokens []checkToken, table *Table) (string, string, error) {
+	if len(tokens) < 5 || !strings.EqualFold(tokens[0].text, "NEW") || tokens[1].text != "." ||
+		tokens[3].kind != checkTokenOp || tokens[3].text != "=" {
+		return "", "", fmt.Errorf("only SET NEW.column = expression is translated")
+	}
+	col, ok := triggerColumn(table, tokens[2].text)
+	if !ok {
+		return "", "", fmt.Errorf("unknown column %s", tokens[2].text)
+	}
+	expr, err := r.rewrite(tokens[4:])
+	if err != nil {
+		return "", "", err
+	}
+	return col, expr, nil
+}
+
+// selfUpdateAssignments recognizes "UPDATE own_table SET a = expr, ... WHERE
+// key = NEW.key" and returns the equivalent NEW assignments. ok is false when
+// stmt has another shape.
+func (r *viewRewriter) selfUpdateAssignments(stmt string, table *Table) ([]string, bool, error) {
+	tokens, err := tokenizeCheckExpression(stmt, r.dialect)
+	if err != nil || len(tokens) < 2 || !strings.EqualFold(tokens[0].text, "UPDATE") {
+		return nil, false, nil
+	}
+	if target, err := triggerTarget(tokens); err != nil || !strings.EqualFold(target, table.SourceName) {
+		return nil, false, nil
+	}
+	set, where := -1, -1
+	for i, tok := range tokens {
+		if tok.kind != checkTokenWord {
+			continue
+		}
+		switch {
+		case set < 0 && strings.EqualFold(tok.text, "SET"):
+			set = i
+		case set >= 0 && strings.EqualFold(tok.text, "WHERE"):
+			where = i
+		}
+	}
+	if set < 0 || where < 0 || !triggerSameRowCondition(tokens[where+1:]) {
+		return nil, false, nil
+	}
+
+	var lines []string
+	for _, assign := range viewSplitArgs(tokens[set+1 : where]) {
+		if len(assign) < 3 || assign[1].kind != checkTokenOp || assign[1].text != "=" {
+			return nil, false, nil
+		}
+		col, ok := triggerColumn(table, assign[0].text)
+		if !ok {
+			return nil, false, fmt.Errorf("unknown column %s", assign[0].text)
+		}
+		expr := assign[2:]
+		for i, tok := range expr {
+			// Unqualified columns would mean the stored row, which is not
+			// in scope inside the BEFORE trigger.
+			if tok.kind != checkTokenWord && tok.kind != checkTokenIdent {
+				continue
+			}
+			if i > 0 && expr[i-1].text == "." || i+1 < len(expr) && expr[i+1].kind == checkTokenLParen {
+				continue
+			}
+			if _, isCol := triggerColumn(table, tok.text); isCol {
+				return nil, false, fmt.Errorf("self-update references column %s without NEW/OLD", tok.text)
+			}
+		}
+		out, err := r.rewrite(expr)
+		if err != nil {
+			return nil, false, err
+		}
+		lines = append(lines, fmt.Sprintf("NEW.%s := %s;", col, out))
+	}
+	return lines, true, nil
+}
+
+// triggerSameRowCondition reports whether tokens are "key = NEW.key" (either
+// way around) for a single column.
+func triggerSameRowCondition(tokens []checkToken) bool {
+	if len(tokens) != 5 {
+		return false
+	}
+	isNewRef := func(t []checkToken) (string, bool) {
+		return t[2].text, strings.EqualFold(t[0].text, "NEW") && t[1].text == "."
+	}
+	if tokens[1].text == "=" {
+		if col, ok := isNewRef(tokens[2:]); ok {
+			return strings.EqualFold(col, tokens[0].text)
+		}
+	}
+	if tokens[3].text == "=" {
+		if col, ok := isNewRef(tokens[:3]); ok {
+			return strings.EqualFold(col, tokens[4].text)
+		}
+	}
+	return false
+}
+
+// parseSQLiteTrigger extracts timing, events, WHEN clause, and body from a
+// CREATE TRIGGER statement as stored in sqlite_master.
+func parseSQLiteTrigger(definition string) (TriggerDefinition, error) {
+	def := TriggerDefinition{Definition: definition}
+	text := stripSQLComments(definition)
+	begin := findSQLKeyword(text, "BEGIN")
+	end := strings.LastIndex(strings.ToUpper(text), "END")
+	if begin < 0 || end < begin {
+		return def, fmt.Errorf("trigger body not found")
+	}
+	def.Body = strings.TrimSpace(text[begin+len("BEGIN") : end])
+
+	tokens, err := tokenizeCheckExpression(text[:begin], "sqlite")
+	if err != nil {
+		return def, err
+	}
+	def.Timing = "BEFORE" // SQLite default
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		if tok.kind != checkTokenWord {
+			continue
+		}
+		switch upper := strings.ToUpper(tok.text); upper {
+		case "BEFORE", "AFTER":
+			def.Timing = upper
+		case "INSTEAD":
+			def.Timing = "INSTEAD OF"
+			i++
+		case "INSERT", "DELETE":
+			def.Events = []string{upper}
+		case "UPDATE":
+			def.Events = []string{upper}
+			if i+1 < len(tokens) && strings.EqualFold(tokens[i+1].text, "OF") {
+				i += 2
+				for ; i < len(tokens) && !strings.EqualFold(tokens[i].text, "ON"); i++ {
+					if tokens[i].kind != checkTokenComma {
+						def.UpdateOf = append(def.UpdateOf, tokens[i].text)
+					}
+				}
+				i--
+			}
+		case "WHEN":
+			// The WHEN condition runs up to BEGIN.
+			pos := findSQLKeyword(text[:begin], "WHEN")
+			def.When = strings.TrimSpace(text[pos+len("WHEN") : begin])
+			i = len(tokens)
+		}
+	}
+	return def, nil
+}
+
+// findSQLKeyword returns the byte offset of the first occurrence of keyword as
+// a whole word outside quotes, or -1.
+func findSQLKeyword(s, keyword string) int {
+	isWord := func(c byte) bool {
+		return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+	}
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if quote != 0 {
+			if c == quote {
+				quote = 0
+			}
+			continue
+		}
+		switch c {
+		case '\'', '"', '`':
+			quote = c
+			continue
+		case '[':
+			quote = ']'
+			continue
+		}
+		if (i == 0 || !isWord(s[i-1])) && len(s)-i >= len(keyword) && strings.EqualFold(s[i:i+len(keyword)], keyword) &&
+			(i+len(keyword) == len(s) || !isWord(s[i+len(keyword)])) {
+			return i
+		}
+	}
+	return -1
+}
+
+// triggerBodyWithoutBlock strips an enclosing BEGIN ... END from a MySQL
+// ACTION_STATEMENT.
+func triggerBodyWithoutBlock(body string) string {
+	trimmed := strings.TrimSpace(body)
+	if findSQLKeyword(trimmed, "BEGIN") != 0 {
+		return trimmed
+	}
+	end := strings.LastIndex(strings.ToUpper(trimmed), "END")
+	if end < len("BEGIN") {
+		return trimmed
+	}
+	return strings.TrimSpace(trimmed[len("BEGIN"):end])
+}
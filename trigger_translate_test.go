@@ -0,0 +1,186 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func triggerTestSchema() *Schema {
+	return &Schema{Tables: []Table{
+		{
+			SourceName: "orders",
+			PGName:     "orders",
+			Columns: []Column{
+				{SourceName: "id", PGName: "id"},
+				{SourceName: "status", PGName: "status"},
+				{SourceName: "updated_at", PGName: "updated_at"},
+				{SourceName: "version", PGName: "version"},
+			},
+		},
+		{
+			SourceName: "order_history",
+			PGName:     "order_history",
+			Columns: []Column{
+				{SourceName: "order_id", PGName: "order_id"},
+				{SourceName: "old_status", PGName: "old_status"},
+			},
+		},
+		{
+			SourceName: "stats",
+			PGName:     "stats",
+			Columns:    []Column{{SourceName: "name", PGName: "name"}, {SourceName: "n", PGName: "n"}},
+		},
+	}}
+}
+
+func TestTranslateTrigger(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     SourceDB
+		def     TriggerDefinition
+		want    []string
+		wantErr string
+	}{
+		{
+			name: "mysql audit timestamp",
+			src:  mysqlSrc,
+			def: TriggerDefinition{
+				Table: "orders", Timing: "BEFORE", Events: []string{"UPDATE"},
+				Body: "SET NEW.updated_at = NOW(), NEW.version = OLD.version + 1",
+			},
+			want: []string{
+				`CREATE OR REPLACE FUNCTION "app"."trg_fn"() RETURNS TRIGGER AS $fn$`,
+				`    NEW."updated_at" := LOCALTIMESTAMP;`,
+				`    NEW."version" := OLD."version" + 1;`,
+				`    RETURN NEW;`,
+				`CREATE TRIGGER "trg" BEFORE UPDATE ON "app"."orders"`,
+				`EXECUTE FUNCTION "app"."trg_fn"();`,
+			},
+		},
+		{
+			name: "mysql copy to history",
+			src:  mysqlSrc,
+			def: TriggerDefinition{
+				Table: "orders", Timing: "AFTER", Events: []string{"UPDATE"},
+				Body: "INSERT INTO `order_history` (`order_id`, `old_status`) VALUES (OLD.`id`, OLD.`status`)",
+			},
+			want: []string{
+				`    INSERT INTO "app"."order_history" ("order_id", "old_status") VALUES (OLD."id", OLD."status");`,
+				`    RETURN NULL;`,
+				`CREATE TRIGGER "trg" AFTER UPDATE ON "app"."orders"`,
+			},
+		},
+		{
+			name: "sqlite counter",
+			src:  &sqliteSourceDB{},
+			def: TriggerDefinition{
+				Table: "orders", Timing: "AFTER", Events: []string{"INSERT"},
+				Body: "UPDATE stats SET n = n + 1 WHERE name = 'orders';",
+			},
+			want: []string{
+				`    UPDATE "app"."stats" SET "n" = "n" + 1 WHERE "name" = 'orders';`,
+				`CREATE TRIGGER "trg" AFTER INSERT ON "app"."orders"`,
+			},
+		},
+		{
+			name: "sqlite touch becomes before trigger",
+			src:  &sqliteSourceDB{},
+			def: TriggerDefinition{
+				Table: "orders", Timing: "AFTER", Events: []string{"UPDATE"}, UpdateOf: []string{"status"},
+				When: "OLD.status <> NEW.status",
+				Body: "UPDATE orders SET updated_at = CURRENT_TIMESTAMP WHERE id = NEW.id;",
+			},
+			want: []string{
+				`    NEW."updated_at" := CURRENT_TIMESTAMP;`,
+				`    RETURN NEW;`,
+				`CREATE TRIGGER "trg" BEFORE UPDATE OF "status" ON "app"."orders"`,
+				`WHEN (OLD."status" <> NEW."status")`,
+			},
+		},
+		{
+			name: "self update reading stored row",
+			src:  &sqliteSourceDB{},
+			def: TriggerDefinition{
+				Table: "orders", Timing: "AFTER", Events: []string{"UPDATE"},
+				Body: "UPDATE orders SET version = version + 1 WHERE id = NEW.id;",
+			},
+			wantErr: "without NEW/OLD",
+		},
+		{
+			name: "other write to own table",
+			src:  &sqliteSourceDB{},
+			def: TriggerDefinition{
+				Table: "orders", Timing: "AFTER", Events: []string{"DELETE"},
+				Body: "DELETE FROM orders WHERE id = OLD.id + 1;",
+			},
+			wantErr: "own table",
+		},
+		{
+			name: "control flow",
+			src:  mysqlSrc,
+			def: TriggerDefinition{
+				Table: "orders", Timing: "BEFORE", Events: []string{"INSERT"},
+				Body: "IF NEW.status = 'x' THEN SET NEW.version = 0; END IF",
+			},
+			wantErr: "unsupported statement IF",
+		},
+		{
+			name:    "mssql",
+			src:     &mssqlSourceDB{},
+			def:     TriggerDefinition{Table: "orders", Timing: "AFTER", Events: []string{"INSERT"}, Body: "SELECT 1"},
+			wantErr: "port them manually",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newViewRewriter(triggerTestSchema(), tt.src, "", "app")
+			got, err := r.translateTrigger("trg", tt.def, triggerTestSchema())
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("translateTrigger() error = %v, want containing %q\n%s", err, tt.wantErr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("translateTrigger() error: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("translateTrigger() missing %q in:\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseSQLiteTrigger(t *testing.T) {
+	def, err := parseSQLiteTrigger("CREATE TRIGGER trg_touch AFTER UPDATE OF status, version ON orders\nWHEN OLD.status <> NEW.status\nBEGIN\n  UPDATE orders SET updated_at = 'begin' WHERE id = NEW.id;\nEND")
+	if err != nil {
+		t.Fatalf("parseSQLiteTrigger() error: %v", err)
+	}
+	if def.Timing != "AFTER" {
+		t.Errorf("timing = %q, want AFTER", def.Timing)
+	}
+	if len(def.Events) != 1 || def.Events[0] != "UPDATE" {
+		t.Errorf("events = %v, want [UPDATE]", def.Events)
+	}
+	if strings.Join(def.UpdateOf, ",") != "status,version" {
+		t.Errorf("update of = %v", def.UpdateOf)
+	}
+	if def.When != "OLD.status <> NEW.status" {
+		t.Errorf("when = %q", def.When)
+	}
+	if def.Body != "UPDATE orders SET updated_at = 'begin' WHERE id = NEW.id;" {
+		t.Errorf("body = %q", def.Body)
+	}
+}
+
+func TestTriggerBodyWithoutBlock(t *testing.T) {
+	if got := triggerBodyWithoutBlock("BEGIN\n  SET NEW.a = 1;\nEND"); got != "SET NEW.a = 1;" {
+		t.Errorf("triggerBodyWithoutBlock() = %q", got)
+	}
+	if got := triggerBodyWithoutBlock("SET NEW.a = 1"); got != "SET NEW.a = 1" {
+		t.Errorf("triggerBodyWithoutBlock() = %q", got)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"strings"
@@ -13,10 +14,60 @@ import (
 
 // ValidationResult holds the outcome of a single table validation.
 type ValidationResult struct {
-	Table       string
-	SourceCount int64
-	TargetCount int64
-	CountMatch  bool
+	Table       string `json:"table"`
+	SourceCount int64  `json:"source_count"`
+	TargetCount int64  `json:"target_count"`
+	CountMatch  bool   `json:"count_match"`
+
+	// ChunkKey is the source column used to split checksum validation into
+	// ranges; empty when the table was hashed as a whole.
+	ChunkKey string `json:"chunk_key,omitempty"`
+	// ChecksumChunks is the number of ranges hashed (checksum mode only).
+	ChecksumChunks int `json:"checksum_chunks,omitempty"`
+	// ChunkMismatches lists the ranges whose digests differ (checksum mode only).
+	ChunkMismatches []ChunkChecksumMismatch `json:"chunk_mismatches,omitempty"`
+
+	// SampledRows is the number of source rows compared (sample mode only).
+	SampledRows int `json:"sampled_rows,omitempty"`
+	// RowDiffs lists column-level differences in sampled rows (sample mode only).
+	RowDiffs []SampleRowDiff `json:"row_diffs,omitempty"`
+
+	// AggregatesChecked is the number of column aggregates compared (aggregate mode only).
+	AggregatesChecked int `json:"aggregates_checked,omitempty"`
+	// AggregateMismatches lists column aggregates that differ (aggregate mode only).
+	AggregateMismatches []ColumnAggregateMismatch `json:"aggregate_mismatches,omitempty"`
+}
+
+// passed reports whether the table validated cleanly in every enabled check.
+func (r ValidationResult) passed() bool {
+	return r.CountMatch && len(r.ChunkMismatches) == 0 && len(r.RowDiffs) == 0 &&
+		len(r.AggregateMismatches) == 0
+}
+
+// validationConfig holds parameters for post-load validation.
+type validationConfig struct {
+	Src        SourceDB
+	SrcDSN     string
+	Pool       *pgxpool.Pool
+	Schema     *Schema
+	PGSchema   string
+	Mode       string // none|row_count|checksum|sample|aggregate
+	Workers    int
+	TypeMap    TypeMappingConfig
+	ChunkSize  int64
+	SampleSize int
+	// Quiet skips per-table result logging for callers that report results themselves.
+	Quiet bool
+	// SourceSnapshot, when set, is the single_tx transaction the data was
+	// copied in. Source reads run inside it, one table at a time.
+	SourceSnapshot *sql.Tx
+}
+
+// validationSource is the source handle validation reads through: either a
+// fresh connection pool or the migration's snapshot transaction.
+type validationSource interface {
+	dbQuerier
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
 }
 
 // validationWorkers returns the effective worker count for validation,
@@ -35,26 +86,34 @@ func buildSourceCountQuery(src SourceDB, table Table) string {
 	return fmt.Sprintf("SELECT COUNT(*) FROM %s", src.SourceTableRef(table))
 }
 
-// validateMigration runs post-load validation comparing source and target row counts.
-// Tables are validated in parallel with bounded concurrency. The workers parameter
-// controls maximum parallelism and is capped by source backend limits (e.g., SQLite
-// is always single-threaded).
-func validateMigration(ctx context.Context, src SourceDB, srcDSN string, pool *pgxpool.Pool, schema *Schema, pgSchema string, mode string, workers int) ([]ValidationResult, error) {
-	if mode == "none" || mode == "" {
+// validateMigration runs post-load validation comparing source and target tables
+// according to cfg.Mode. Tables are validated in parallel with bounded
+// concurrency. The worker count is capped by source backend limits (e.g.,
+// SQLite is always single-threaded).
+func validateMigration(ctx context.Context, cfg validationConfig) ([]ValidationResult, error) {
+	if cfg.Mode == "none" || cfg.Mode == "" {
 		return nil, nil
 	}
 
-	workers = validationWorkers(workers, src)
+	workers := validationWorkers(cfg.Workers, cfg.Src)
 
-	srcDB, err := src.OpenDB(srcDSN)
-	if err != nil {
-		return nil, fmt.Errorf("open source for validation: %w", err)
+	var srcDB validationSource
+	if cfg.SourceSnapshot != nil {
+		// A transaction is bound to one connection; read it sequentially.
+		workers = 1
+		srcDB = cfg.SourceSnapshot
+	} else {
+		db, err := cfg.Src.OpenDB(cfg.SrcDSN)
+		if err != nil {
+			return nil, fmt.Errorf("open source for validation: %w", err)
+		}
+		defer db.Close()
+		db.SetMaxOpenConns(workers)
+		srcDB = db
 	}
-	defer srcDB.Close()
-	srcDB.SetMaxOpenConns(workers)
 
 	start := time.Now()
-	results := make([]ValidationResult, len(schema.Tables))
+	results := make([]ValidationResult, len(cfg.Schema.Tables))
 
 	// Use a cancellable context so a failure in one goroutine stops the rest.
 	ctx, cancel := context.WithCancel(ctx)
@@ -70,7 +129,7 @@ func validateMigration(ctx context.Context, src SourceDB, srcDSN string, pool *p
 		cancel()
 	}
 
-	for i, t := range schema.Tables {
+	for i, t := range cfg.Schema.Tables {
 		wg.Add(1)
 		go func(idx int, tbl Table) {
 			defer wg.Done()
@@ -81,23 +140,11 @@ func validateMigration(ctx context.Context, src SourceDB, srcDSN string, pool *p
 			}
 			defer func() { <-sem }()
 
-			result := ValidationResult{Table: tbl.SourceName}
-
-			// Count source rows
-			srcQuery := buildSourceCountQuery(src, tbl)
-			if err := srcDB.QueryRowContext(ctx, srcQuery).Scan(&result.SourceCount); err != nil {
-				setErr(fmt.Errorf("count source rows for %s: %w", tbl.SourceName, err))
-				return
-			}
-
-			// Count target rows
-			pgQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s", pgIdent(pgSchema), pgIdent(tbl.PGName))
-			if err := pool.QueryRow(ctx, pgQuery).Scan(&result.TargetCount); err != nil {
-				setErr(fmt.Errorf("count target rows for %s: %w", tbl.PGName, err))
+			result, err := validateTable(ctx, cfg, srcDB, tbl)
+			if err != nil {
+				setErr(err)
 				return
 			}
-
-			result.CountMatch = result.SourceCount == result.TargetCount
 			results[idx] = result
 		}(i, t)
 	}
@@ -109,27 +156,106 @@ func validateMigration(ctx context.Context, src SourceDB, srcDSN string, pool *p
 	}
 
 	// Report results deterministically (in original table order)
-	var mismatches int
+	var failed []string
 	for _, r := range results {
-		if !r.CountMatch {
-			mismatches++
-			log.Printf("  MISMATCH: %s — source=%d target=%d", r.Table, r.SourceCount, r.TargetCount)
-		} else {
-			log.Printf("  OK: %s — %d rows", r.Table, r.SourceCount)
+		if !cfg.Quiet {
+			logValidationResult(r)
+		}
+		if !r.passed() {
+			failed = append(failed, r.Table)
 		}
 	}
 
-	log.Printf("  validated %d table(s) in %s (workers=%d)", len(schema.Tables), time.Since(start).Round(time.Millisecond), workers)
+	log.Printf("  validated %d table(s) in %s (workers=%d)", len(cfg.Schema.Tables), time.Since(start).Round(time.Millisecond), workers)
 
-	if mismatches > 0 {
-		var names []string
-		for _, r := range results {
-			if !r.CountMatch {
-				names = append(names, r.Table)
-			}
-		}
-		return results, fmt.Errorf("validation failed: row count mismatch on %d table(s): %s",
-			mismatches, strings.Join(names, ", "))
+	if len(failed) > 0 {
+		return results, fmt.Errorf("validation failed: %s mismatch on %d table(s): %s",
+			validationMismatchLabel(cfg.Mode), len(failed), strings.Join(failed, ", "))
 	}
 	return results, nil
 }
+
+// validateTable runs the configured validation mode for a single table.
+func validateTable(ctx context.Context, cfg validationConfig, srcDB validationSource, tbl Table) (ValidationResult, error) {
+	switch cfg.Mode {
+	case "checksum":
+		return validateTableChecksum(ctx, cfg, srcDB, tbl)
+	case "sample":
+		return validateTableSample(ctx, cfg, srcDB, tbl)
+	case "aggregate":
+		return validateTableAggregate(ctx, cfg, srcDB, tbl)
+	default:
+		return validateTableRowCount(ctx, cfg, srcDB, tbl)
+	}
+}
+
+func validateTableRowCount(ctx context.Context, cfg validationConfig, srcDB validationSource, tbl Table) (ValidationResult, error) {
+	result := ValidationResult{Table: tbl.SourceName}
+
+	// Count source rows
+	srcQuery := buildSourceCountQuery(cfg.Src, tbl)
+	if err := srcDB.QueryRowContext(ctx, srcQuery).Scan(&result.SourceCount); err != nil {
+		return result, fmt.Errorf("count source rows for %s: %w", tbl.SourceName, err)
+	}
+
+	// Count target rows
+	pgQuery := fmt.Sprintf("SELECT COUNT(*) FROM %s.%s", pgIdent(cfg.PGSchema), pgIdent(tbl.PGName))
+	if err := cfg.Pool.QueryRow(ctx, pgQuery).Scan(&result.TargetCount); err != nil {
+		return result, fmt.Errorf("count target rows for %s: %w", tbl.PGName, err)
+	}
+
+	result.CountMatch = result.SourceCount == result.TargetCount
+	return result, nil
+}
+
+func logValidationResult(r ValidationResult) {
+	for _, line := range validationResultLines(r) {
+		log.Printf("  %s", line)
+	}
+}
+
+// validationResultLines renders a table's outcome as report lines: an OK line
+// when it passed, otherwise one MISMATCH line per difference found.
+func validationResultLines(r ValidationResult) []string {
+	var lines []string
+	if !r.CountMatch {
+		lines = append(lines, fmt.Sprintf("MISMATCH: %s — source=%d target=%d", r.Table, r.SourceCount, r.TargetCount))
+	}
+	for _, m := range r.ChunkMismatches {
+		lines = append(lines, fmt.Sprintf("MISMATCH: %s — %s: source rows=%d digest=%s, target rows=%d digest=%s",
+			r.Table, m.describe(r.ChunkKey), m.SourceRows, m.SourceDigest, m.TargetRows, m.TargetDigest))
+	}
+	for _, d := range r.RowDiffs {
+		lines = append(lines, fmt.Sprintf("MISMATCH: %s", d.describe(r.Table)))
+	}
+	for _, m := range r.AggregateMismatches {
+		lines = append(lines, fmt.Sprintf("MISMATCH: %s.%s %s: source=%s target=%s", r.Table, m.Column, m.Aggregate, m.Source, m.Target))
+	}
+	if !r.passed() {
+		return lines
+	}
+
+	switch {
+	case r.ChecksumChunks > 0:
+		return []string{fmt.Sprintf("OK: %s — %d rows, %d checksum chunk(s)", r.Table, r.SourceCount, r.ChecksumChunks)}
+	case r.AggregatesChecked > 0:
+		return []string{fmt.Sprintf("OK: %s — %d rows, %d column aggregate(s)", r.Table, r.SourceCount, r.AggregatesChecked)}
+	case r.SampledRows > 0:
+		return []string{fmt.Sprintf("OK: %s — %d rows, %d sampled", r.Table, r.SourceCount, r.SampledRows)}
+	default:
+		return []string{fmt.Sprintf("OK: %s — %d rows", r.Table, r.SourceCount)}
+	}
+}
+
+func validationMismatchLabel(mode string) string {
+	switch mode {
+	case "checksum":
+		return "checksum"
+	case "sample":
+		return "sampled row"
+	case "aggregate":
+		return "column aggregate"
+	default:
+		return "row count"
+	}
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ColumnAggregateMismatch describes one per-column aggregate whose source and
+// target values differ.
+type ColumnAggregateMismatch struct {
+	Column    string `json:"column"`
+	Aggregate string `json:"aggregate"` // nulls|min|max|sum|length
+	Source    string `json:"source"`
+	Target    string `json:"target"`
+}
+
+// columnAggregate is one aggregate computed over a column on both sides.
+// Values are compared after canonicalizing them as Family.
+type columnAggregate struct {
+	Column    string
+	Aggregate string
+	SourceSQL string
+	TargetSQL string
+	Family    string
+}
+
+// columnAggregates returns the aggregates compared for a column, chosen by the
+// column's mapped PostgreSQL type family:
+//   - every column: NULL count
+//   - integer and numeric columns: MIN, MAX and SUM
+//   - floating point and date/time columns: MIN and MAX
+//   - character and binary columns: total length
+func columnAggregates(src SourceDB, col Column, family string) []columnAggregate {
+	srcCol := aggregateSourceColumnExpr(src, col)
+	pgCol := pgIdent(col.PGName)
+
+	aggs := []columnAggregate{{
+		Aggregate: "nulls",
+		SourceSQL: fmt.Sprintf("COUNT(*) - COUNT(%s)", srcCol),
+		TargetSQL: fmt.Sprintf("COUNT(*) - COUNT(%s)", pgCol),
+		Family:    "int",
+	}}
+
+	switch family {
+	case "int", "numeric", "float4", "float8", "date", "timestamp", "timestamptz":
+		for _, fn := range []string{"min", "max"} {
+			aggs = append(aggs, columnAggregate{
+				Aggregate: fn,
+				SourceSQL: fmt.Sprintf("%s(%s)", strings.ToUpper(fn), srcCol),
+				TargetSQL: pgChecksumSelectExpr(fmt.Sprintf("%s(%s)", strings.ToUpper(fn), pgCol), family),
+				Family:    family,
+			})
+		}
+	}
+
+	switch {
+	case family == "int" || family == "numeric":
+		aggs = append(aggs, columnAggregate{
+			Aggregate: "sum",
+			SourceSQL: aggregateSourceSumExpr(src, srcCol, family),
+			TargetSQL: fmt.Sprintf("SUM(%s)::text", pgCol),
+			Family:    "numeric",
+		})
+	case (family == "text" || family == "char") && isCharacterSourceType(col.DataType):
+		aggs = append(aggs, columnAggregate{
+			Aggregate: "length",
+			SourceSQL: aggregateSourceTextLengthExpr(src, srcCol, family),
+			TargetSQL: aggregateTargetTextLengthExpr(src, pgCol),
+			Family:    "numeric",
+		})
+	case family == "bytea" && isBinarySourceType(col.DataType):
+		aggs = append(aggs, columnAggregate{
+			Aggregate: "length",
+			SourceSQL: aggregateSourceBinaryLengthExpr(src, srcCol),
+			TargetSQL: fmt.Sprintf("SUM(octet_length(%s))::text", pgCol),
+			Family:    "numeric",
+		})
+	}
+
+	for i := range aggs {
+		aggs[i].Column = col.SourceName
+	}
+	return aggs
+}
+
+// aggregateSourceColumnExpr renders the source column for aggregation. MySQL
+// zero dates are migrated as NULL, so they are treated as NULL here too.
+func aggregateSourceColumnExpr(src SourceDB, col Column) string {
+	quoted := src.QuoteIdentifier(col.SourceName)
+	if src.Name() == "MySQL" {
+		switch col.DataType {
+		case "date", "datetime", "timestamp":
+			return fmt.Sprintf("CASE WHEN %s < '0001-01-01' THEN NULL ELSE %s END", quoted, quoted)
+		}
+	}
+	return quoted
+}
+
+func aggregateSourceSumExpr(src SourceDB, srcCol, family string) string {
+	if src.Name() == "MSSQL" && family == "int" {
+		// SUM over int/smallint/tinyint returns int and overflows easily.
+		return fmt.Sprintf("SUM(CAST(%s AS decimal(38,0)))", srcCol)
+	}
+	return fmt.Sprintf("SUM(%s)", srcCol)
+}
+
+// aggregateSourceTextLengthExpr sums character lengths. Fixed-width char
+// columns compare without trailing padding, matching PostgreSQL char(n).
+// SQL Server sums UTF-16 byte lengths instead: LEN drops trailing spaces and,
+// outside _SC collations, counts surrogate pairs as two characters.
+func aggregateSourceTextLengthExpr(src SourceDB, srcCol, family string) string {
+	switch src.Name() {
+	case "MSSQL":
+		if family == "char" {
+			srcCol = fmt.Sprintf("RTRIM(%s)", srcCol)
+		}
+		return fmt.Sprintf("SUM(CAST(DATALENGTH(CAST(%s AS nvarchar(max))) AS bigint))", srcCol)
+	case "SQLite":
+		if family == "char" {
+			return fmt.Sprintf("SUM(LENGTH(RTRIM(%s)))", srcCol)
+		}
+		return fmt.Sprintf("SUM(LENGTH(%s))", srcCol)
+	default:
+		return fmt.Sprintf("SUM(CHAR_LENGTH(%s))", srcCol)
+	}
+}
+
+// aggregateTargetTextLengthExpr sums the target side of the length
+// aggregate: code points, or for SQL Server the UTF-16 byte length, two bytes
+// per code point plus two more for each one outside the Basic Multilingual
+// Plane.
+func aggregateTargetTextLengthExpr(src SourceDB, pgCol string) string {
+	if src.Name() == "MSSQL" {
+		return fmt.Sprintf(`SUM(2 * (2 * char_length(%[1]s::text) - char_length(regexp_replace(%[1]s::text, '[\U00010000-\U0010FFFF]', '', 'g'))))::text`, pgCol)
+	}
+	return fmt.Sprintf("SUM(char_length(%s::text))::text", pgCol)
+}
+
+func aggregateSourceBinaryLengthExpr(src SourceDB, srcCol string) string {
+	switch src.Name() {
+	case "MSSQL":
+		return fmt.Sprintf("SUM(CAST(DATALENGTH(%s) AS bigint))", srcCol)
+	case "SQLite":
+		return fmt.Sprintf("SUM(LENGTH(CAST(%s AS BLOB)))", srcCol)
+	default:
+		return fmt.Sprintf("SUM(LENGTH(%s))", srcCol)
+	}
+}
+
+// isCharacterSourceType reports whether a source data type stores character
+// data whose length survives migration unchanged.
+func isCharacterSourceType(dataType string) bool {
+	t := strings.ToLower(dataType)
+	return strings.Contains(t, "char") || strings.Contains(t, "text") ||
+		strings.Contains(t, "clob") || t == "enum" || t == "set"
+}
+
+// isBinarySourceType reports whether a source data type stores raw bytes that
+// are copied to bytea as-is.
+func isBinarySourceType(dataType string) bool {
+	t := strings.ToLower(dataType)
+	return strings.Contains(t, "binary") || strings.Contains(t, "blob") || t == "image"
+}
+
+// tableAggregates returns every aggregate compared for a table, in column order.
+func tableAggregates(src SourceDB, table Table, families []string) []columnAggregate {
+	var aggs []columnAggregate
+	for i, col := range table.Columns {
+		aggs = append(aggs, columnAggregates(src, col, families[i])...)
+	}
+	return aggs
+}
+
+func buildSourceAggregateQuery(src SourceDB, table Table, aggs []columnAggregate) string {
+	exprs := make([]string, 0, len(aggs)+1)
+	exprs = append(exprs, "COUNT(*)")
+	for _, a := range aggs {
+		exprs = append(exprs, a.SourceSQL)
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), src.SourceTableRef(table))
+}
+
+func buildTargetAggregateQuery(pgSchema string, table Table, aggs []columnAggregate) string {
+	exprs := make([]string, 0, len(aggs)+1)
+	exprs = append(exprs, "COUNT(*)")
+	for _, a := range aggs {
+		exprs = append(exprs, a.TargetSQL)
+	}
+	return fmt.Sprintf("SELECT %s FROM %s", strings.Join(exprs, ", "), pgQualifiedIdent(pgSchema, table.PGName))
+}
+
+// compareColumnAggregates canonicalizes both result rows (row count first,
+// then one value per aggregate) and returns the aggregates that differ.
+func compareColumnAggregates(aggs []columnAggregate, srcVals, tgtVals []any) []ColumnAggregateMismatch {
+	var mismatches []ColumnAggregateMismatch
+	for i, a := range aggs {
+		s := sampleDisplayValue(sampleCanonicalValue(srcVals[i+1], a.Family))
+		t := sampleDisplayValue(sampleCanonicalValue(tgtVals[i+1], a.Family))
+		if s != t {
+			mismatches = append(mismatches, ColumnAggregateMismatch{
+				Column:    a.Column,
+				Aggregate: a.Aggregate,
+				Source:    s,
+				Target:    t,
+			})
+		}
+	}
+	return mismatches
+}
+
+// validateTableAggregate compares row counts and per-column aggregates computed
+// by each database in a single pass over the table.
+func validateTableAggregate(ctx context.Context, cfg validationConfig, srcDB validationSource, tbl Table) (ValidationResult, error) {
+	result := ValidationResult{Table: tbl.SourceName}
+
+	families, err := checksumColumnFamilies(tbl, cfg.Src, cfg.TypeMap)
+	if err != nil {
+		return result, fmt.Errorf("aggregate %s: %w", tbl.SourceName, err)
+	}
+	aggs := tableAggregates(cfg.Src, tbl, families)
+
+	srcVals := make([]any, len(aggs)+1)
+	srcPtrs := make([]any, len(srcVals))
+	for i := range srcVals {
+		srcPtrs[i] = &srcVals[i]
+	}
+	srcQuery := buildSourceAggregateQuery(cfg.Src, tbl, aggs)
+	if err := srcDB.QueryRowContext(ctx, srcQuery).Scan(srcPtrs...); err != nil {
+		return result, fmt.Errorf("aggregate source rows for %s: %w", tbl.SourceName, err)
+	}
+
+	pgQuery := buildTargetAggregateQuery(cfg.PGSchema, tbl, aggs)
+	rows, err := cfg.Pool.Query(ctx, pgQuery)
+	if err != nil {
+		return result, fmt.Errorf("aggregate target rows for %s: %w", tbl.PGName, err)
+	}
+	defer rows.Close()
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return result, fmt.Errorf("aggregate target rows for %s: %w", tbl.PGName, err)
+		}
+		return result, fmt.Errorf("aggregate target rows for %s: no result row", tbl.PGName)
+	}
+	tgtVals, err := rows.Values()
+	if err != nil {
+		return result, fmt.Errorf("aggregate target rows for %s: %w", tbl.PGName, err)
+	}
+	rows.Close()
+
+	srcCount, err := strconv.ParseInt(checksumCanonicalValue(srcVals[0], "int"), 10, 64)
+	if err != nil {
+		return result, fmt.Errorf("aggregate source rows for %s: parse count: %w", tbl.SourceName, err)
+	}
+	result.SourceCount = srcCount
+	if n, ok := tgtVals[0].(int64); ok {
+		result.TargetCount = n
+	}
+	result.CountMatch = result.SourceCount == result.TargetCount
+	result.AggregatesChecked = len(aggs)
+	result.AggregateMismatches = compareColumnAggregates(aggs, srcVals, tgtVals)
+	return result, nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func aggregateNames(aggs []columnAggregate) []string {
+	names := make([]string, len(aggs))
+	for i, a := range aggs {
+		names[i] = a.Aggregate
+	}
+	return names
+}
+
+func TestColumnAggregates_ByFamily(t *testing.T) {
+	src := &mysqlSourceDB{}
+	tests := []struct {
+		name   string
+		col    Column
+		family string
+		want   []string
+	}{
+		{"integer", Column{SourceName: "id", PGName: "id", DataType: "int"}, "int", []string{"nulls", "min", "max", "sum"}},
+		{"decimal", Column{SourceName: "amt", PGName: "amt", DataType: "decimal"}, "numeric", []string{"nulls", "min", "max", "sum"}},
+		{"double", Column{SourceName: "f", PGName: "f", DataType: "double"}, "float8", []string{"nulls", "min", "max"}},
+		{"datetime", Column{SourceName: "ts", PGName: "ts", DataType: "datetime"}, "timestamp", []string{"nulls", "min", "max"}},
+		{"varchar", Column{SourceName: "email", PGName: "email", DataType: "varchar"}, "text", []string{"nulls", "length"}},
+		{"blob", Column{SourceName: "data", PGName: "data", DataType: "blob"}, "bytea", []string{"nulls", "length"}},
+		{"spatial as bytea", Column{SourceName: "geo", PGName: "geo", DataType: "point"}, "bytea", []string{"nulls"}},
+		{"binary16 uuid", Column{SourceName: "u", PGName: "u", DataType: "binary"}, "uuid", []string{"nulls"}},
+		{"boolean", Column{SourceName: "b", PGName: "b", DataType: "tinyint"}, "bool", []string{"nulls"}},
+		{"json", Column{SourceName: "doc", PGName: "doc", DataType: "json"}, "json", []string{"nulls"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := aggregateNames(columnAggregates(src, tt.col, tt.family))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("aggregates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestColumnAggregates_SourceExpressions(t *testing.T) {
+	mysqlDate := columnAggregates(&mysqlSourceDB{}, Column{SourceName: "d", PGName: "d", DataType: "date"}, "date")
+	if want := "MIN(CASE WHEN `d` < '0001-01-01' THEN NULL ELSE `d` END)"; mysqlDate[1].SourceSQL != want {
+		t.Errorf("MySQL date min = %q, want %q", mysqlDate[1].SourceSQL, want)
+	}
+
+	mssqlInt := columnAggregates(&mssqlSourceDB{}, Column{SourceName: "n", PGName: "n", DataType: "int"}, "int")
+	if want := "SUM(CAST([n] AS decimal(38,0)))"; mssqlInt[3].SourceSQL != want {
+		t.Errorf("MSSQL int sum = %q, want %q", mssqlInt[3].SourceSQL, want)
+	}
+
+	mssqlText := columnAggregates(&mssqlSourceDB{}, Column{SourceName: "s", PGName: "s", DataType: "nvarchar"}, "text")
+	if want := "SUM(CAST(DATALENGTH(CAST([s] AS nvarchar(max))) AS bigint))"; mssqlText[1].SourceSQL != want {
+		t.Errorf("MSSQL text length = %q, want %q", mssqlText[1].SourceSQL, want)
+	}
+	if want := `SUM(2 * (2 * char_length("s"::text) - char_length(regexp_replace("s"::text, '[\U00010000-\U0010FFFF]', '', 'g'))))::text`; mssqlText[1].TargetSQL != want {
+		t.Errorf("MSSQL text length target = %q, want %q", mssqlText[1].TargetSQL, want)
+	}
+
+	sqliteText := columnAggregates(&sqliteSourceDB{}, Column{SourceName: "s", PGName: "s", DataType: "text"}, "text")
+	if want := `SUM(LENGTH("s"))`; sqliteText[1].SourceSQL != want {
+		t.Errorf("SQLite text length = %q, want %q", sqliteText[1].SourceSQL, want)
+	}
+}
+
+func TestBuildTargetAggregateQuery(t *testing.T) {
+	table := Table{
+		SourceName: "orders",
+		PGName:     "orders",
+		Columns: []Column{
+			{SourceName: "total", PGName: "total", DataType: "decimal"},
+			{SourceName: "note", PGName: "note", DataType: "varchar"},
+		},
+	}
+	aggs := tableAggregates(&mysqlSourceDB{}, table, []string{"numeric", "text"})
+
+	got := buildTargetAggregateQuery("app", table, aggs)
+	want := `SELECT COUNT(*), COUNT(*) - COUNT("total"), MIN("total")::text, MAX("total")::text, SUM("total")::text, ` +
+		`COUNT(*) - COUNT("note"), SUM(char_length("note"::text))::text FROM "app"."orders"`
+	if got != want {
+		t.Errorf("buildTargetAggregateQuery() =\n%s\nwant\n%s", got, want)
+	}
+
+	got = buildSourceAggregateQuery(&mysqlSourceDB{}, table, aggs)
+	want = "SELECT COUNT(*), COUNT(*) - COUNT(`total`), MIN(`total`), MAX(`total`), SUM(`total`), " +
+		"COUNT(*) - COUNT(`note`), SUM(CHAR_LENGTH(`note`)) FROM `orders`"
+	if got != want {
+		t.Errorf("buildSourceAggregateQuery() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestCompareColumnAggregates(t *testing.T) {
+	col := Column{SourceName: "amt", PGName: "amt", DataType: "decimal"}
+	aggs := columnAggregates(&mysqlSourceDB{}, col, "numeric")
+
+	// count, nulls, min, max, sum
+	srcVals := []any{[]byte("3"), []byte("0"), []byte("1.50"), []byte("9.99"), []byte("12.345")}
+	tgtVals := []any{int64(3), int64(0), "1.5", "9.99", "12.35"}
+
+	got := compareColumnAggregates(aggs, srcVals, tgtVals)
+	want := []ColumnAggregateMismatch{{Column: "amt", Aggregate: "sum", Source: "12.345", Target: "12.35"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("compareColumnAggregates() = %+v, want %+v", got, want)
+	}
+
+	// Empty tables produce NULL aggregates on both sides.
+	empty := []any{int64(0), int64(0), nil, nil, nil}
+	if got := compareColumnAggregates(aggs, empty, empty); len(got) != 0 {
+		t.Errorf("expected no mismatches for empty table, got %+v", got)
+	}
+}
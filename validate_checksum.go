@@ -0,0 +1,650 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ChunkChecksumMismatch describes one validation range whose source and target
+// digests differ. Nil bounds mean the range is open on that side.
+type ChunkChecksumMismatch struct {
+	ChunkIndex   int    `json:"chunk_index"`
+	LowerBound   *int64 `json:"lower_bound"` // inclusive
+	UpperBound   *int64 `json:"upper_bound"` // exclusive
+	SourceRows   int64  `json:"source_rows"`
+	TargetRows   int64  `json:"target_rows"`
+	SourceDigest string `json:"source_digest"`
+	TargetDigest string `json:"target_digest"`
+}
+
+func (m ChunkChecksumMismatch) describe(key string) string {
+	switch {
+	case key == "":
+		return "full table"
+	case m.LowerBound != nil && m.UpperBound != nil:
+		return fmt.Sprintf("chunk %d (%s %d..%d)", m.ChunkIndex, key, *m.LowerBound, *m.UpperBound)
+	case m.LowerBound != nil:
+		return fmt.Sprintf("chunk %d (%s >= %d)", m.ChunkIndex, key, *m.LowerBound)
+	case m.UpperBound != nil:
+		return fmt.Sprintf("chunk %d (%s < %d)", m.ChunkIndex, key, *m.UpperBound)
+	default:
+		return fmt.Sprintf("chunk %d (all rows)", m.ChunkIndex)
+	}
+}
+
+// checksumRange is a key range hashed as one unit. The first and last ranges
+// are left open so rows outside the source MIN/MAX (for example stray target
+// rows) still land in a compared range.
+type checksumRange struct {
+	Index    int
+	Lower    int64
+	Upper    int64
+	HasLower bool
+	HasUpper bool
+}
+
+func checksumRangesFromChunks(chunks []Chunk) []checksumRange {
+	ranges := make([]checksumRange, len(chunks))
+	for i, c := range chunks {
+		ranges[i] = checksumRange{
+			Index:    c.Index,
+			Lower:    c.LowerBound,
+			Upper:    c.UpperBound,
+			HasLower: i > 0,
+			HasUpper: !c.IsLast,
+		}
+	}
+	return ranges
+}
+
+func checksumRangePredicate(quotedKey string, r checksumRange) string {
+	var conds []string
+	if r.HasLower {
+		conds = append(conds, fmt.Sprintf("%s >= %d", quotedKey, r.Lower))
+	}
+	if r.HasUpper {
+		conds = append(conds, fmt.Sprintf("%s < %d", quotedKey, r.Upper))
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// planChecksumRanges returns the ranges to hash for a table. Tables without a
+// chunkable key are hashed as a single range and the returned key is nil.
+func planChecksumRanges(ctx context.Context, source dbQuerier, src SourceDB, table Table, chunkSize int64) ([]checksumRange, *ChunkKey, error) {
+	key := chunkKeyForTable(table, src)
+	if key == nil {
+		return []checksumRange{{Index: 0}}, nil, nil
+	}
+	min, max, hasRows, err := queryMinMax(ctx, source, src, table, *key)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !hasRows {
+		return []checksumRange{{Index: 0}}, key, nil
+	}
+	return checksumRangesFromChunks(planChunks(min, max, chunkSize)), key, nil
+}
+
+func buildSourceChecksumQuery(src SourceDB, table Table, key *ChunkKey, r checksumRange, typeMap TypeMappingConfig) string {
+	cols := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		cols[i] = columnSelectExpr(src, col, typeMap)
+	}
+	q := fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), src.SourceTableRef(table))
+	if key != nil {
+		if where := checksumRangePredicate(src.QuoteIdentifier(key.SourceColumn), r); where != "" {
+			q += " WHERE " + where
+		}
+	}
+	return q
+}
+
+func buildTargetChecksumQuery(pgSchema string, table Table, families []string, key *ChunkKey, r checksumRange) string {
+	cols := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		cols[i] = pgChecksumSelectExpr(pgIdent(col.PGName), families[i])
+	}
+	q := fmt.Sprintf("SELECT %s FROM %s", strings.Join(cols, ", "), pgQualifiedIdent(pgSchema, table.PGName))
+	if key != nil {
+		if where := checksumRangePredicate(pgIdent(key.PGColumn), r); where != "" {
+			q += " WHERE " + where
+		}
+	}
+	return q
+}
+
+// checksumColumnFamilies resolves the mapped PostgreSQL type family of every
+// column so both sides canonicalize values against the same target type.
+func checksumColumnFamilies(table Table, src SourceDB, typeMap TypeMappingConfig) ([]string, error) {
+	families := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		pgType, err := src.MapType(col, typeMap)
+		if err != nil {
+			return nil, fmt.Errorf("column %s: %w", col.PGName, err)
+		}
+		families[i] = checksumTypeFamily(pgTypeForCollation(col, pgType, typeMap))
+	}
+	return families, nil
+}
+
+// checksumTypeFamily groups mapped PostgreSQL types by how their values are
+// canonicalized for hashing.
+func checksumTypeFamily(pgType string) string {
+	t := strings.ToLower(strings.TrimSpace(pgType))
+	switch {
+	case t == "smallint", t == "integer", t == "bigint":
+		return "int"
+	case t == "numeric", strings.HasPrefix(t, "numeric("):
+		return "numeric"
+	case t == "real":
+		return "float4"
+	case t == "double precision":
+		return "float8"
+	case t == "boolean":
+		return "bool"
+	case t == "bytea":
+		return "bytea"
+	case t == "date":
+		return "date"
+	case t == "timestamptz":
+		return "timestamptz"
+	case strings.HasPrefix(t, "timestamp"):
+		return "timestamp"
+	case t == "time":
+		return "time"
+	case t == "interval":
+		return "interval"
+	case t == "json", t == "jsonb":
+		return "json"
+	case strings.HasSuffix(t, "[]"):
+		return "array"
+	case strings.HasPrefix(t, "bit("), t == "varbit":
+		return "bits"
+	case strings.HasPrefix(t, "char("):
+		return "char"
+	case t == "uuid":
+		return "uuid"
+	case t == "geometry":
+		return "geometry"
+	default:
+		return "text"
+	}
+}
+
+// pgChecksumSelectExpr renders the target-side SELECT expression for a column.
+// Types whose pgx decoding is awkward to compare are cast to a stable text form.
+func pgChecksumSelectExpr(ident, family string) string {
+	switch family {
+	case "int", "float4", "float8", "bool", "bytea", "timestamp", "timestamptz":
+		return ident
+	case "interval":
+		return fmt.Sprintf("extract(epoch from %s)::text", ident)
+	case "array":
+		return fmt.Sprintf("array_to_json(%s)::text", ident)
+	case "geometry":
+		return fmt.Sprintf("encode(ST_AsEWKB(%s, 'NDR'), 'hex')", ident)
+	default:
+		return ident + "::text"
+	}
+}
+
+// checksumDigest is an order-independent digest of a set of rows: the per-row
+// MD5 hashes are summed as two 64-bit lanes so range reads need no ORDER BY.
+type checksumDigest struct {
+	Rows int64
+	hi   uint64
+	lo   uint64
+}
+
+func (d *checksumDigest) addRow(sum [md5.Size]byte) {
+	d.Rows++
+	d.hi += binary.BigEndian.Uint64(sum[:8])
+	d.lo += binary.BigEndian.Uint64(sum[8:])
+}
+
+func (d checksumDigest) String() string {
+	return fmt.Sprintf("%016x%016x", d.hi, d.lo)
+}
+
+// checksumRowEncoder builds the encoding of one row: the hex MD5 of every
+// canonical value, or "n" for NULL so it cannot collide with any text value.
+// buildSourceDigestQuery and buildTargetDigestQuery encode rows the same way.
+type checksumRowEncoder struct {
+	buf bytes.Buffer
+}
+
+func (e *checksumRowEncoder) reset() { e.buf.Reset() }
+
+func (e *checksumRowEncoder) add(v any, family string) {
+	if v == nil {
+		e.buf.WriteByte('n')
+		return
+	}
+	sum := md5.Sum([]byte(checksumCanonicalValue(v, family)))
+	e.buf.WriteString(hex.EncodeToString(sum[:]))
+}
+
+func (e *checksumRowEncoder) sum() [md5.Size]byte {
+	return md5.Sum(e.buf.Bytes())
+}
+
+// sourceChecksumDigest streams a source range through TransformValue so the
+// digest reflects exactly what the COPY path would have written. It is used
+// for sources that cannot hash in SQL (see checksumHashesInSQL).
+func sourceChecksumDigest(ctx context.Context, source dbQuerier, src SourceDB, table Table, query string, families []string, typeMap TypeMappingConfig) (checksumDigest, error) {
+	var d checksumDigest
+	rows, err := source.QueryContext(ctx, query)
+	if err != nil {
+		return d, fmt.Errorf("select: %w", err)
+	}
+	defer rows.Close()
+
+	scanDest := make([]any, len(table.Columns))
+	scanPtrs := make([]any, len(table.Columns))
+	for i := range scanDest {
+		scanPtrs[i] = &scanDest[i]
+	}
+
+	var enc checksumRowEncoder
+	for rows.Next() {
+		if err := rows.Scan(scanPtrs...); err != nil {
+			return d, err
+		}
+		enc.reset()
+		for i, col := range table.Columns {
+			v, err := src.TransformValue(scanDest[i], col, typeMap)
+			if err != nil {
+				return d, fmt.Errorf("column %s: %w", col.SourceName, err)
+			}
+			enc.add(v, families[i])
+		}
+		d.addRow(enc.sum())
+	}
+	return d, rows.Err()
+}
+
+func targetChecksumDigest(ctx context.Context, pool *pgxpool.Pool, query string, families []string) (checksumDigest, error) {
+	var d checksumDigest
+	rows, err := pool.Query(ctx, query)
+	if err != nil {
+		return d, fmt.Errorf("select: %w", err)
+	}
+	defer rows.Close()
+
+	var enc checksumRowEncoder
+	for rows.Next() {
+		vals, err := rows.Values()
+		if err != nil {
+			return d, err
+		}
+		enc.reset()
+		for i, v := range vals {
+			enc.add(v, families[i])
+		}
+		d.addRow(enc.sum())
+	}
+	return d, rows.Err()
+}
+
+// validateTableChecksum hashes every chunk range of a table on both sides and
+// records the ranges whose digests differ.
+func validateTableChecksum(ctx context.Context, cfg validationConfig, srcDB validationSource, tbl Table) (ValidationResult, error) {
+	result := ValidationResult{Table: tbl.SourceName}
+
+	families, err := checksumColumnFamilies(tbl, cfg.Src, cfg.TypeMap)
+	if err != nil {
+		return result, fmt.Errorf("checksum %s: %w", tbl.SourceName, err)
+	}
+	ranges, key, err := planChecksumRanges(ctx, srcDB, cfg.Src, tbl, cfg.ChunkSize)
+	if err != nil {
+		return result, err
+	}
+	if key != nil {
+		result.ChunkKey = key.SourceColumn
+	}
+
+	for _, r := range ranges {
+		srcDigest, tgtDigest, err := checksumRangeDigests(ctx, cfg, srcDB, tbl, families, key, r)
+		if err != nil {
+			return result, err
+		}
+
+		result.SourceCount += srcDigest.Rows
+		result.TargetCount += tgtDigest.Rows
+		if srcDigest != tgtDigest {
+			result.ChunkMismatches = append(result.ChunkMismatches, newChunkChecksumMismatch(r, srcDigest, tgtDigest))
+		}
+	}
+
+	result.ChecksumChunks = len(ranges)
+	result.CountMatch = result.SourceCount == result.TargetCount
+	return result, nil
+}
+
+// checksumRangeDigests hashes one range on both sides, in SQL when the source
+// supports it and client-side otherwise.
+func checksumRangeDigests(ctx context.Context, cfg validationConfig, source validationSource, tbl Table, families []string, key *ChunkKey, r checksumRange) (checksumDigest, checksumDigest, error) {
+	var srcDigest, tgtDigest checksumDigest
+	var err error
+	inSQL := checksumHashesInSQL(cfg.Src, tbl, cfg.TypeMap)
+
+	if inSQL {
+		srcDigest, err = sourceSQLChecksumDigest(ctx, source, buildSourceDigestQuery(cfg.Src, tbl, families, key, r, cfg.TypeMap))
+	} else {
+		srcQuery := buildSourceChecksumQuery(cfg.Src, tbl, key, r, cfg.TypeMap)
+		srcDigest, err = sourceChecksumDigest(ctx, source, cfg.Src, tbl, srcQuery, families, cfg.TypeMap)
+	}
+	if err != nil {
+		return srcDigest, tgtDigest, fmt.Errorf("checksum source rows for %s chunk %d: %w", tbl.SourceName, r.Index, err)
+	}
+
+	if inSQL {
+		tgtDigest, err = targetSQLChecksumDigest(ctx, cfg.Pool, buildTargetDigestQuery(cfg.PGSchema, cfg.Src, tbl, families, key, r))
+	} else {
+		pgQuery := buildTargetChecksumQuery(cfg.PGSchema, tbl, families, key, r)
+		tgtDigest, err = targetChecksumDigest(ctx, cfg.Pool, pgQuery, families)
+	}
+	if err != nil {
+		return srcDigest, tgtDigest, fmt.Errorf("checksum target rows for %s chunk %d: %w", tbl.PGName, r.Index, err)
+	}
+	return srcDigest, tgtDigest, nil
+}
+
+func newChunkChecksumMismatch(r checksumRange, src, tgt checksumDigest) ChunkChecksumMismatch {
+	m := ChunkChecksumMismatch{
+		ChunkIndex:   r.Index,
+		SourceRows:   src.Rows,
+		TargetRows:   tgt.Rows,
+		SourceDigest: src.String(),
+		TargetDigest: tgt.String(),
+	}
+	if r.HasLower {
+		lower := r.Lower
+		m.LowerBound = &lower
+	}
+	if r.HasUpper {
+		upper := r.Upper
+		m.UpperBound = &upper
+	}
+	return m
+}
+
+// --- Value canonicalization ---
+
+const (
+	checksumTimestampLayout = "2006-01-02 15:04:05.999999"
+	checksumTimeLayout      = "15:04:05.999999"
+)
+
+// checksumTimeLayouts are the textual date/time forms accepted from sources
+// that hand back strings (SQLite, MySQL TIME) instead of time.Time values.
+var checksumTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02T15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02",
+}
+
+// checksumCanonicalValue renders a non-NULL value into the canonical text used
+// for row hashing. Source values arrive after TransformValue and target values
+// arrive from pgChecksumSelectExpr; both are normalized by the mapped PostgreSQL
+// type family so driver-specific Go types compare equal.
+func checksumCanonicalValue(v any, family string) string {
+	switch family {
+	case "int":
+		switch n := v.(type) {
+		case float64:
+			if n == math.Trunc(n) {
+				return strconv.FormatFloat(n, 'f', -1, 64)
+			}
+		case bool:
+			if n {
+				return "1"
+			}
+			return "0"
+		}
+		return strings.TrimSpace(checksumRawString(v))
+
+	case "numeric":
+		var s string
+		switch n := v.(type) {
+		case float64:
+			s = strconv.FormatFloat(n, 'f', -1, 64)
+		case float32:
+			s = strconv.FormatFloat(float64(n), 'f', -1, 32)
+		default:
+			s = strings.TrimSpace(checksumRawString(v))
+		}
+		if r, ok := new(big.Rat).SetString(s); ok {
+			return canonicalDecimal(r)
+		}
+		return s
+
+	case "float4":
+		if n, ok := v.(float32); ok {
+			return strconv.FormatFloat(float64(n), 'g', -1, 32)
+		}
+		if f, ok := checksumFloat(v, 32); ok {
+			return strconv.FormatFloat(float64(float32(f)), 'g', -1, 32)
+		}
+		return checksumRawString(v)
+
+	case "float8":
+		if f, ok := checksumFloat(v, 64); ok {
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+		return checksumRawString(v)
+
+	case "bool":
+		switch b := v.(type) {
+		case bool:
+			if b {
+				return "t"
+			}
+			return "f"
+		case int64:
+			if b != 0 {
+				return "t"
+			}
+			return "f"
+		}
+		switch s := strings.ToLower(strings.TrimSpace(checksumRawString(v))); s {
+		case "1", "t", "true":
+			return "t"
+		case "0", "f", "false":
+			return "f"
+		default:
+			return s
+		}
+
+	case "bytea", "geometry":
+		switch b := v.(type) {
+		case []byte:
+			return hex.EncodeToString(b)
+		case string:
+			if family == "geometry" {
+				return strings.ToLower(b)
+			}
+			return hex.EncodeToString([]byte(b))
+		}
+		return checksumRawString(v)
+
+	case "date":
+		if t, ok := checksumTime(v); ok {
+			return t.Format("2006-01-02")
+		}
+		return checksumRawString(v)
+
+	case "timestamp":
+		if t, ok := checksumTime(v); ok {
+			return t.Format(checksumTimestampLayout)
+		}
+		return checksumRawString(v)
+
+	case "timestamptz":
+		if t, ok := checksumTime(v); ok {
+			return t.UTC().Format(checksumTimestampLayout)
+		}
+		return checksumRawString(v)
+
+	case "time":
+		if t, ok := v.(time.Time); ok {
+			return t.Format(checksumTimeLayout)
+		}
+		s := strings.TrimSpace(checksumRawString(v))
+		if t, err := time.Parse("15:04:05.999999999", s); err == nil {
+			return t.Format(checksumTimeLayout)
+		}
+		return s
+
+	case "interval":
+		s := strings.TrimSpace(checksumRawString(v))
+		if secs, ok := intervalLiteralSeconds(s); ok {
+			return canonicalDecimal(secs)
+		}
+		return s
+
+	case "json":
+		return canonicalJSON(checksumRawString(v))
+
+	case "array":
+		if items, ok := v.([]string); ok {
+			b, err := json.Marshal(items)
+			if err == nil {
+				return canonicalJSON(string(b))
+			}
+		}
+		return canonicalJSON(checksumRawString(v))
+
+	case "char":
+		return strings.TrimRight(checksumRawString(v), " ")
+
+	case "uuid":
+		return strings.ToLower(strings.TrimSpace(checksumRawString(v)))
+
+	default:
+		return checksumRawString(v)
+	}
+}
+
+func checksumRawString(v any) string {
+	switch s := v.(type) {
+	case string:
+		return s
+	case []byte:
+		return string(s)
+	case time.Time:
+		return s.Format(time.RFC3339Nano)
+	case fmt.Stringer:
+		return s.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func checksumFloat(v any, bitSize int) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case []byte, string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(checksumRawString(v)), bitSize)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func checksumTime(v any) (time.Time, bool) {
+	if t, ok := v.(time.Time); ok {
+		return t, true
+	}
+	s := strings.TrimSpace(checksumRawString(v))
+	for _, layout := range checksumTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// intervalLiteralSeconds converts either a plain seconds value (target-side
+// extract(epoch ...)) or the "H hours M mins S secs" literal produced by
+// mysqlTimeToInterval into a total number of seconds.
+func intervalLiteralSeconds(s string) (*big.Rat, bool) {
+	if r, ok := new(big.Rat).SetString(s); ok {
+		return r, true
+	}
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return nil, false
+	}
+	total := new(big.Rat)
+	for i := 0; i < len(fields); i += 2 {
+		n, ok := new(big.Rat).SetString(fields[i])
+		if !ok {
+			return nil, false
+		}
+		var mult int64
+		switch fields[i+1] {
+		case "hours", "hour":
+			mult = 3600
+		case "mins", "min":
+			mult = 60
+		case "secs", "sec":
+			mult = 1
+		default:
+			return nil, false
+		}
+		total.Add(total, n.Mul(n, new(big.Rat).SetInt64(mult)))
+	}
+	return total, true
+}
+
+// canonicalDecimal renders r as a decimal without trailing zeros so that
+// equal values compare equal regardless of scale (12.5 vs 12.50).
+func canonicalDecimal(r *big.Rat) string {
+	if r.IsInt() {
+		return r.RatString()
+	}
+	for prec := 1; prec <= 64; prec++ {
+		s := r.FloatString(prec)
+		if back, ok := new(big.Rat).SetString(s); ok && back.Cmp(r) == 0 {
+			return s
+		}
+	}
+	return r.RatString()
+}
+
+// canonicalJSON re-encodes a JSON document with sorted object keys so that
+// json/jsonb text output and source documents compare by content.
+func canonicalJSON(s string) string {
+	dec := json.NewDecoder(strings.NewReader(s))
+	dec.UseNumber()
+	var doc any
+	if err := dec.Decode(&doc); err != nil {
+		return s
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return s
+	}
+	return string(b)
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// MySQL and MSSQL ranges are hashed by the databases themselves: each column
+// is rendered to the canonical text of its mapped PostgreSQL type family, the
+// row hash is built exactly like checksumRowEncoder, and only the row count and
+// the four 32-bit lane sums of the row hashes cross the network. Source
+// expressions mirror TransformValue for the column so the digest reflects what
+// the COPY path would have written.
+
+// pgChecksumMaxDecimal is the largest DECIMAL(65,30) value. MySQL clamps float
+// casts to it, so the target clamps too.
+const pgChecksumMaxDecimal = "99999999999999999999999999999999999.999999999999999999999999999999"
+
+// checksumHashesInSQL reports whether a table's ranges can be hashed in SQL on
+// both sides. SQLite sources, and MSSQL json columns mapped to jsonb (whose
+// normalized text SQL Server cannot reproduce), are hashed client-side.
+func checksumHashesInSQL(src SourceDB, table Table, typeMap TypeMappingConfig) bool {
+	switch src.Name() {
+	case "MySQL":
+		return true
+	case "MSSQL":
+		for _, col := range table.Columns {
+			if col.DataType == "json" && typeMap.JSONAsJSONB {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// buildSourceDigestQuery returns the row count and lane sums of one range.
+func buildSourceDigestQuery(src SourceDB, table Table, families []string, key *ChunkKey, r checksumRange, typeMap TypeMappingConfig) string {
+	tokens := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		tokens[i] = sourceChecksumToken(src, col, families[i], typeMap)
+	}
+	where := ""
+	if key != nil {
+		if pred := checksumRangePredicate(src.QuoteIdentifier(key.SourceColumn), r); pred != "" {
+			where = " WHERE " + pred
+		}
+	}
+
+	if src.Name() == "MSSQL" {
+		lanes := make([]string, 4)
+		for i := range lanes {
+			lanes[i] = fmt.Sprintf("CAST(ISNULL(SUM(CAST(CAST(SUBSTRING(h, %d, 4) AS BIGINT) AS DECIMAL(38,0))), 0) AS VARCHAR(40))", i*4+1)
+		}
+		return fmt.Sprintf("SELECT COUNT_BIG(*), %s FROM (SELECT HASHBYTES('MD5', %s) AS h FROM %s%s) AS r",
+			strings.Join(lanes, ", "), strings.Join(tokens, " + "), src.SourceTableRef(table), where)
+	}
+
+	lanes := make([]string, 4)
+	for i := range lanes {
+		lanes[i] = fmt.Sprintf("COALESCE(SUM(CAST(CONV(SUBSTRING(h, %d, 8), 16, 10) AS UNSIGNED)), 0)", i*8+1)
+	}
+	return fmt.Sprintf("SELECT COUNT(*), %s FROM (SELECT MD5(CONCAT(%s)) AS h FROM %s%s) AS r",
+		strings.Join(lanes, ", "), strings.Join(tokens, ", "), src.SourceTableRef(table), where)
+}
+
+// buildTargetDigestQuery is the PostgreSQL counterpart of buildSourceDigestQuery.
+func buildTargetDigestQuery(pgSchema string, src SourceDB, table Table, families []string, key *ChunkKey, r checksumRange) string {
+	tokens := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		tokens[i] = fmt.Sprintf("coalesce(md5(%s), 'n')", pgChecksumHashExpr(pgIdent(col.PGName), families[i], src.Name()))
+	}
+	lanes := make([]string, 4)
+	for i := range lanes {
+		lanes[i] = fmt.Sprintf("coalesce(sum(('x' || substr(h, %d, 8))::bit(32)::bigint), 0)::text", i*8+1)
+	}
+	q := fmt.Sprintf("SELECT count(*), %s FROM (SELECT md5(%s) AS h FROM %s",
+		strings.Join(lanes, ", "), strings.Join(tokens, " || "), pgQualifiedIdent(pgSchema, table.PGName))
+	if key != nil {
+		if where := checksumRangePredicate(pgIdent(key.PGColumn), r); where != "" {
+			q += " WHERE " + where
+		}
+	}
+	return q + ") AS r"
+}
+
+// sourceChecksumToken hashes one canonical column value to its 32-character
+// hex MD5, or "n" for NULL.
+func sourceChecksumToken(src SourceDB, col Column, family string, typeMap TypeMappingConfig) string {
+	if src.Name() == "MSSQL" {
+		// HASHBYTES hashes the string's bytes; the UTF-8 collation makes them
+		// match the UTF-8 text PostgreSQL hashes.
+		return fmt.Sprintf("ISNULL(LOWER(CONVERT(VARCHAR(32), HASHBYTES('MD5', CAST((%s) COLLATE Latin1_General_100_BIN2_UTF8 AS VARCHAR(MAX))), 2)), 'n')",
+			mssqlChecksumHashExpr(src, col, family, typeMap))
+	}
+	return fmt.Sprintf("COALESCE(MD5(%s), 'n')", mysqlChecksumHashExpr(src, col, family, typeMap))
+}
+
+// pgChecksumHashExpr renders a target column as the canonical text of its type
+// family. Float canonicalization depends on the source: MSSQL floats arrive
+// bit-exact, MySQL floats are compared through their shortest decimal form.
+func pgChecksumHashExpr(ident, family, srcName string) string {
+	switch family {
+	case "float4", "float8":
+		if srcName == "MSSQL" {
+			return fmt.Sprintf("encode(%ssend(%s), 'hex')", family, ident)
+		}
+		return fmt.Sprintf("round(least(greatest(%s::float8::text::numeric, -%s), %s), 30)::text", ident, pgChecksumMaxDecimal, pgChecksumMaxDecimal)
+	case "bytea":
+		return fmt.Sprintf("encode(%s, 'hex')", ident)
+	case "date":
+		return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", ident)
+	case "timestamp":
+		return fmt.Sprintf("to_char(%s, 'YYYY-MM-DD HH24:MI:SS.US')", ident)
+	case "timestamptz":
+		return fmt.Sprintf("to_char(%s AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS.US')", ident)
+	case "time":
+		return fmt.Sprintf("to_char(date '2000-01-01' + %s, 'HH24:MI:SS.US')", ident)
+	case "interval":
+		return fmt.Sprintf("round(extract(epoch from %s)::numeric, 6)::text", ident)
+	case "json":
+		if srcName == "MySQL" {
+			// MySQL renders JSON normalized the way jsonb does.
+			return ident + "::jsonb::text"
+		}
+		return ident + "::text"
+	case "array":
+		return fmt.Sprintf("array_to_string(%s, ',')", ident)
+	case "geometry":
+		return fmt.Sprintf("encode(ST_AsEWKB(%s, 'NDR'), 'hex')", ident)
+	default:
+		return ident + "::text"
+	}
+}
+
+// mysqlChecksumHashExpr renders a MySQL column as the canonical text of its
+// mapped type family, following mysqlTransformValue.
+func mysqlChecksumHashExpr(src SourceDB, col Column, family string, typeMap TypeMappingConfig) string {
+	q := src.QuoteIdentifier(col.SourceName)
+	switch {
+	case family == "uuid" && isBinary16Column(col):
+		h := fmt.Sprintf("HEX(%s)", q)
+		parts := [][2]int{{1, 8}, {9, 4}, {13, 4}, {17, 4}, {21, 12}}
+		if typeMap.Binary16UUIDMode == "mysql_uuid_to_bin_swap" {
+			parts = [][2]int{{9, 8}, {5, 4}, {1, 4}, {17, 4}, {21, 12}}
+		}
+		subs := make([]string, len(parts))
+		for i, p := range parts {
+			subs[i] = fmt.Sprintf("SUBSTRING(%s, %d, %d)", h, p[0], p[1])
+		}
+		return fmt.Sprintf("LOWER(CONCAT(%s))", strings.Join(subs, ", '-', "))
+	case family == "uuid":
+		return fmt.Sprintf("LOWER(TRIM(%s))", q)
+	case family == "bool":
+		return fmt.Sprintf("CASE %s WHEN 1 THEN 'true' WHEN 0 THEN 'false' END", q)
+	case col.DataType == "year":
+		return fmt.Sprintf("CAST(%s + 0 AS CHAR)", q)
+	case family == "int", family == "numeric":
+		return fmt.Sprintf("CAST(%s AS CHAR)", q)
+	case family == "float4", family == "float8":
+		return fmt.Sprintf("CAST(CAST(%s AS DECIMAL(65,30)) AS CHAR)", q)
+	case col.DataType == "bit":
+		width := mysqlBitWidth(col)
+		if family == "bits" {
+			return fmt.Sprintf("LPAD(BIN(%s + 0), %d, '0')", q, width)
+		}
+		return fmt.Sprintf("LPAD(LOWER(HEX(%s + 0)), %d, '0')", q, (width+7)/8*2)
+	case family == "geometry":
+		// Rebuild mysqlSpatialToEWKB: plain WKB for SRID 0, otherwise the
+		// SRID flag on the type word and the SRID after it.
+		wkb := mysqlPostGISWKBExpr(src, q)
+		srid := fmt.Sprintf("ST_SRID(%s)", q)
+		return fmt.Sprintf("IF(%[2]s = 0, LOWER(HEX(%[1]s)), LOWER(CONCAT(HEX(SUBSTRING(%[1]s, 1, 4)), "+
+			"LPAD(HEX(ASCII(SUBSTRING(%[1]s, 5, 1)) | 32), 2, '0'), "+
+			"LPAD(HEX(%[2]s & 255), 2, '0'), LPAD(HEX((%[2]s >> 8) & 255), 2, '0'), "+
+			"LPAD(HEX((%[2]s >> 16) & 255), 2, '0'), LPAD(HEX((%[2]s >> 24) & 255), 2, '0'), "+
+			"HEX(SUBSTRING(%[1]s, 6)))))", wkb, srid)
+	case family == "bytea":
+		return fmt.Sprintf("LOWER(HEX(%s))", q)
+	case family == "date":
+		return fmt.Sprintf("NULLIF(DATE_FORMAT(%s, '%%Y-%%m-%%d'), '0000-00-00')", q)
+	case family == "timestamp", family == "timestamptz":
+		// Zero dates are migrated as NULL. The driver reads wall-clock values
+		// as UTC, which is what the target renders back.
+		return fmt.Sprintf("NULLIF(DATE_FORMAT(%s, '%%Y-%%m-%%d %%H:%%i:%%s.%%f'), '0000-00-00 00:00:00.000000')", q)
+	case family == "time":
+		return fmt.Sprintf("TIME_FORMAT(%s, '%%H:%%i:%%s.%%f')", q)
+	case family == "interval":
+		return fmt.Sprintf("CAST(CAST((HOUR(%[1]s) * 3600 + MINUTE(%[1]s) * 60 + SECOND(%[1]s) + MICROSECOND(%[1]s) * 0.000001) * IF(%[1]s < 0, -1, 1) AS DECIMAL(20,6)) AS CHAR)", q)
+	case family == "json":
+		return fmt.Sprintf("CAST(%s AS CHAR)", q)
+	case isMySQLSpatialType(col.DataType):
+		return fmt.Sprintf("ST_AsText(%s)", q)
+	case isCharacterSourceType(col.DataType):
+		// Covers set columns migrated to text[] too: array_to_string on the
+		// target restores the comma-joined source value.
+		return fmt.Sprintf("REPLACE(CONVERT(%s USING utf8mb4), CHAR(0 USING utf8mb4), '')", q)
+	default:
+		return fmt.Sprintf("CAST(%s AS CHAR)", q)
+	}
+}
+
+// mysqlBitWidth returns the declared width of a BIT column, as mysqlMapType does.
+func mysqlBitWidth(col Column) int64 {
+	n, ok := mysqlColumnTypeLength(col.ColumnType, "bit")
+	if !ok {
+		n = col.Precision
+	}
+	if n <= 0 {
+		n = 1
+	}
+	return n
+}
+
+// mssqlChecksumHashExpr renders an MSSQL column as the canonical text of its
+// mapped type family, following mssqlTransformValue and columnSelectExpr.
+func mssqlChecksumHashExpr(src SourceDB, col Column, family string, typeMap TypeMappingConfig) string {
+	q := src.QuoteIdentifier(col.SourceName)
+	switch {
+	case col.DataType == "money":
+		return fmt.Sprintf("CAST(CAST(%s AS DECIMAL(19,4)) AS VARCHAR(64))", q)
+	case col.DataType == "smallmoney":
+		return fmt.Sprintf("CAST(CAST(%s AS DECIMAL(10,4)) AS VARCHAR(64))", q)
+	case family == "int", family == "numeric":
+		return fmt.Sprintf("CAST(%s AS VARCHAR(64))", q)
+	case family == "float4":
+		return fmt.Sprintf("LOWER(CONVERT(VARCHAR(8), CAST(%s AS BINARY(4)), 2))", q)
+	case family == "float8":
+		return fmt.Sprintf("LOWER(CONVERT(VARCHAR(16), CAST(%s AS BINARY(8)), 2))", q)
+	case family == "bool":
+		return fmt.Sprintf("CASE %s WHEN 1 THEN 'true' WHEN 0 THEN 'false' END", q)
+	case family == "bytea":
+		if isMSSQLSpatialType(col.DataType) {
+			q += ".STAsBinary()"
+		}
+		return fmt.Sprintf("LOWER(CONVERT(VARCHAR(MAX), CAST(%s AS VARBINARY(MAX)), 2))", q)
+	case family == "date":
+		return fmt.Sprintf("CONVERT(VARCHAR(10), %s, 23)", q)
+	case family == "timestamp", family == "timestamptz":
+		if col.DataType == "datetimeoffset" {
+			q = fmt.Sprintf("SWITCHOFFSET(%s, '+00:00')", q)
+		}
+		// pgx truncates to microseconds, so cut the seventh digit instead of rounding.
+		return fmt.Sprintf("LEFT(CONVERT(VARCHAR(27), CAST(%s AS DATETIME2(7)), 121), 26)", q)
+	case family == "time":
+		return fmt.Sprintf("LEFT(CAST(CAST(%s AS TIME(7)) AS VARCHAR(16)), 15)", q)
+	case family == "uuid":
+		return fmt.Sprintf("LOWER(CAST(%s AS CHAR(36)))", q)
+	}
+
+	switch {
+	case col.DataType == "hierarchyid":
+		q += ".ToString()"
+	case isMSSQLSpatialType(col.DataType):
+		q += ".STAsText()"
+	}
+	text := fmt.Sprintf("REPLACE(CAST(%s AS NVARCHAR(MAX)) COLLATE Latin1_General_100_BIN2, NCHAR(0), N'')", q)
+	if family == "char" {
+		return fmt.Sprintf("RTRIM(%s)", text)
+	}
+	return text
+}
+
+// sourceSQLChecksumDigest reads the digest of a range hashed by the source.
+func sourceSQLChecksumDigest(ctx context.Context, source validationSource, query string) (checksumDigest, error) {
+	var rows int64
+	var lanes [4]string
+	if err := source.QueryRowContext(ctx, query).Scan(&rows, &lanes[0], &lanes[1], &lanes[2], &lanes[3]); err != nil {
+		return checksumDigest{}, fmt.Errorf("select: %w", err)
+	}
+	return checksumDigestFromLanes(rows, lanes)
+}
+
+// targetSQLChecksumDigest reads the digest of a range hashed by PostgreSQL.
+func targetSQLChecksumDigest(ctx context.Context, pool *pgxpool.Pool, query string) (checksumDigest, error) {
+	var rows int64
+	var lanes [4]string
+	if err := pool.QueryRow(ctx, query).Scan(&rows, &lanes[0], &lanes[1], &lanes[2], &lanes[3]); err != nil {
+		return checksumDigest{}, fmt.Errorf("select: %w", err)
+	}
+	return checksumDigestFromLanes(rows, lanes)
+}
+
+// checksumDigestFromLanes folds the sums of the four big-endian 32-bit words
+// of every row hash into the two wrapping 64-bit lanes of checksumDigest.
+func checksumDigestFromLanes(rows int64, lanes [4]string) (checksumDigest, error) {
+	var sums [4]*big.Int
+	for i, s := range lanes {
+		n, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+		if !ok || n.Sign() < 0 {
+			return checksumDigest{}, fmt.Errorf("invalid checksum lane sum %q", s)
+		}
+		sums[i] = n
+	}
+	mask := new(big.Int).SetUint64(^uint64(0))
+	fold := func(high, low *big.Int) uint64 {
+		v := new(big.Int).Lsh(high, 32)
+		return v.Add(v, low).And(v, mask).Uint64()
+	}
+	return checksumDigest{Rows: rows, hi: fold(sums[0], sums[1]), lo: fold(sums[2], sums[3])}, nil
+}
@@ -0,0 +1,376 @@
+package main
+
+import (
+	"crypto/md5"
+	"encoding/binary"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestChecksumRangesFromChunks_OpenEnds(t *testing.T) {
+	ranges := checksumRangesFromChunks(planChunks(1, 250, 100))
+	if len(ranges) != 3 {
+		t.Fatalf("len(ranges) = %d, want 3", len(ranges))
+	}
+	if ranges[0].HasLower || !ranges[0].HasUpper {
+		t.Errorf("first range should be open below and bounded above: %+v", ranges[0])
+	}
+	if !ranges[1].HasLower || !ranges[1].HasUpper {
+		t.Errorf("middle range should be bounded on both sides: %+v", ranges[1])
+	}
+	if !ranges[2].HasLower || ranges[2].HasUpper {
+		t.Errorf("last range should be bounded below and open above: %+v", ranges[2])
+	}
+
+	single := checksumRangesFromChunks(planChunks(1, 10, 100))
+	if got := checksumRangePredicate(`"id"`, single[0]); got != "" {
+		t.Errorf("single-range predicate = %q, want empty", got)
+	}
+}
+
+func TestBuildSourceChecksumQuery_MySQL(t *testing.T) {
+	src := &mysqlSourceDB{}
+	table := Table{
+		SourceName: "users",
+		PGName:     "users",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id", DataType: "int"},
+			{SourceName: "email", PGName: "email", DataType: "varchar"},
+		},
+	}
+	key := &ChunkKey{SourceColumn: "id", PGColumn: "id"}
+	r := checksumRange{Index: 1, Lower: 100, Upper: 200, HasLower: true, HasUpper: true}
+
+	got := buildSourceChecksumQuery(src, table, key, r, defaultTypeMappingConfig())
+	want := "SELECT `id`, `email` FROM `users` WHERE `id` >= 100 AND `id` < 200"
+	if got != want {
+		t.Errorf("buildSourceChecksumQuery() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildTargetChecksumQuery(t *testing.T) {
+	table := Table{
+		SourceName: "events",
+		PGName:     "events",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id"},
+			{SourceName: "amount", PGName: "amount"},
+			{SourceName: "tags", PGName: "tags"},
+		},
+	}
+	families := []string{"int", "numeric", "array"}
+	key := &ChunkKey{SourceColumn: "id", PGColumn: "id"}
+	r := checksumRange{Index: 2, Lower: 500, HasLower: true}
+
+	got := buildTargetChecksumQuery("app", table, families, key, r)
+	want := `SELECT "id", "amount"::text, array_to_json("tags")::text FROM "app"."events" WHERE "id" >= 500`
+	if got != want {
+		t.Errorf("buildTargetChecksumQuery() = %q, want %q", got, want)
+	}
+
+	got = buildTargetChecksumQuery("app", table, families, nil, checksumRange{})
+	if strings.Contains(got, "WHERE") {
+		t.Errorf("unkeyed query should not filter: %q", got)
+	}
+}
+
+func TestChecksumTypeFamily(t *testing.T) {
+	tests := map[string]string{
+		"integer":                   "int",
+		"numeric(20)":               "numeric",
+		"numeric(10,2)":             "numeric",
+		"real":                      "float4",
+		"double precision":          "float8",
+		"timestamptz":               "timestamptz",
+		"timestamp":                 "timestamp",
+		"jsonb":                     "json",
+		"text[]":                    "array",
+		"bit(8)":                    "bits",
+		"char(10)":                  "char",
+		"varchar(255)":              "text",
+		"citext":                    "text",
+		"pgferry_enum_0123456789ab": "text",
+	}
+	for pgType, want := range tests {
+		if got := checksumTypeFamily(pgType); got != want {
+			t.Errorf("checksumTypeFamily(%q) = %q, want %q", pgType, got, want)
+		}
+	}
+}
+
+// Each case pairs a transformed source value with the value the target-side
+// checksum query returns for the same data; both must canonicalize equally.
+func TestChecksumCanonicalValue_SourceTargetParity(t *testing.T) {
+	ts := time.Date(2024, 3, 5, 10, 11, 12, 345600000, time.UTC)
+	tests := []struct {
+		name   string
+		family string
+		source any
+		target any
+	}{
+		{"int from bytes", "int", []byte("42"), int64(42)},
+		{"unsigned bigint", "numeric", []byte("18446744073709551615"), "18446744073709551615"},
+		{"decimal scale", "numeric", []byte("12.50"), "12.50"},
+		{"sqlite numeric float", "numeric", float64(12.5), "12.50"},
+		{"money string", "numeric", "19.9900", "19.9900"},
+		{"float4", "float4", float32(0.1), float32(0.1)},
+		{"float8 from bytes", "float8", []byte("3.25"), float64(3.25)},
+		{"bool from sqlite int", "bool", int64(1), true},
+		{"bytea", "bytea", []byte{0xde, 0xad}, []byte{0xde, 0xad}},
+		{"date", "date", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), "2024-03-05"},
+		{"timestamp", "timestamp", ts, ts},
+		{"sqlite timestamp text", "timestamp", "2024-03-05 10:11:12.3456", ts},
+		{"timestamptz zone", "timestamptz", ts.In(time.FixedZone("x", 3600)), ts},
+		{"mysql time", "time", "10:11:12.500000", "10:11:12.5"},
+		{"interval", "interval", "838 hours 59 mins 59 secs", "3020399.000000"},
+		{"json key order", "json", `{"b":1,"a":[1,2]}`, `{"a": [1, 2], "b": 1}`},
+		{"set array", "array", []string{"a", "b"}, `["a","b"]`},
+		{"char padding", "char", "ab  ", "ab"},
+		{"uuid case", "uuid", "A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checksumCanonicalValue(tt.source, tt.family)
+			want := checksumCanonicalValue(tt.target, tt.family)
+			if got != want {
+				t.Errorf("source canonical %q != target canonical %q", got, want)
+			}
+		})
+	}
+}
+
+func TestChecksumCanonicalValue_DetectsDifferences(t *testing.T) {
+	tests := []struct {
+		name   string
+		family string
+		a, b   any
+	}{
+		{"truncated text", "text", "hello world", "hello wor"},
+		{"case change", "text", "Foo@x", "foo@x"},
+		{"timezone shift", "timestamp", time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)},
+		{"precision loss", "numeric", "12.345", "12.35"},
+		{"uuid byte order", "uuid", "33221100-5544-7766-8899-aabbccddeeff", "00112233-4455-6677-8899-aabbccddeeff"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if checksumCanonicalValue(tt.a, tt.family) == checksumCanonicalValue(tt.b, tt.family) {
+				t.Errorf("expected %v and %v to canonicalize differently", tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestChecksumRowEncoder_NullDistinctFromEmpty(t *testing.T) {
+	var enc checksumRowEncoder
+	enc.add(nil, "text")
+	nullSum := enc.sum()
+
+	enc.reset()
+	enc.add("", "text")
+	emptySum := enc.sum()
+
+	if nullSum == emptySum {
+		t.Fatal("NULL and empty string must hash differently")
+	}
+}
+
+func TestChecksumDigest_OrderIndependent(t *testing.T) {
+	rows := [][md5.Size]byte{md5.Sum([]byte("a")), md5.Sum([]byte("b")), md5.Sum([]byte("c"))}
+
+	var forward, backward checksumDigest
+	for i := range rows {
+		forward.addRow(rows[i])
+		backward.addRow(rows[len(rows)-1-i])
+	}
+	if forward != backward {
+		t.Errorf("digest depends on row order: %s vs %s", forward, backward)
+	}
+
+	var dup checksumDigest
+	dup.addRow(rows[0])
+	dup.addRow(rows[0])
+	var single checksumDigest
+	single.addRow(rows[0])
+	if dup == single {
+		t.Error("duplicate rows must change the digest")
+	}
+}
+
+func TestChunkChecksumMismatch_Describe(t *testing.T) {
+	lower, upper := int64(100), int64(200)
+	tests := []struct {
+		m    ChunkChecksumMismatch
+		key  string
+		want string
+	}{
+		{ChunkChecksumMismatch{ChunkIndex: 1, LowerBound: &lower, UpperBound: &upper}, "id", "chunk 1 (id 100..200)"},
+		{ChunkChecksumMismatch{ChunkIndex: 0, UpperBound: &upper}, "id", "chunk 0 (id < 200)"},
+		{ChunkChecksumMismatch{ChunkIndex: 2, LowerBound: &lower}, "id", "chunk 2 (id >= 100)"},
+		{ChunkChecksumMismatch{}, "", "full table"},
+	}
+	for _, tt := range tests {
+		if got := tt.m.describe(tt.key); got != tt.want {
+			t.Errorf("describe() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestBuildTargetDigestQuery(t *testing.T) {
+	table := Table{
+		SourceName: "events",
+		PGName:     "events",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id"},
+			{SourceName: "at", PGName: "at"},
+		},
+	}
+	key := &ChunkKey{SourceColumn: "id", PGColumn: "id"}
+	r := checksumRange{Index: 2, Lower: 500, HasLower: true}
+
+	got := buildTargetDigestQuery("app", &mysqlSourceDB{}, table, []string{"int", "timestamptz"}, key, r)
+	want := `SELECT count(*), ` +
+		`coalesce(sum(('x' || substr(h, 1, 8))::bit(32)::bigint), 0)::text, ` +
+		`coalesce(sum(('x' || substr(h, 9, 8))::bit(32)::bigint), 0)::text, ` +
+		`coalesce(sum(('x' || substr(h, 17, 8))::bit(32)::bigint), 0)::text, ` +
+		`coalesce(sum(('x' || substr(h, 25, 8))::bit(32)::bigint), 0)::text ` +
+		`FROM (SELECT md5(coalesce(md5("id"::text), 'n') || ` +
+		`coalesce(md5(to_char("at" AT TIME ZONE 'UTC', 'YYYY-MM-DD HH24:MI:SS.US')), 'n')) AS h ` +
+		`FROM "app"."events" WHERE "id" >= 500) AS r`
+	if got != want {
+		t.Errorf("buildTargetDigestQuery() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestBuildSourceDigestQuery_MySQL(t *testing.T) {
+	table := Table{
+		SourceName: "users",
+		PGName:     "users",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id", DataType: "int"},
+			{SourceName: "email", PGName: "email", DataType: "varchar"},
+		},
+	}
+	key := &ChunkKey{SourceColumn: "id", PGColumn: "id"}
+	r := checksumRange{Index: 1, Lower: 100, Upper: 200, HasLower: true, HasUpper: true}
+
+	got := buildSourceDigestQuery(&mysqlSourceDB{}, table, []string{"int", "text"}, key, r, defaultTypeMappingConfig())
+	for _, want := range []string{
+		"SELECT COUNT(*), COALESCE(SUM(CAST(CONV(SUBSTRING(h, 1, 8), 16, 10) AS UNSIGNED)), 0), ",
+		"MD5(CONCAT(COALESCE(MD5(CAST(`id` AS CHAR)), 'n'), COALESCE(MD5(REPLACE(CONVERT(`email` USING utf8mb4), CHAR(0 USING utf8mb4), '')), 'n')))",
+		"FROM `users` WHERE `id` >= 100 AND `id` < 200) AS r",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("buildSourceDigestQuery() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestBuildSourceDigestQuery_MSSQL(t *testing.T) {
+	table := Table{
+		SourceName: "orders",
+		PGName:     "orders",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id", DataType: "int"},
+			{SourceName: "code", PGName: "code", DataType: "nchar", CharMaxLen: 4},
+		},
+	}
+
+	got := buildSourceDigestQuery(&mssqlSourceDB{}, table, []string{"int", "char"}, nil, checksumRange{}, defaultTypeMappingConfig())
+	for _, want := range []string{
+		"SELECT COUNT_BIG(*), CAST(ISNULL(SUM(CAST(CAST(SUBSTRING(h, 1, 4) AS BIGINT) AS DECIMAL(38,0))), 0) AS VARCHAR(40)), ",
+		"HASHBYTES('MD5', ISNULL(LOWER(CONVERT(VARCHAR(32), HASHBYTES('MD5', CAST((CAST([id] AS VARCHAR(64))) COLLATE Latin1_General_100_BIN2_UTF8 AS VARCHAR(MAX))), 2)), 'n') + ",
+		"RTRIM(REPLACE(CAST([code] AS NVARCHAR(MAX)) COLLATE Latin1_General_100_BIN2, NCHAR(0), N''))",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("buildSourceDigestQuery() = %q, missing %q", got, want)
+		}
+	}
+	if strings.Contains(got, "WHERE") {
+		t.Errorf("unkeyed query should not filter: %q", got)
+	}
+}
+
+func TestMySQLChecksumHashExpr(t *testing.T) {
+	src := &mysqlSourceDB{}
+	typeMap := defaultTypeMappingConfig()
+	typeMap.Binary16AsUUID = true
+	typeMap.Binary16UUIDMode = "mysql_uuid_to_bin_swap"
+
+	tests := []struct {
+		col    Column
+		family string
+		want   string
+	}{
+		{Column{SourceName: "y", DataType: "year"}, "int", "CAST(`y` + 0 AS CHAR)"},
+		{Column{SourceName: "f", DataType: "float"}, "float4", "CAST(CAST(`f` AS DECIMAL(65,30)) AS CHAR)"},
+		{Column{SourceName: "b", DataType: "bit", ColumnType: "bit(10)"}, "bits", "LPAD(BIN(`b` + 0), 10, '0')"},
+		{Column{SourceName: "b", DataType: "bit", ColumnType: "bit(10)"}, "bytea", "LPAD(LOWER(HEX(`b` + 0)), 4, '0')"},
+		{Column{SourceName: "d", DataType: "date"}, "date", "NULLIF(DATE_FORMAT(`d`, '%Y-%m-%d'), '0000-00-00')"},
+		{Column{SourceName: "t", DataType: "time"}, "time", "TIME_FORMAT(`t`, '%H:%i:%s.%f')"},
+		{
+			Column{SourceName: "u", DataType: "binary", ColumnType: "binary(16)"}, "uuid",
+			"LOWER(CONCAT(SUBSTRING(HEX(`u`), 9, 8), '-', SUBSTRING(HEX(`u`), 5, 4), '-', SUBSTRING(HEX(`u`), 1, 4), '-', SUBSTRING(HEX(`u`), 17, 4), '-', SUBSTRING(HEX(`u`), 21, 12)))",
+		},
+	}
+	for _, tt := range tests {
+		if got := mysqlChecksumHashExpr(src, tt.col, tt.family, typeMap); got != tt.want {
+			t.Errorf("mysqlChecksumHashExpr(%s, %s) = %q, want %q", tt.col.DataType, tt.family, got, tt.want)
+		}
+	}
+}
+
+func TestChecksumHashesInSQL(t *testing.T) {
+	table := Table{Columns: []Column{{SourceName: "doc", DataType: "json"}}}
+	typeMap := defaultTypeMappingConfig()
+	typeMap.JSONAsJSONB = false
+
+	if !checksumHashesInSQL(&mysqlSourceDB{}, table, typeMap) {
+		t.Error("MySQL tables should hash in SQL")
+	}
+	if checksumHashesInSQL(&sqliteSourceDB{}, table, typeMap) {
+		t.Error("SQLite tables should hash client-side")
+	}
+	if !checksumHashesInSQL(&mssqlSourceDB{}, table, typeMap) {
+		t.Error("MSSQL json columns kept as json should hash in SQL")
+	}
+	typeMap.JSONAsJSONB = true
+	if checksumHashesInSQL(&mssqlSourceDB{}, table, typeMap) {
+		t.Error("MSSQL json columns mapped to jsonb should hash client-side")
+	}
+}
+
+// The SQL digest queries only return lane sums; folding them must give the
+// same digest as adding the row hashes one by one.
+func TestChecksumDigestFromLanes_MatchesAddRow(t *testing.T) {
+	var want checksumDigest
+	var sums [4]uint64
+	for i := range 1000 {
+		sum := md5.Sum([]byte(strconv.Itoa(i)))
+		want.addRow(sum)
+		for lane := range sums {
+			sums[lane] += uint64(binary.BigEndian.Uint32(sum[lane*4:]))
+		}
+	}
+	var lanes [4]string
+	for i, s := range sums {
+		lanes[i] = strconv.FormatUint(s, 10)
+	}
+
+	got, err := checksumDigestFromLanes(want.Rows, lanes)
+	if err != nil {
+		t.Fatalf("checksumDigestFromLanes: %v", err)
+	}
+	if got != want {
+		t.Errorf("checksumDigestFromLanes() = %s, want %s", got, want)
+	}
+
+	empty, err := checksumDigestFromLanes(0, [4]string{"0", "0", "0", "0"})
+	if err != nil || empty != (checksumDigest{}) {
+		t.Errorf("empty range digest = %+v, %v; want zero", empty, err)
+	}
+	if _, err := checksumDigestFromLanes(1, [4]string{"1", "x", "0", "0"}); err == nil {
+		t.Error("expected error for a non-numeric lane sum")
+	}
+}
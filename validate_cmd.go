@@ -0,0 +1,317 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/spf13/cobra"
+)
+
+var validateConfigPath string
+var validateTables []string
+var validateFormat string
+var validateMode string
+var validateSchemaCheck bool
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [migration.toml]",
+	Short: "Compare a migrated PostgreSQL schema against its source",
+	Long: `Re-run post-load validation against an existing migration without
+re-migrating any data. Uses the source, target, schema, and type mapping
+settings from the migration config.
+
+With --schema-check, also compares the target schema's columns, types,
+nullability, collations, keys, indexes, foreign keys, and sequences against
+what pgferry would generate from the source.
+
+Exits with a non-zero status when any table fails validation or the schema
+check finds differences.`,
+	Args:         cobra.MaximumNArgs(1),
+	RunE:         runValidate,
+	SilenceUsage: true,
+}
+
+func init() {
+	validateCmd.Flags().StringVar(&validateConfigPath, "config", "", "path to migration TOML config file")
+	validateCmd.Flags().StringSliceVar(&validateTables, "tables", nil, "comma-separated source or PostgreSQL table names to validate (default: all)")
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format: text or json")
+	validateCmd.Flags().StringVar(&validateMode, "mode", "", "validation mode: row_count, checksum, sample, or aggregate (default: config validation, or row_count when it is none)")
+	validateCmd.Flags().BoolVar(&validateSchemaCheck, "schema-check", false, "also compare the target schema against what pgferry would generate")
+}
+
+// ValidationReport is the output of the validate command.
+type ValidationReport struct {
+	Mode   string             `json:"mode"`
+	Schema string             `json:"schema"`
+	Passed bool               `json:"passed"`
+	Tables []ValidationResult `json:"tables"`
+	// SchemaIssues is only populated when the schema check was requested.
+	SchemaIssues []SchemaIssue `json:"schema_issues,omitempty"`
+}
+
+// addSchemaIssues attaches schema check findings to the report; any issue
+// fails it.
+func (r *ValidationReport) addSchemaIssues(issues []SchemaIssue) {
+	r.SchemaIssues = issues
+	if len(issues) > 0 {
+		r.Passed = false
+	}
+}
+
+// errValidationMismatch is returned by the validate command when validation
+// completed but at least one table did not match.
+var errValidationMismatch = errors.New("validation found mismatches")
+
+func runValidate(cmd *cobra.Command, args []string) error {
+	cfgPath := validateConfigPath
+	if len(args) > 0 {
+		cfgPath = args[0]
+	}
+	if cfgPath == "" {
+		return fmt.Errorf("config file required: pgferry validate <migration.toml> or pgferry validate --config <migration.toml>")
+	}
+
+	switch validateFormat {
+	case "text", "json":
+	default:
+		return fmt.Errorf("--format must be text or json")
+	}
+
+	cfg, err := loadConfig(cfgPath)
+	if err != nil {
+		return err
+	}
+
+	mode, err := resolveValidateMode(validateMode, cfg.Validation)
+	if err != nil {
+		return err
+	}
+
+	return runValidateWithConfig(cfg, mode, validateTables, validateFormat, validateSchemaCheck, cmd.OutOrStdout())
+}
+
+// resolveValidateMode picks the validation mode for the validate command: the
+// --mode flag wins, then the config's validation setting. A config that
+// disables validation falls back to row_count, since running the command is an
+// explicit request to validate.
+func resolveValidateMode(flagMode, cfgMode string) (string, error) {
+	mode := flagMode
+	if mode == "" {
+		mode = cfgMode
+	}
+	switch mode {
+	case "", "none":
+		return "row_count", nil
+	case "row_count", "checksum", "sample", "aggregate":
+		return mode, nil
+	default:
+		return "", fmt.Errorf("--mode must be one of: row_count, checksum, sample, aggregate")
+	}
+}
+
+func runValidateWithConfig(cfg *MigrationConfig, mode string, tables []string, format string, schemaCheck bool, out io.Writer) error {
+	ctx := context.Background()
+
+	src, err := newConfiguredSourceDB(cfg)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("pgferry validate — %s → PostgreSQL (mode=%s)", src.Name(), mode)
+
+	schema, err := introspectSourceSchema(ctx, src, cfg)
+	if err != nil {
+		return err
+	}
+
+	schema, err = filterSchemaTables(schema, tables)
+	if err != nil {
+		return err
+	}
+
+	pgPool, err := pgxpool.New(ctx, cfg.Target.DSN)
+	if err != nil {
+		return fmt.Errorf("connect postgres: %w", err)
+	}
+	defer pgPool.Close()
+
+	if err := pgPool.Ping(ctx); err != nil {
+		return fmt.Errorf("ping postgres: %w", err)
+	}
+	if err := loadTargetPartitions(ctx, pgPool, cfg.Schema, schema); err != nil {
+		return err
+	}
+
+	log.Printf("validating %d table(s) in schema '%s'...", len(schema.Tables), cfg.Schema)
+	results, err := validateMigration(ctx, validationConfig{
+		Src:        src,
+		SrcDSN:     cfg.Source.DSN,
+		Pool:       pgPool,
+		Schema:     schema,
+		PGSchema:   cfg.Schema,
+		Mode:       mode,
+		Workers:    cfg.Workers,
+		TypeMap:    effectiveTypeMapping(cfg),
+		ChunkSize:  cfg.ChunkSize,
+		SampleSize: cfg.ValidationSampleSize,
+		Quiet:      true,
+	})
+	if results == nil && err != nil {
+		return err
+	}
+
+	report := buildValidationReport(mode, cfg.Schema, results)
+	if schemaCheck {
+		log.Printf("checking schema conformance for '%s'...", cfg.Schema)
+		issues, err := checkSchemaConformance(ctx, pgPool, schema, cfg.Schema, src, effectiveTypeMapping(cfg))
+		if err != nil {
+			return fmt.Errorf("schema check: %w", err)
+		}
+		report.addSchemaIssues(issues)
+	}
+
+	if format == "json" {
+		if err := writeValidationJSON(out, report); err != nil {
+			return err
+		}
+	} else {
+		writeValidationText(out, report)
+	}
+
+	if !report.Passed {
+		return errValidationMismatch
+	}
+	return nil
+}
+
+// introspectSourceSchema connects to the source just long enough to read its
+// schema. Commands that work on an existing migration use it to recover the
+// table layout and name mapping.
+func introspectSourceSchema(ctx context.Context, src SourceDB, cfg *MigrationConfig) (*Schema, error) {
+	sourceDB, err := src.OpenDB(cfg.Source.DSN)
+	if err != nil {
+		return nil, err
+	}
+	defer sourceDB.Close()
+	sourceDB.SetMaxOpenConns(1)
+
+	if err := sourceDB.PingContext(ctx); err != nil {
+		return nil, fmt.Errorf("ping %s: %w", strings.ToLower(src.Name()), err)
+	}
+
+	dbName, err := src.ExtractDBName(cfg.Source.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("introspecting %s schema '%s'...", src.Name(), dbName)
+	schema, err := src.IntrospectSchema(sourceDB, dbName)
+	if err != nil {
+		return nil, fmt.Errorf("introspect schema: %w", err)
+	}
+	translateGeneratedColumns(schema, src, effectiveTypeMapping(cfg))
+	translateIndexExpressions(schema, src, effectiveTypeMapping(cfg))
+	translatePartitioning(schema, src, effectiveTypeMapping(cfg))
+	// Partition bounds come from the target (loadTargetPartitions), not from
+	// the source data as it is now.
+	if err := applyPartitioningConfig(ctx, nil, src, schema, cfg.Partitioning, effectiveTypeMapping(cfg)); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// filterSchemaTables restricts schema to the named tables, matching either
+// source or PostgreSQL names. An empty list keeps every table.
+func filterSchemaTables(schema *Schema, names []string) (*Schema, error) {
+	if len(names) == 0 {
+		return schema, nil
+	}
+
+	wanted := make(map[string]bool, len(names))
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n != "" {
+			wanted[n] = false
+		}
+	}
+
+	filtered := &Schema{}
+	for _, t := range schema.Tables {
+		_, bySource := wanted[t.SourceName]
+		_, byPG := wanted[t.PGName]
+		if !bySource && !byPG {
+			continue
+		}
+		filtered.Tables = append(filtered.Tables, t)
+		if bySource {
+			wanted[t.SourceName] = true
+		}
+		if byPG {
+			wanted[t.PGName] = true
+		}
+	}
+
+	var unknown []string
+	for _, n := range names {
+		if n = strings.TrimSpace(n); n != "" && !wanted[n] {
+			unknown = append(unknown, n)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown table(s) in --tables: %s", strings.Join(unknown, ", "))
+	}
+	return filtered, nil
+}
+
+func buildValidationReport(mode, pgSchema string, results []ValidationResult) *ValidationReport {
+	report := &ValidationReport{
+		Mode:   mode,
+		Schema: pgSchema,
+		Passed: true,
+		Tables: results,
+	}
+	if report.Tables == nil {
+		report.Tables = []ValidationResult{}
+	}
+	for _, r := range results {
+		if !r.passed() {
+			report.Passed = false
+		}
+	}
+	return report
+}
+
+func writeValidationJSON(w io.Writer, report *ValidationReport) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(report)
+}
+
+func writeValidationText(w io.Writer, report *ValidationReport) {
+	failed := 0
+	for _, r := range report.Tables {
+		if !r.passed() {
+			failed++
+		}
+		for _, line := range validationResultLines(r) {
+			fmt.Fprintln(w, line)
+		}
+	}
+	for _, issue := range report.SchemaIssues {
+		fmt.Fprintf(w, "SCHEMA: %s\n", issue.describe())
+	}
+	if report.Passed {
+		fmt.Fprintf(w, "\nvalidation passed: %d table(s), mode=%s\n", len(report.Tables), report.Mode)
+		return
+	}
+	if len(report.SchemaIssues) > 0 {
+		fmt.Fprintf(w, "\nvalidation failed: %d of %d table(s) mismatched, %d schema issue(s), mode=%s\n", failed, len(report.Tables), len(report.SchemaIssues), report.Mode)
+		return
+	}
+	fmt.Fprintf(w, "\nvalidation failed: %d of %d table(s) mismatched, mode=%s\n", failed, len(report.Tables), report.Mode)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestResolveValidateMode(t *testing.T) {
+	tests := []struct {
+		flag, cfg, want string
+	}{
+		{"", "none", "row_count"},
+		{"", "checksum", "checksum"},
+		{"aggregate", "row_count", "aggregate"},
+		{"sample", "none", "sample"},
+	}
+	for _, tt := range tests {
+		got, err := resolveValidateMode(tt.flag, tt.cfg)
+		if err != nil {
+			t.Fatalf("resolveValidateMode(%q, %q) error: %v", tt.flag, tt.cfg, err)
+		}
+		if got != tt.want {
+			t.Errorf("resolveValidateMode(%q, %q) = %q, want %q", tt.flag, tt.cfg, got, tt.want)
+		}
+	}
+
+	if _, err := resolveValidateMode("bogus", "none"); err == nil {
+		t.Error("expected error for unknown --mode")
+	}
+}
+
+func TestFilterSchemaTables(t *testing.T) {
+	schema := &Schema{Tables: []Table{
+		{SourceName: "Users", PGName: "users"},
+		{SourceName: "OrderItems", PGName: "order_items"},
+		{SourceName: "Logs", PGName: "logs"},
+	}}
+
+	got, err := filterSchemaTables(schema, []string{"order_items", "Users"})
+	if err != nil {
+		t.Fatalf("filterSchemaTables() error: %v", err)
+	}
+	if len(got.Tables) != 2 || got.Tables[0].PGName != "users" || got.Tables[1].PGName != "order_items" {
+		t.Errorf("filtered tables = %+v, want users and order_items in schema order", got.Tables)
+	}
+
+	all, err := filterSchemaTables(schema, nil)
+	if err != nil || len(all.Tables) != 3 {
+		t.Errorf("empty filter should keep all tables, got %d (err=%v)", len(all.Tables), err)
+	}
+
+	_, err = filterSchemaTables(schema, []string{"users", "missing"})
+	if err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected unknown table error naming 'missing', got %v", err)
+	}
+}
+
+func TestBuildValidationReport(t *testing.T) {
+	ok := ValidationResult{Table: "users", SourceCount: 3, TargetCount: 3, CountMatch: true}
+	bad := ValidationResult{Table: "orders", SourceCount: 3, TargetCount: 2}
+
+	if r := buildValidationReport("row_count", "app", []ValidationResult{ok}); !r.Passed {
+		t.Error("report with only matching tables should pass")
+	}
+	if r := buildValidationReport("row_count", "app", []ValidationResult{ok, bad}); r.Passed {
+		t.Error("report with a mismatched table should fail")
+	}
+	if r := buildValidationReport("row_count", "app", nil); r.Tables == nil {
+		t.Error("Tables should be an empty slice, not nil")
+	}
+}
+
+func TestWriteValidationJSON(t *testing.T) {
+	report := buildValidationReport("sample", "app", []ValidationResult{{
+		Table:       "users",
+		SourceCount: 2,
+		TargetCount: 2,
+		CountMatch:  true,
+		SampledRows: 2,
+		RowDiffs:    []SampleRowDiff{{Key: "id=1", Column: "email", Source: "'Foo@x'", Target: "'foo@x'"}},
+	}})
+
+	var buf bytes.Buffer
+	if err := writeValidationJSON(&buf, report); err != nil {
+		t.Fatalf("writeValidationJSON() error: %v", err)
+	}
+
+	var decoded map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded["passed"] != false {
+		t.Errorf("passed = %v, want false", decoded["passed"])
+	}
+	tables := decoded["tables"].([]any)
+	table := tables[0].(map[string]any)
+	if _, ok := table["chunk_mismatches"]; ok {
+		t.Error("empty chunk_mismatches should be omitted")
+	}
+	diffs := table["row_diffs"].([]any)
+	if diffs[0].(map[string]any)["column"] != "email" {
+		t.Errorf("row_diffs = %v", diffs)
+	}
+}
+
+func TestWriteValidationText(t *testing.T) {
+	report := buildValidationReport("row_count", "app", []ValidationResult{
+		{Table: "users", SourceCount: 3, TargetCount: 3, CountMatch: true},
+		{Table: "orders", SourceCount: 3, TargetCount: 2},
+	})
+
+	var buf bytes.Buffer
+	writeValidationText(&buf, report)
+	out := buf.String()
+
+	for _, want := range []string{
+		"OK: users — 3 rows",
+		"MISMATCH: orders — source=3 target=2",
+		"validation failed: 1 of 2 table(s) mismatched, mode=row_count",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunValidate_RequiresConfig(t *testing.T) {
+	validateConfigPath = ""
+	err := runValidate(validateCmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "config file required") {
+		t.Fatalf("expected config required error, got %v", err)
+	}
+}
@@ -0,0 +1,374 @@
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SampleRowDiff describes one difference found by sampled row comparison:
+// either a column whose values differ or a sampled row missing from the target.
+type SampleRowDiff struct {
+	Key     string `json:"key"`              // e.g. "id=123" or "a=1, b=2"
+	Column  string `json:"column,omitempty"` // empty when the whole row is missing
+	Source  string `json:"source,omitempty"`
+	Target  string `json:"target,omitempty"`
+	Missing bool   `json:"missing,omitempty"`
+}
+
+func (d SampleRowDiff) describe(table string) string {
+	if d.Missing {
+		return fmt.Sprintf("%s.%s: row missing in target", table, d.Key)
+	}
+	return fmt.Sprintf("%s.%s column %s: %s vs %s", table, d.Key, d.Column, d.Source, d.Target)
+}
+
+// sampleRow is one sampled row with its values canonicalized per column.
+// A nil entry means SQL NULL.
+type sampleRow struct {
+	key    string
+	values []*string
+}
+
+// samplePrimaryKeyColumns returns the positions of the primary key columns in
+// table.Columns, or nil when the table has no usable primary key.
+func samplePrimaryKeyColumns(table Table) []int {
+	if table.PrimaryKey == nil || len(table.PrimaryKey.Columns) == 0 {
+		return nil
+	}
+	positions := make([]int, 0, len(table.PrimaryKey.Columns))
+	for _, pkCol := range table.PrimaryKey.Columns {
+		found := false
+		for i, col := range table.Columns {
+			if col.PGName == pkCol {
+				positions = append(positions, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil
+		}
+	}
+	return positions
+}
+
+// sampleSourceRows reads up to n random source rows without sorting the
+// table. Tables with a single-column integer primary key are sampled by
+// looking up random key values between its MIN and MAX; other tables keep
+// each row with probability n/rowCount in one unsorted pass. rowCount is
+// counted when not known (<= 0).
+func sampleSourceRows(ctx context.Context, source validationSource, src SourceDB, table Table, n int, rowCount int64, families []string, pkPositions []int, typeMap TypeMappingConfig) ([]sampleRow, []any, error) {
+	if rowCount <= 0 {
+		if err := source.QueryRowContext(ctx, buildSourceCountQuery(src, table)).Scan(&rowCount); err != nil {
+			return nil, nil, fmt.Errorf("count: %w", err)
+		}
+		if rowCount == 0 {
+			return nil, nil, nil
+		}
+	}
+
+	var query string
+	if key := chunkKeyForTable(table, src); key != nil {
+		minVal, maxVal, ok, err := queryMinMax(ctx, source, src, table, *key)
+		if err != nil || !ok {
+			return nil, nil, err
+		}
+		query = buildSourceKeySampleQuery(src, table, *key, randomSampleKeys(minVal, maxVal, n, rowCount), n, typeMap)
+	} else {
+		query = buildSourceRandomSampleQuery(src, table, n, rowCount, typeMap)
+	}
+	return fetchSourceSample(ctx, source, src, table, query, families, pkPositions, typeMap)
+}
+
+// randomSampleKeys draws distinct random key values in [minVal, maxVal].
+// Sparse keys get proportionally more draws, up to 4n, so that roughly n of
+// them hit a row. Small ranges are returned whole.
+func randomSampleKeys(minVal, maxVal int64, n int, rowCount int64) []int64 {
+	span := uint64(maxVal-minVal) + 1 // 0 when the range covers every int64
+	spanF := float64(span)
+	if span == 0 {
+		spanF = math.Exp2(64)
+	}
+	draws := 4 * uint64(n)
+	if perRow := spanF / float64(max(rowCount, 1)); perRow < 4 {
+		draws = max(uint64(n), uint64(float64(n)*perRow))
+	}
+
+	var keys []int64
+	if span != 0 && span <= 2*draws {
+		for v := minVal; ; v++ {
+			keys = append(keys, v)
+			if v == maxVal {
+				return keys
+			}
+		}
+	}
+	seen := make(map[int64]bool, draws)
+	for uint64(len(keys)) < draws {
+		var off uint64
+		if span == 0 {
+			off = rand.Uint64()
+		} else {
+			off = rand.Uint64N(span)
+		}
+		v := minVal + int64(off)
+		if !seen[v] {
+			seen[v] = true
+			keys = append(keys, v)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// sourceSampleSelect renders a SELECT of at most n rows using the same
+// column expressions as the COPY path.
+func sourceSampleSelect(src SourceDB, table Table, where string, n int, typeMap TypeMappingConfig) string {
+	cols := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		cols[i] = columnSelectExpr(src, col, typeMap)
+	}
+	colList := strings.Join(cols, ", ")
+	ref := src.SourceTableRef(table)
+	if where != "" {
+		where = " WHERE " + where
+	}
+	if src.Name() == "MSSQL" {
+		return fmt.Sprintf("SELECT TOP (%d) %s FROM %s%s", n, colList, ref, where)
+	}
+	return fmt.Sprintf("SELECT %s FROM %s%s LIMIT %d", colList, ref, where, n)
+}
+
+// buildSourceKeySampleQuery selects the rows whose key is one of keys.
+func buildSourceKeySampleQuery(src SourceDB, table Table, key ChunkKey, keys []int64, n int, typeMap TypeMappingConfig) string {
+	values := make([]string, len(keys))
+	for i, k := range keys {
+		values[i] = strconv.FormatInt(k, 10)
+	}
+	where := fmt.Sprintf("%s IN (%s)", src.QuoteIdentifier(key.SourceColumn), strings.Join(values, ", "))
+	return sourceSampleSelect(src, table, where, n, typeMap)
+}
+
+// buildSourceRandomSampleQuery keeps each row with probability n/rowCount,
+// in parts per million, stopping after n rows.
+func buildSourceRandomSampleQuery(src SourceDB, table Table, n int, rowCount int64, typeMap TypeMappingConfig) string {
+	const scale = 1000000
+	threshold := (int64(n)*scale + rowCount - 1) / rowCount
+	if threshold >= scale {
+		return sourceSampleSelect(src, table, "", n, typeMap)
+	}
+	var where string
+	switch src.Name() {
+	case "MSSQL":
+		where = fmt.Sprintf("ABS(CHECKSUM(NEWID()) %% %d) < %d", scale, threshold)
+	case "SQLite":
+		where = fmt.Sprintf("ABS(RANDOM() %% %d) < %d", scale, threshold)
+	default:
+		where = fmt.Sprintf("RAND() * %d < %d", scale, threshold)
+	}
+	return sourceSampleSelect(src, table, where, n, typeMap)
+}
+
+// buildTargetSampleQuery fetches the target rows matching keyCount sampled
+// primary keys. Key values are bound as text parameters, which PostgreSQL
+// parses with the key column's own input function.
+func buildTargetSampleQuery(pgSchema string, table Table, families []string, pkPositions []int, keyCount int) string {
+	cols := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		cols[i] = pgChecksumSelectExpr(pgIdent(col.PGName), families[i])
+	}
+
+	groups := make([]string, keyCount)
+	param := 1
+	for k := range groups {
+		conds := make([]string, len(pkPositions))
+		for j, pos := range pkPositions {
+			conds[j] = fmt.Sprintf("%s = $%d", pgIdent(table.Columns[pos].PGName), param)
+			param++
+		}
+		groups[k] = "(" + strings.Join(conds, " AND ") + ")"
+	}
+
+	return fmt.Sprintf("SELECT %s FROM %s WHERE %s",
+		strings.Join(cols, ", "), pgQualifiedIdent(pgSchema, table.PGName), strings.Join(groups, " OR "))
+}
+
+// sampleKeyParam renders a transformed source key value in a text form that
+// PostgreSQL accepts as input for the mapped column type.
+func sampleKeyParam(v any, family string) string {
+	switch family {
+	case "numeric":
+		return strings.TrimSpace(checksumRawString(v))
+	case "bytea":
+		if b, ok := v.([]byte); ok {
+			return `\x` + hex.EncodeToString(b)
+		}
+	case "timestamptz":
+		return checksumCanonicalValue(v, family) + "+00"
+	}
+	return checksumCanonicalValue(v, family)
+}
+
+func sampleCanonicalValue(v any, family string) *string {
+	if v == nil {
+		return nil
+	}
+	s := checksumCanonicalValue(v, family)
+	return &s
+}
+
+func sampleRowKey(table Table, pkPositions []int, values []*string) string {
+	parts := make([]string, len(pkPositions))
+	for i, pos := range pkPositions {
+		parts[i] = table.Columns[pos].SourceName + "=" + sampleDisplayValue(values[pos])
+	}
+	return strings.Join(parts, ", ")
+}
+
+func sampleDisplayValue(v *string) string {
+	if v == nil {
+		return "NULL"
+	}
+	return *v
+}
+
+func sampleQuotedValue(v *string) string {
+	if v == nil {
+		return "NULL"
+	}
+	return "'" + *v + "'"
+}
+
+// fetchSourceSample reads the rows selected by query, passing each value through
+// TransformValue so the comparison reflects what the COPY path wrote. It also
+// returns the text parameters used to look the rows up on the target.
+func fetchSourceSample(ctx context.Context, source dbQuerier, src SourceDB, table Table, query string, families []string, pkPositions []int, typeMap TypeMappingConfig) ([]sampleRow, []any, error) {
+	rows, err := source.QueryContext(ctx, query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("select: %w", err)
+	}
+	defer rows.Close()
+
+	scanDest := make([]any, len(table.Columns))
+	scanPtrs := make([]any, len(table.Columns))
+	for i := range scanDest {
+		scanPtrs[i] = &scanDest[i]
+	}
+
+	var sample []sampleRow
+	var params []any
+	for rows.Next() {
+		if err := rows.Scan(scanPtrs...); err != nil {
+			return nil, nil, err
+		}
+		transformed := make([]any, len(table.Columns))
+		values := make([]*string, len(table.Columns))
+		for i, col := range table.Columns {
+			v, err := src.TransformValue(scanDest[i], col, typeMap)
+			if err != nil {
+				return nil, nil, fmt.Errorf("column %s: %w", col.SourceName, err)
+			}
+			transformed[i] = v
+			values[i] = sampleCanonicalValue(v, families[i])
+		}
+		for _, pos := range pkPositions {
+			params = append(params, sampleKeyParam(transformed[pos], families[pos]))
+		}
+		sample = append(sample, sampleRow{key: sampleRowKey(table, pkPositions, values), values: values})
+	}
+	return sample, params, rows.Err()
+}
+
+func fetchTargetSample(ctx context.Context, pool *pgxpool.Pool, table Table, query string, params []any, families []string, pkPositions []int) (map[string][]*string, error) {
+	rows, err := pool.Query(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("select: %w", err)
+	}
+	defer rows.Close()
+
+	byKey := make(map[string][]*string)
+	for rows.Next() {
+		vals, err := rows.Values()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]*string, len(vals))
+		for i, v := range vals {
+			values[i] = sampleCanonicalValue(v, families[i])
+		}
+		byKey[sampleRowKey(table, pkPositions, values)] = values
+	}
+	return byKey, rows.Err()
+}
+
+// compareSampleRows diffs each sampled source row against its target row
+// column by column.
+func compareSampleRows(table Table, sample []sampleRow, target map[string][]*string) []SampleRowDiff {
+	var diffs []SampleRowDiff
+	for _, row := range sample {
+		tgt, ok := target[row.key]
+		if !ok {
+			diffs = append(diffs, SampleRowDiff{Key: row.key, Missing: true})
+			continue
+		}
+		for i, col := range table.Columns {
+			s, t := row.values[i], tgt[i]
+			if (s == nil) == (t == nil) && (s == nil || *s == *t) {
+				continue
+			}
+			diffs = append(diffs, SampleRowDiff{
+				Key:    row.key,
+				Column: col.SourceName,
+				Source: sampleQuotedValue(s),
+				Target: sampleQuotedValue(t),
+			})
+		}
+	}
+	return diffs
+}
+
+// validateTableSample compares row counts, then fetches a random sample of
+// rows by primary key from both sides and diffs them column by column. Tables
+// without a primary key are validated by row count only.
+func validateTableSample(ctx context.Context, cfg validationConfig, srcDB validationSource, tbl Table) (ValidationResult, error) {
+	result, err := validateTableRowCount(ctx, cfg, srcDB, tbl)
+	if err != nil {
+		return result, err
+	}
+
+	pkPositions := samplePrimaryKeyColumns(tbl)
+	if pkPositions == nil || cfg.SampleSize <= 0 || result.SourceCount == 0 {
+		return result, nil
+	}
+
+	families, err := checksumColumnFamilies(tbl, cfg.Src, cfg.TypeMap)
+	if err != nil {
+		return result, fmt.Errorf("sample %s: %w", tbl.SourceName, err)
+	}
+
+	sample, params, err := sampleSourceRows(ctx, srcDB, cfg.Src, tbl, cfg.SampleSize, result.SourceCount, families, pkPositions, cfg.TypeMap)
+	if err != nil {
+		return result, fmt.Errorf("sample source rows for %s: %w", tbl.SourceName, err)
+	}
+	if len(sample) == 0 {
+		return result, nil
+	}
+
+	pgQuery := buildTargetSampleQuery(cfg.PGSchema, tbl, families, pkPositions, len(sample))
+	target, err := fetchTargetSample(ctx, cfg.Pool, tbl, pgQuery, params, families, pkPositions)
+	if err != nil {
+		return result, fmt.Errorf("sample target rows for %s: %w", tbl.PGName, err)
+	}
+
+	result.SampledRows = len(sample)
+	result.RowDiffs = compareSampleRows(tbl, sample, target)
+	return result, nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func sampleTestTable() Table {
+	return Table{
+		SourceName: "users",
+		PGName:     "users",
+		Columns: []Column{
+			{SourceName: "id", PGName: "id", DataType: "int"},
+			{SourceName: "email", PGName: "email", DataType: "varchar"},
+			{SourceName: "bio", PGName: "bio", DataType: "text"},
+		},
+		PrimaryKey: &Index{Columns: []string{"id"}, IsPrimary: true},
+	}
+}
+
+func TestSamplePrimaryKeyColumns(t *testing.T) {
+	table := sampleTestTable()
+	if got := samplePrimaryKeyColumns(table); len(got) != 1 || got[0] != 0 {
+		t.Errorf("samplePrimaryKeyColumns() = %v, want [0]", got)
+	}
+
+	table.PrimaryKey = &Index{Columns: []string{"email", "id"}, IsPrimary: true}
+	if got := samplePrimaryKeyColumns(table); len(got) != 2 || got[0] != 1 || got[1] != 0 {
+		t.Errorf("composite samplePrimaryKeyColumns() = %v, want [1 0]", got)
+	}
+
+	table.PrimaryKey = nil
+	if got := samplePrimaryKeyColumns(table); got != nil {
+		t.Errorf("no-PK samplePrimaryKeyColumns() = %v, want nil", got)
+	}
+}
+
+func TestBuildSourceKeySampleQuery(t *testing.T) {
+	table := sampleTestTable()
+	tm := defaultTypeMappingConfig()
+	key := ChunkKey{SourceColumn: "id", PGColumn: "id"}
+
+	tests := []struct {
+		src  SourceDB
+		want string
+	}{
+		{&mysqlSourceDB{}, "SELECT `id`, `email`, `bio` FROM `users` WHERE `id` IN (3, 7) LIMIT 50"},
+		{&sqliteSourceDB{}, `SELECT "id", "email", "bio" FROM "users" WHERE "id" IN (3, 7) LIMIT 50`},
+	}
+	for _, tt := range tests {
+		if got := buildSourceKeySampleQuery(tt.src, table, key, []int64{3, 7}, 50, tm); got != tt.want {
+			t.Errorf("%s: buildSourceKeySampleQuery() = %q, want %q", tt.src.Name(), got, tt.want)
+		}
+	}
+}
+
+func TestBuildSourceRandomSampleQuery(t *testing.T) {
+	table := sampleTestTable()
+	tm := defaultTypeMappingConfig()
+
+	if got, want := buildSourceRandomSampleQuery(&mysqlSourceDB{}, table, 50, 1000, tm),
+		"SELECT `id`, `email`, `bio` FROM `users` WHERE RAND() * 1000000 < 50000 LIMIT 50"; got != want {
+		t.Errorf("MySQL query = %q, want %q", got, want)
+	}
+	if got, want := buildSourceRandomSampleQuery(&sqliteSourceDB{}, table, 50, 20, tm),
+		`SELECT "id", "email", "bio" FROM "users" LIMIT 50`; got != want {
+		t.Errorf("small table query = %q, want %q", got, want)
+	}
+}
+
+func TestRandomSampleKeys(t *testing.T) {
+	keys := randomSampleKeys(1, 1000000, 100, 1000000)
+	if len(keys) != 100 {
+		t.Fatalf("dense keys = %d, want 100", len(keys))
+	}
+	for i, k := range keys {
+		if k < 1 || k > 1000000 || (i > 0 && k <= keys[i-1]) {
+			t.Fatalf("keys not distinct, sorted and in range: %v", keys)
+		}
+	}
+
+	if got := randomSampleKeys(1, 10000000, 100, 1000000); len(got) != 400 {
+		t.Errorf("sparse keys = %d, want 400 (capped at 4n)", len(got))
+	}
+	if got := randomSampleKeys(5, 9, 100, 5); len(got) != 5 || got[0] != 5 || got[4] != 9 {
+		t.Errorf("small range keys = %v, want 5..9", got)
+	}
+	if got := randomSampleKeys(math.MinInt64, math.MaxInt64, 10, 10); len(got) != 40 {
+		t.Errorf("full range keys = %d, want 40", len(got))
+	}
+}
+
+func TestBuildTargetSampleQuery(t *testing.T) {
+	table := sampleTestTable()
+	table.PrimaryKey = &Index{Columns: []string{"id", "email"}, IsPrimary: true}
+	families := []string{"int", "text", "text"}
+
+	got := buildTargetSampleQuery("app", table, families, []int{0, 1}, 2)
+	want := `SELECT "id", "email"::text, "bio"::text FROM "app"."users" WHERE ("id" = $1 AND "email" = $2) OR ("id" = $3 AND "email" = $4)`
+	if got != want {
+		t.Errorf("buildTargetSampleQuery() = %q, want %q", got, want)
+	}
+}
+
+func TestSampleKeyParam(t *testing.T) {
+	tests := []struct {
+		v      any
+		family string
+		want   string
+	}{
+		{[]byte("42"), "int", "42"},
+		{[]byte("12.50"), "numeric", "12.50"},
+		{[]byte{0xab, 0x01}, "bytea", `\xab01`},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("x", 3600)), "timestamptz", "2024-01-02 02:04:05+00"},
+		{"A0EEBC99-9C0B-4EF8-BB6D-6BB9BD380A11", "uuid", "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"},
+	}
+	for _, tt := range tests {
+		if got := sampleKeyParam(tt.v, tt.family); got != tt.want {
+			t.Errorf("sampleKeyParam(%v, %q) = %q, want %q", tt.v, tt.family, got, tt.want)
+		}
+	}
+}
+
+func TestCompareSampleRows(t *testing.T) {
+	table := sampleTestTable()
+	pk := []int{0}
+	str := func(s string) *string { return &s }
+
+	row := func(values ...*string) sampleRow {
+		return sampleRow{key: sampleRowKey(table, pk, values), values: values}
+	}
+	sample := []sampleRow{
+		row(str("1"), str("a@x"), nil),
+		row(str("123"), str("Foo@x"), str("hi")),
+		row(str("7"), str("b@x"), nil),
+	}
+	target := map[string][]*string{
+		"id=1":   {str("1"), str("a@x"), nil},
+		"id=123": {str("123"), str("foo@x"), nil},
+	}
+
+	diffs := compareSampleRows(table, sample, target)
+	want := []string{
+		"users.id=123 column email: 'Foo@x' vs 'foo@x'",
+		"users.id=123 column bio: 'hi' vs NULL",
+		"users.id=7: row missing in target",
+	}
+	if len(diffs) != len(want) {
+		t.Fatalf("got %d diffs, want %d: %+v", len(diffs), len(want), diffs)
+	}
+	for i, d := range diffs {
+		if got := d.describe("users"); got != want[i] {
+			t.Errorf("diff[%d] = %q, want %q", i, got, want[i])
+		}
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 func TestValidateMigration_NoneMode(t *testing.T) {
-	results, err := validateMigration(context.Background(), nil, "", nil, nil, "", "none", 4)
+	results, err := validateMigration(context.Background(), validationConfig{Mode: "none", Workers: 4})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -16,7 +16,7 @@ func TestValidateMigration_NoneMode(t *testing.T) {
 }
 
 func TestValidateMigration_EmptyMode(t *testing.T) {
-	results, err := validateMigration(context.Background(), nil, "", nil, nil, "", "", 4)
+	results, err := validateMigration(context.Background(), validationConfig{Workers: 4})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -0,0 +1,834 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// sourceIdentNamer is implemented by backends that rename source identifiers
+// (lowercase or snake_case) when building PostgreSQL names.
+type sourceIdentNamer interface {
+	identName(string) string
+}
+
+// sourceIdentName returns the identifier renaming function used by src.
+func sourceIdentName(src SourceDB) func(string) string {
+	if n, ok := src.(sourceIdentNamer); ok {
+		return n.identName
+	}
+	return strings.ToLower
+}
+
+// viewKeywords are bare words that are never treated as identifiers when
+// rewriting view SQL. The DML keywords are used by trigger bodies.
+var viewKeywords = map[string]bool{
+	"SELECT": true, "DISTINCT": true, "ALL": true, "FROM": true, "WHERE": true,
+	"JOIN": true, "INNER": true, "LEFT": true, "RIGHT": true, "FULL": true,
+	"OUTER": true, "CROSS": true, "ON": true, "USING": true, "AS": true,
+	"AND": true, "OR": true, "NOT": true, "IN": true, "IS": true, "NULL": true,
+	"LIKE": true, "BETWEEN": true, "EXISTS": true, "CASE": true, "WHEN": true,
+	"THEN": true, "ELSE": true, "END": true, "GROUP": true, "BY": true,
+	"ORDER": true, "HAVING": true, "LIMIT": true, "OFFSET": true, "UNION": true,
+	"INTERSECT": true, "EXCEPT": true, "ASC": true, "DESC": true, "TOP": true,
+	"WITH": true, "OVER": true, "PARTITION": true, "TRUE": true, "FALSE": true,
+	"CHECK": true, "OPTION": true, "INSERT": true, "INTO": true, "VALUES": true,
+	"UPDATE": true, "SET": true, "DELETE": true,
+}
+
+// viewClauseEnds end a FROM list: table names are no longer expected after them.
+var viewClauseEnds = map[string]bool{
+	"WHERE": true, "GROUP": true, "ORDER": true, "HAVING": true, "LIMIT": true,
+	"UNION": true, "INTERSECT": true, "EXCEPT": true, "ON": true, "USING": true,
+	"WITH": true, "SELECT": true, "SET": true, "VALUES": true,
+}
+
+// viewRewriter rewrites source view SQL into PostgreSQL. It works on the
+// check-expression token stream: identifiers are renamed the way table
+// columns are, table references are qualified with the target schema, and a
+// set of common functions and clauses (IFNULL/ISNULL, IF/IIF, CONCAT, date
+// functions, TOP, LIMIT a,b, CAST/CONVERT) is translated. Everything else is
+// passed through unchanged and left for PostgreSQL to accept or reject.
+type viewRewriter struct {
+	dialect   string
+	tables    map[string]string // lowercased source table name -> PG name
+	columns   map[string]bool   // lowercased source column names
+	srcSchema string
+	pgSchema  string
+	identName func(string) string
+}
+
+func newViewRewriter(schema *Schema, src SourceDB, srcSchema, pgSchema string) *viewRewriter {
+	r := &viewRewriter{
+		dialect:   checkDialect(src),
+		tables:    make(map[string]string),
+		columns:   make(map[string]bool),
+		srcSchema: srcSchema,
+		pgSchema:  pgSchema,
+		identName: sourceIdentName(src),
+	}
+	if schema != nil {
+		for _, t := range schema.Tables {
+			r.tables[strings.ToLower(t.SourceName)] = t.PGName
+			for _, c := range t.Columns {
+				r.columns[strings.ToLower(c.SourceName)] = true
+			}
+		}
+	}
+	return r
+}
+
+// translateViews translates every introspected view. Views are returned in
+// source order; untranslatable ones carry the reason.
+func translateViews(objs *SourceObjects, schema *Schema, src SourceDB, pgSchema string) []View {
+	if objs == nil || len(objs.Views) == 0 {
+		return nil
+	}
+	r := newViewRewriter(schema, src, objs.SchemaName, pgSchema)
+	views := make([]View, 0, len(objs.Views))
+	for _, name := range objs.Views {
+		v := View{SourceName: name, PGName: r.identName(name), Definition: objs.ViewDefinitions[name]}
+		// Views get their own names so a reference to another view is
+		// qualified like a table reference.
+		r.tables[strings.ToLower(name)] = v.PGName
+		views = append(views, v)
+	}
+	for i := range views {
+		v := &views[i]
+		if v.Definition == "" {
+			v.Reason = "definition unavailable (missing privileges or encrypted view)"
+			continue
+		}
+		sql, err := r.translate(v.PGName, v.Definition)
+		if err != nil {
+			v.Reason = err.Error()
+			continue
+		}
+		v.SQL = sql
+	}
+	return views
+}
+
+// countTranslatedViews returns how many views have a CREATE VIEW statement.
+func countTranslatedViews(views []View) int {
+	n := 0
+	for _, v := range views {
+		if v.SQL != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// translate returns the CREATE VIEW statement for one view definition.
+func (r *viewRewriter) translate(pgName, definition string) (string, error) {
+	text := strings.TrimSpace(stripSQLComments(definition))
+	text = strings.TrimSpace(strings.TrimSuffix(text, ";"))
+	tokens, err := tokenizeCheckExpression(text, r.dialect)
+	if err != nil {
+		return "", err
+	}
+
+	var columns string
+	if r.dialect != "mysql" {
+		body, cols, err := r.splitViewHeader(tokens)
+		if err != nil {
+			return "", err
+		}
+		tokens = body
+		if len(cols) > 0 {
+			columns = " (" + strings.Join(cols, ", ") + ")"
+		}
+	}
+	if len(tokens) == 0 {
+		return "", fmt.Errorf("empty view body")
+	}
+
+	body, err := r.rewrite(tokens)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("CREATE VIEW %s.%s%s AS %s", pgIdent(r.pgSchema), pgIdent(pgName), columns, body), nil
+}
+
+// splitViewHeader strips CREATE [OR ALTER] [TEMP] VIEW [IF NOT EXISTS] name
+// [(columns)] [WITH options] AS from an MSSQL or SQLite definition and
+// returns the SELECT tokens and the renamed column list.
+func (r *viewRewriter) splitViewHeader(tokens []checkToken) ([]checkToken, []string, error) {
+	i := 0
+	for i < len(tokens) && !(tokens[i].kind == checkTokenWord && strings.EqualFold(tokens[i].text, "VIEW")) {
+		i++
+	}
+	if i == len(tokens) {
+		return nil, nil, fmt.Errorf("CREATE VIEW header not found")
+	}
+	i++
+	// Skip IF NOT EXISTS and the (possibly qualified) view name.
+	for i < len(tokens) {
+		tok := tokens[i]
+		if tok.kind == checkTokenWord && strings.EqualFold(tok.text, "AS") ||
+			tok.kind == checkTokenWord && strings.EqualFold(tok.text, "WITH") ||
+			tok.kind == checkTokenLParen {
+			break
+		}
+		i++
+	}
+
+	var cols []string
+	if i < len(tokens) && tokens[i].kind == checkTokenLParen {
+		end, err := viewMatchingParen(tokens, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, tok := range tokens[i+1 : end] {
+			switch tok.kind {
+			case checkTokenComma:
+			case checkTokenWord, checkTokenIdent:
+				cols = append(cols, pgIdent(r.identName(tok.text)))
+			default:
+				return nil, nil, fmt.Errorf("unexpected %q in view column list", tok.text)
+			}
+		}
+		i = end + 1
+	}
+	if i < len(tokens) && tokens[i].kind == checkTokenWord && strings.EqualFold(tokens[i].text, "WITH") {
+		// WITH SCHEMABINDING, VIEW_METADATA, ... have no PostgreSQL meaning.
+		i++
+		for i < len(tokens) && !(tokens[i].kind == checkTokenWord && strings.EqualFold(tokens[i].text, "AS")) {
+			i++
+		}
+	}
+	if i >= len(tokens) || !strings.EqualFold(tokens[i].text, "AS") {
+		return nil, nil, fmt.Errorf("expected AS after view name")
+	}
+	return tokens[i+1:], cols, nil
+}
+
+// rewrite translates one token sequence (a whole query or the inside of a
+// parenthesized group).
+func (r *viewRewriter) rewrite(tokens []checkToken) (string, error) {
+	var out []string
+	var limit string
+	tableExpected, inFrom := false, false
+
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.kind {
+		case checkTokenLParen:
+			end, err := viewMatchingParen(tokens, i)
+			if err != nil {
+				return "", err
+			}
+			inner, err := r.rewrite(tokens[i+1 : end])
+			if err != nil {
+				return "", err
+			}
+			out = append(out, "("+inner+")")
+			i = end
+			tableExpected = false
+		case checkTokenRParen:
+			return "", fmt.Errorf("unbalanced parentheses")
+		case checkTokenComma:
+			out = append(out, ",")
+			tableExpected = inFrom
+		case checkTokenString:
+			out = append(out, pgLiteral(tok.text))
+		case checkTokenNumber:
+			out = append(out, tok.text)
+		case checkTokenOp:
+			op, err := r.operator(tokens, i)
+			if err != nil {
+				return "", err
+			}
+			out = append(out, op)
+		case checkTokenWord:
+			upper := strings.ToUpper(tok.text)
+			next := viewTokenAt(tokens, i+1)
+
+			if upper == "TOP" && r.dialect == "mssql" {
+				n, consumed, err := viewTopCount(tokens[i+1:])
+				if err != nil {
+					return "", err
+				}
+				limit = n
+				i += consumed
+				continue
+			}
+			if upper == "LIMIT" && r.dialect == "mysql" && len(tokens) >= i+4 &&
+				tokens[i+1].kind == checkTokenNumber && tokens[i+2].kind == checkTokenComma && tokens[i+3].kind == checkTokenNumber {
+				out = append(out, "LIMIT", tokens[i+3].text, "OFFSET", tokens[i+1].text)
+				i += 3
+				inFrom, tableExpected = false, false
+				continue
+			}
+			if viewKeywords[upper] {
+				out = append(out, upper)
+				switch {
+				case upper == "FROM" || upper == "JOIN" || upper == "INTO" || upper == "UPDATE":
+					inFrom, tableExpected = true, true
+				case viewClauseEnds[upper]:
+					inFrom, tableExpected = false, false
+				}
+				continue
+			}
+			if next != nil && next.kind == checkTokenLParen {
+				end, err := viewMatchingParen(tokens, i+1)
+				if err != nil {
+					return "", err
+				}
+				call, err := r.function(upper, tok.text, tokens[i+2:end])
+				if err != nil {
+					return "", err
+				}
+				out = append(out, call)
+				i = end
+				continue
+			}
+			name, consumed, err := r.name(tokens[i:], tableExpected)
+			if err != nil {
+				return "", err
+			}
+			out = append(out, name)
+			i += consumed - 1
+			tableExpected = false
+		case checkTokenIdent:
+			name, consumed, err := r.name(tokens[i:], tableExpected)
+			if err != nil {
+				return "", err
+			}
+			out = append(out, name)
+			i += consumed - 1
+			tableExpected = false
+		}
+	}
+	if limit != "" {
+		out = append(out, "LIMIT", limit)
+	}
+
+	var b strings.Builder
+	for i, piece := range out {
+		if i > 0 && piece != "," {
+			b.WriteByte(' ')
+		}
+		b.WriteString(piece)
+	}
+	return b.String(), nil
+}
+
+// operator translates the operator at tokens[i].
+func (r *viewRewriter) operator(tokens []checkToken, i int) (string, error) {
+	op := tokens[i].text
+	switch op {
+	case "==":
+		return "=", nil
+	case "!=":
+		return "<>", nil
+	case "->", "->>":
+		return "", fmt.Errorf("JSON operator %s is not translated", op)
+	case "+":
+		// MSSQL concatenates strings with +; only rewrite when a string
+		// literal makes the intent unambiguous.
+		if r.dialect == "mssql" {
+			prev, next := viewTokenAt(tokens, i-1), viewTokenAt(tokens, i+1)
+			if prev != nil && prev.kind == checkTokenString || next != nil && next.kind == checkTokenString {
+				return "||", nil
+			}
+		}
+	}
+	return op, nil
+}
+
+// name renders a possibly qualified name starting at tokens[0] and returns
+// how many tokens it used. A leading source schema becomes the target schema,
+// and a bare table name in table position is schema-qualified.
+func (r *viewRewriter) name(tokens []checkToken, tablePosition bool) (string, int, error) {
+	type part struct {
+		text   string
+		quoted bool
+	}
+	parts := []part{{tokens[0].text, tokens[0].kind == checkTokenIdent}}
+	n := 1
+	for n+1 < len(tokens) && tokens[n].kind == checkTokenOp && tokens[n].text == "." {
+		next := tokens[n+1]
+		switch {
+		case next.kind == checkTokenIdent, next.kind == checkTokenWord:
+			parts = append(parts, part{next.text, next.kind == checkTokenIdent})
+		case next.kind == checkTokenOp && next.text == "*":
+			parts = append(parts, part{"*", false})
+		default:
+			return "", 0, fmt.Errorf("unexpected %q after '.'", next.text)
+		}
+		n += 2
+	}
+
+	rename := func(p part) string {
+		if p.text == "*" {
+			return "*"
+		}
+		lower := strings.ToLower(p.text)
+		if pg, ok := r.tables[lower]; ok {
+			return pgIdent(pg)
+		}
+		if p.quoted || r.columns[lower] {
+			return pgIdent(r.identName(p.text))
+		}
+		return p.text
+	}
+
+	var rendered []string
+	switch {
+	case len(parts) >= 2 && r.srcSchema != "" && strings.EqualFold(parts[0].text, r.srcSchema):
+		rendered = append(rendered, pgIdent(r.pgSchema))
+		for _, p := range parts[1:] {
+			rendered = append(rendered, rename(p))
+		}
+	case len(parts) >= 3:
+		return "", 0, fmt.Errorf("reference %q crosses databases or schemas", tokens[0].text)
+	case len(parts) == 1 && tablePosition:
+		if pg, ok := r.tables[strings.ToLower(parts[0].text)]; ok {
+			rendered = append(rendered, pgIdent(r.pgSchema), pgIdent(pg))
+			break
+		}
+		rendered = append(rendered, rename(parts[0]))
+	default:
+		for _, p := range parts {
+			rendered = append(rendered, rename(p))
+		}
+	}
+	if len(rendered) > 3 {
+		return "", 0, fmt.Errorf("unsupported qualified name %q", tokens[0].text)
+	}
+	return strings.Join(rendered, "."), n, nil
+}
+
+// function translates a function call. name is upper-cased; display keeps the
+// source spelling for functions passed through.
+func (r *viewRewriter) function(name, display string, argTokens []checkToken) (string, error) {
+	rawArgs := viewSplitArgs(argTokens)
+
+	// MSSQL date functions start with a bare datepart keyword.
+	if r.dialect == "mssql" && (name == "DATEADD" || name == "DATEDIFF" || name == "DATEPART") {
+		if len(rawArgs) == 0 || len(rawArgs[0]) != 1 || rawArgs[0][0].kind != checkTokenWord {
+			return "", fmt.Errorf("%s() needs a datepart", strings.ToLower(name))
+		}
+		part := genDateParts[strings.ToUpper(rawArgs[0][0].text)]
+		args, err := r.rewriteArgs(rawArgs[1:])
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case part == "":
+			return "", fmt.Errorf("unsupported datepart %s", strings.ToLower(rawArgs[0][0].text))
+		case name == "DATEPART" && len(args) == 1:
+			return fmt.Sprintf("CAST(EXTRACT(%s FROM %s) AS integer)", part, args[0]), nil
+		case name == "DATEADD" && len(args) == 2 && part != "DOY" && part != "QUARTER":
+			return fmt.Sprintf("(%s + (%s) * INTERVAL '1 %s')", args[1], args[0], strings.ToLower(part)), nil
+		case name == "DATEDIFF" && len(args) == 2 && (part == "DAY" || part == "DOY"):
+			return fmt.Sprintf("(CAST(%s AS date) - CAST(%s AS date))", args[1], args[0]), nil
+		}
+		return "", fmt.Errorf("unsupported %s(%s, ...)", strings.ToLower(name), strings.ToLower(rawArgs[0][0].text))
+	}
+
+	if name == "CAST" || name == "CONVERT" {
+		return r.cast(name, rawArgs)
+	}
+	if (name == "DATE_ADD" || name == "DATE_SUB") && r.dialect == "mysql" {
+		return r.mysqlDateAdd(name, rawArgs)
+	}
+
+	args, err := r.rewriteArgs(rawArgs)
+	if err != nil {
+		return "", err
+	}
+	argc := len(args)
+	mysql, mssql, sqlite := r.dialect == "mysql", r.dialect == "mssql", r.dialect == "sqlite"
+
+	switch {
+	case (name == "IFNULL" && (mysql || sqlite) || name == "ISNULL" && mssql) && argc == 2:
+		return "COALESCE(" + strings.Join(args, ", ") + ")", nil
+	case (name == "IF" && mysql || name == "IIF" && (mssql || sqlite)) && argc == 3:
+		return fmt.Sprintf("CASE WHEN %s THEN %s ELSE %s END", args[0], args[1], args[2]), nil
+	case name == "CONCAT" && mysql && argc > 0:
+		// MySQL CONCAT is NULL when any argument is; || behaves the same.
+		parts := make([]string, argc)
+		for i, a := range args {
+			parts[i] = "CAST(" + a + " AS text)"
+		}
+		return "(" + strings.Join(parts, " || ") + ")", nil
+	case (name == "UCASE" || name == "LCASE") && mysql && argc == 1:
+		return map[string]string{"UCASE": "upper", "LCASE": "lower"}[name] + "(" + args[0] + ")", nil
+	case name == "LENGTH" && mysql && argc == 1:
+		return "octet_length(" + args[0] + ")", nil
+	case name == "LEN" && mssql && argc == 1:
+		return "length(rtrim(" + args[0] + "))", nil
+	case name == "CHARINDEX" && mssql && argc == 2:
+		return fmt.Sprintf("strpos(%s, %s)", args[1], args[0]), nil
+	case (name == "CURDATE" || name == "CURRENT_DATE") && mysql && argc == 0:
+		return "CURRENT_DATE", nil
+	case name == "CURTIME" && mysql && argc == 0:
+		return "LOCALTIME", nil
+	case (name == "NOW" || name == "SYSDATE" || name == "CURRENT_TIMESTAMP" || name == "LOCALTIMESTAMP") && mysql && argc == 0:
+		return "LOCALTIMESTAMP", nil
+	case (name == "GETDATE" || name == "SYSDATETIME") && mssql && argc == 0:
+		return "LOCALTIMESTAMP", nil
+	case (name == "GETUTCDATE" || name == "SYSUTCDATETIME") && mssql && argc == 0:
+		return "(now() AT TIME ZONE 'UTC')", nil
+	case name == "NEWID" && mssql && argc == 0:
+		return "gen_random_uuid()", nil
+	case name == "RAND" && mysql && argc == 0:
+		return "random()", nil
+	case name == "DATE" && mysql && argc == 1:
+		return "CAST(" + args[0] + " AS date)", nil
+	case name == "DATEDIFF" && mysql && argc == 2:
+		return fmt.Sprintf("(CAST(%s AS date) - CAST(%s AS date))", args[0], args[1]), nil
+	case (mysql && genMySQLDateFunctions[name] || mssql && (name == "YEAR" || name == "MONTH" || name == "DAY")) && argc == 1:
+		return fmt.Sprintf("CAST(EXTRACT(%s FROM %s) AS integer)", genDateParts[name], args[0]), nil
+	}
+	return display + "(" + strings.Join(args, ", ") + ")", nil
+}
+
+// rewriteArgs rewrites each argument token slice.
+func (r *viewRewriter) rewriteArgs(raw [][]checkToken) ([]string, error) {
+	args := make([]string, len(raw))
+	for i, a := range raw {
+		s, err := r.rewrite(a)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = s
+	}
+	return args, nil
+}
+
+// cast translates CAST(x AS type) and CONVERT in both dialects, mapping the
+// common source type names.
+func (r *viewRewriter) cast(name string, raw [][]checkToken) (string, error) {
+	var exprTokens, typeTokens []checkToken
+	switch {
+	case name == "CAST" && len(raw) == 1:
+		for i, tok := range raw[0] {
+			if tok.kind == checkTokenWord && strings.EqualFold(tok.text, "AS") {
+				exprTokens, typeTokens = raw[0][:i], raw[0][i+1:]
+				break
+			}
+		}
+	case name == "CONVERT" && r.dialect == "mssql" && len(raw) == 2:
+		// CONVERT(type, expr); a third style argument changes formatting.
+		exprTokens, typeTokens = raw[1], raw[0]
+	case name == "CONVERT" && r.dialect == "mysql" && len(raw) == 2:
+		exprTokens, typeTokens = raw[0], raw[1]
+	case name == "CONVERT" && r.dialect == "mysql" && len(raw) == 1:
+		// CONVERT(x USING charset) only changes the character set.
+		for i, tok := range raw[0] {
+			if tok.kind == checkTokenWord && strings.EqualFold(tok.text, "USING") {
+				return r.rewrite(raw[0][:i])
+			}
+		}
+	}
+	if len(exprTokens) == 0 || len(typeTokens) == 0 {
+		return "", fmt.Errorf("unsupported %s() form", strings.ToLower(name))
+	}
+	expr, err := r.rewrite(exprTokens)
+	if err != nil {
+		return "", err
+	}
+	pgType, err := viewCastType(typeTokens)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("CAST(%s AS %s)", expr, pgType), nil
+}
+
+// viewCastType maps a MySQL or MSSQL CAST target type to PostgreSQL.
+func viewCastType(tokens []checkToken) (string, error) {
+	var words []string
+	var length []string
+	for i := 0; i < len(tokens); i++ {
+		tok := tokens[i]
+		switch tok.kind {
+		case checkTokenWord, checkTokenIdent:
+			upper := strings.ToUpper(tok.text)
+			if upper == "CHARSET" || upper == "CHARACTER" && len(words) > 0 {
+				// charset clause: drop it and its argument
+				i = len(tokens)
+				continue
+			}
+			words = append(words, upper)
+		case checkTokenLParen:
+			end, err := viewMatchingParen(tokens, i)
+			if err != nil {
+				return "", err
+			}
+			for _, t := range tokens[i+1 : end] {
+				if t.kind != checkTokenComma {
+					length = append(length, t.text)
+				}
+			}
+			i = end
+		default:
+			return "", fmt.Errorf("unsupported cast type")
+		}
+	}
+	if len(words) == 0 {
+		return "", fmt.Errorf("unsupported cast type")
+	}
+	args := ""
+	if len(length) > 0 {
+		args = "(" + strings.Join(length, ",") + ")"
+	}
+	isMax := len(length) == 1 && strings.EqualFold(length[0], "max")
+	switch strings.Join(words, " ") {
+	case "SIGNED", "SIGNED INTEGER", "SIGNED INT", "UNSIGNED", "UNSIGNED INTEGER", "UNSIGNED INT", "BIGINT":
+		return "bigint", nil
+	case "INT", "INTEGER":
+		return "integer", nil
+	case "SMALLINT", "TINYINT":
+		return "smallint", nil
+	case "CHAR", "NCHAR", "VARCHAR", "NVARCHAR":
+		switch {
+		case args == "" || isMax:
+			return "text", nil
+		case words[0] == "CHAR" || words[0] == "NCHAR":
+			return "char" + args, nil
+		}
+		return "varchar" + args, nil
+	case "DECIMAL", "NUMERIC":
+		return "numeric" + args, nil
+	case "FLOAT", "DOUBLE", "REAL":
+		return "double precision", nil
+	case "DATE":
+		return "date", nil
+	case "DATETIME", "DATETIME2", "SMALLDATETIME":
+		return "timestamp", nil
+	case "TIME":
+		return "time", nil
+	case "BIT":
+		return "boolean", nil
+	case "JSON":
+		return "jsonb", nil
+	default:
+		return "", fmt.Errorf("unsupported cast type %s", strings.ToLower(strings.Join(words, " ")))
+	}
+}
+
+// mysqlDateAdd translates DATE_ADD/DATE_SUB(x, INTERVAL n unit).
+func (r *viewRewriter) mysqlDateAdd(name string, raw [][]checkToken) (string, error) {
+	if len(raw) != 2 || len(raw[1]) < 3 || !strings.EqualFold(raw[1][0].text, "INTERVAL") {
+		return "", fmt.Errorf("unsupported %s() form", strings.ToLower(name))
+	}
+	unitTok := raw[1][len(raw[1])-1]
+	unit := strings.ToLower(unitTok.text)
+	switch unit {
+	case "second", "minute", "hour", "day", "week", "month", "year":
+	default:
+		return "", fmt.Errorf("unsupported interval unit %s", unit)
+	}
+	base, err := r.rewrite(raw[0])
+	if err != nil {
+		return "", err
+	}
+	n, err := r.rewrite(raw[1][1 : len(raw[1])-1])
+	if err != nil {
+		return "", err
+	}
+	op := "+"
+	if name == "DATE_SUB" {
+		op = "-"
+	}
+	return fmt.Sprintf("(%s %s (%s) * INTERVAL '1 %s')", base, op, n, unit), nil
+}
+
+// viewTopCount parses the count of an MSSQL TOP clause and returns it with
+// the number of tokens consumed.
+func viewTopCount(tokens []checkToken) (string, int, error) {
+	var count string
+	consumed := 0
+	switch {
+	case len(tokens) >= 1 && tokens[0].kind == checkTokenNumber:
+		count, consumed = tokens[0].text, 1
+	case len(tokens) >= 3 && tokens[0].kind == checkTokenLParen && tokens[1].kind == checkTokenNumber && tokens[2].kind == checkTokenRParen:
+		count, consumed = tokens[1].text, 3
+	default:
+		return "", 0, fmt.Errorf("unsupported TOP clause")
+	}
+	if next := viewTokenAt(tokens, consumed); next != nil && next.kind == checkTokenWord &&
+		(strings.EqualFold(next.text, "PERCENT") || strings.EqualFold(next.text, "WITH")) {
+		return "", 0, fmt.Errorf("TOP ... %s is not translated", strings.ToUpper(next.text))
+	}
+	return count, consumed, nil
+}
+
+func viewTokenAt(tokens []checkToken, i int) *checkToken {
+	if i < 0 || i >= len(tokens) {
+		return nil
+	}
+	return &tokens[i]
+}
+
+// viewMatchingParen returns the index of the parenthesis closing tokens[open].
+func viewMatchingParen(tokens []checkToken, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(tokens); i++ {
+		switch tokens[i].kind {
+		case checkTokenLParen:
+			depth++
+		case checkTokenRParen:
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("unbalanced parentheses")
+}
+
+// viewSplitArgs splits function arguments at top-level commas.
+func viewSplitArgs(tokens []checkToken) [][]checkToken {
+	if len(tokens) == 0 {
+		return nil
+	}
+	var args [][]checkToken
+	depth, start := 0, 0
+	for i, tok := range tokens {
+		switch tok.kind {
+		case checkTokenLParen:
+			depth++
+		case checkTokenRParen:
+			depth--
+		case checkTokenComma:
+			if depth == 0 {
+				args = append(args, tokens[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(args, tokens[start:])
+}
+
+// stripSQLComments removes -- and /* */ comments outside quoted text.
+func stripSQLComments(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`' || c == '[':
+			closeCh := c
+			if c == '[' {
+				closeCh = ']'
+			}
+			j := i + 1
+			for j < len(s) {
+				if s[j] == closeCh {
+					if j+1 < len(s) && s[j+1] == closeCh {
+						j += 2
+						continue
+					}
+					break
+				}
+				if s[j] == '\\' && c == '\'' && j+1 < len(s) {
+					j++
+				}
+				j++
+			}
+			if j >= len(s) {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteString(s[i : j+1])
+			i = j
+		case c == '-' && i+1 < len(s) && s[i+1] == '-':
+			for i+1 < len(s) && s[i+1] != '\n' {
+				i++
+			}
+			b.WriteByte(' ')
+		case c == '/' && i+1 < len(s) && s[i+1] == '*':
+			end := strings.Index(s[i+2:], "*/")
+			if end < 0 {
+				return b.String()
+			}
+			i += end + 3
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// createViews creates every translated view. Views may depend on each other,
+// so failed ones are retried while any progress is made; what still fails gets
+// its error recorded in CreateError, for the end-of-run summary, and is left
+// for an after_all hook.
+func createViews(ctx context.Context, pool *pgxpool.Pool, views []View, pgSchema string) error {
+	var pending []int
+	skipped := 0
+	for i, v := range views {
+		if v.SQL == "" {
+			log.Printf("    skipping view %s: %s", v.SourceName, v.Reason)
+			skipped++
+			continue
+		}
+		pending = append(pending, i)
+	}
+
+	created := 0
+	errs := make(map[int]error)
+	for len(pending) > 0 {
+		var retry []int
+		for _, i := range pending {
+			v := views[i]
+			if err := createView(ctx, pool, v, pgSchema); err != nil {
+				errs[i] = err
+				retry = append(retry, i)
+				continue
+			}
+			log.Printf("    view %s.%s", pgSchema, v.PGName)
+			created++
+		}
+		if len(retry) == len(pending) {
+			break
+		}
+		pending = retry
+	}
+	for _, i := range pending {
+		views[i].CreateError = errs[i].Error()
+		log.Printf("    WARN: view %s could not be created: %v\n      SQL: %s", views[i].SourceName, errs[i], views[i].SQL)
+	}
+	if created == 0 {
+		log.Printf("    no views created (%d skipped, %d failed)", skipped, len(pending))
+	}
+	return nil
+}
+
+// failedViewWarnings lists the translated views that could not be created on
+// the target, for the end-of-run summary.
+func failedViewWarnings(views []View) []string {
+	var warnings []string
+	for _, v := range views {
+		if v.CreateError != "" {
+			warnings = append(warnings, fmt.Sprintf("view %s → %s: %s", v.SourceName, v.PGName, v.CreateError))
+		}
+	}
+	return warnings
+}
+
+// createView runs one CREATE VIEW with the target schema first on the
+// search_path, so unqualified references the rewriter left alone resolve to
+// the migrated tables.
+func createView(ctx context.Context, pool *pgxpool.Pool, v View, pgSchema string) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL search_path TO %s, public", pgIdent(pgSchema))); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, v.SQL); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTranslateViews(t *testing.T) {
+	schema := &Schema{Tables: []Table{
+		{
+			SourceName: "Orders",
+			PGName:     "orders",
+			Columns: []Column{
+				{SourceName: "Id", PGName: "id"},
+				{SourceName: "CustomerName", PGName: "customername"},
+				{SourceName: "Total", PGName: "total"},
+				{SourceName: "CreatedAt", PGName: "createdat"},
+				{SourceName: "Note", PGName: "note"},
+			},
+		},
+		{
+			SourceName: "Customers",
+			PGName:     "customers",
+			Columns:    []Column{{SourceName: "Id", PGName: "id"}, {SourceName: "Name", PGName: "name"}},
+		},
+	}}
+
+	tests := []struct {
+		name       string
+		src        SourceDB
+		srcSchema  string
+		definition string
+		want       string
+		wantErr    string
+	}{
+		{
+			name:       "mysql qualified select",
+			src:        mysqlSrc,
+			srcSchema:  "shop",
+			definition: "select `shop`.`Orders`.`Id` AS `Id`,ifnull(`shop`.`Orders`.`Note`,'-') AS `Note` from `shop`.`Orders` where (`shop`.`Orders`.`Total` > 10)",
+			want:       `CREATE VIEW "app"."v" AS SELECT "app"."orders"."id" AS "id", COALESCE("app"."orders"."note", '-') AS "note" FROM "app"."orders" WHERE ("app"."orders"."total" > 10)`,
+		},
+		{
+			name:       "mysql concat and limit offset",
+			src:        mysqlSrc,
+			srcSchema:  "shop",
+			definition: "select concat(`o`.`CustomerName`,': ',`o`.`Total`) AS `label` from `shop`.`Orders` `o` limit 5,10",
+			want:       `CREATE VIEW "app"."v" AS SELECT (CAST("o"."customername" AS text) || CAST(': ' AS text) || CAST("o"."total" AS text)) AS "label" FROM "app"."orders" "o" LIMIT 10 OFFSET 5`,
+		},
+		{
+			name:       "mysql date functions",
+			src:        mysqlSrc,
+			srcSchema:  "shop",
+			definition: "select year(`Orders`.`CreatedAt`) AS `y`,date_add(`Orders`.`CreatedAt`,interval 1 day) AS `due`,if((`Orders`.`Total` > 0),'paid','open') AS `state` from `Orders`",
+			want:       `CREATE VIEW "app"."v" AS SELECT CAST(EXTRACT(YEAR FROM "orders"."createdat") AS integer) AS "y", ("orders"."createdat" + (1) * INTERVAL '1 day') AS "due", CASE WHEN ("orders"."total" > 0) THEN 'paid' ELSE 'open' END AS "state" FROM "app"."orders"`,
+		},
+		{
+			name:       "mssql top and joins",
+			src:        &mssqlSourceDB{},
+			srcSchema:  "dbo",
+			definition: "-- report\nCREATE VIEW [dbo].[TopOrders] WITH SCHEMABINDING AS\nSELECT TOP (3) o.[Id], c.Name + ' (' + CONVERT(varchar(10), o.Total) + ')' AS Label, ISNULL(o.Note, N'') AS Note\nFROM dbo.Orders o INNER JOIN dbo.Customers AS c ON c.Id = o.Id\nORDER BY o.CreatedAt DESC;",
+			want:       `CREATE VIEW "app"."v" AS SELECT o."id", c."name" || ' (' || CAST(o."total" AS varchar(10)) || ')' AS Label, COALESCE(o."note", '') AS "note" FROM "app"."orders" o INNER JOIN "app"."customers" AS c ON c."id" = o."id" ORDER BY o."createdat" DESC LIMIT 3`,
+		},
+		{
+			name:       "mssql column list and date functions",
+			src:        &mssqlSourceDB{},
+			srcSchema:  "dbo",
+			definition: "CREATE VIEW dbo.Recent (OrderId, Age) AS SELECT Id, DATEDIFF(day, CreatedAt, GETDATE()) FROM Orders WHERE DATEADD(month, 1, CreatedAt) > GETDATE()",
+			want:       `CREATE VIEW "app"."v" ("orderid", "age") AS SELECT "id", (CAST(LOCALTIMESTAMP AS date) - CAST("createdat" AS date)) FROM "app"."orders" WHERE ("createdat" + (1) * INTERVAL '1 month') > LOCALTIMESTAMP`,
+		},
+		{
+			name:       "sqlite view",
+			src:        &sqliteSourceDB{},
+			srcSchema:  "main",
+			definition: `CREATE VIEW IF NOT EXISTS "Big Orders" AS SELECT "Id", iif(Total > 100, 'big', 'small') AS size FROM Orders`,
+			want:       `CREATE VIEW "app"."v" AS SELECT "id", CASE WHEN "total" > 100 THEN 'big' ELSE 'small' END AS size FROM "app"."orders"`,
+		},
+		{
+			name:       "cross database reference",
+			src:        mysqlSrc,
+			srcSchema:  "shop",
+			definition: "select `other`.`t`.`id` AS `id` from `other`.`t`",
+			wantErr:    "crosses databases",
+		},
+		{
+			name:       "top percent",
+			src:        &mssqlSourceDB{},
+			srcSchema:  "dbo",
+			definition: "CREATE VIEW v AS SELECT TOP 10 PERCENT Id FROM Orders",
+			wantErr:    "TOP ... PERCENT",
+		},
+		{
+			name:       "json operator",
+			src:        mysqlSrc,
+			srcSchema:  "shop",
+			definition: "select `Orders`.`Note`->>'$.a' AS `a` from `Orders`",
+			wantErr:    "JSON operator",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newViewRewriter(schema, tt.src, tt.srcSchema, "app")
+			got, err := r.translate("v", tt.definition)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("translate() error = %v, want containing %q (got %q)", err, tt.wantErr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("translate() error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("translate() =\n  %s\nwant\n  %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateViews_ReferencesOtherViews(t *testing.T) {
+	objs := &SourceObjects{
+		Views: []string{"ActiveUsers", "Hidden", "BaseView"},
+		ViewDefinitions: map[string]string{
+			"ActiveUsers": "select `u`.`id` AS `id` from `shop`.`BaseView` `u`",
+			"BaseView":    "select 1 AS `id`",
+		},
+		SchemaName: "shop",
+	}
+
+	views := translateViews(objs, &Schema{}, mysqlSrc, "app")
+	if len(views) != 3 {
+		t.Fatalf("views = %d, want 3", len(views))
+	}
+	if want := `CREATE VIEW "app"."activeusers" AS SELECT "u"."id" AS "id" FROM "app"."baseview" "u"`; views[0].SQL != want {
+		t.Errorf("views[0].SQL = %q, want %q", views[0].SQL, want)
+	}
+	if views[1].SQL != "" || !strings.Contains(views[1].Reason, "definition unavailable") {
+		t.Errorf("views[1] = %+v, want unavailable definition", views[1])
+	}
+	if got := countTranslatedViews(views); got != 2 {
+		t.Errorf("countTranslatedViews() = %d, want 2", got)
+	}
+}
+
+func TestStripSQLComments(t *testing.T) {
+	got := stripSQLComments("SELECT '--x' AS a -- trailing\nFROM t /* block */ WHERE b = '/*'")
+	want := "SELECT '--x' AS a  \nFROM t   WHERE b = '/*'"
+	if got != want {
+		t.Errorf("stripSQLComments() = %q, want %q", got, want)
+	}
+}
+
+func TestFailedViewWarnings(t *testing.T) {
+	views := []View{
+		{SourceName: "v_ok", PGName: "v_ok", SQL: "CREATE VIEW v_ok AS SELECT 1"},
+		{SourceName: "v_untranslated", PGName: "v_untranslated", Reason: "unsupported function"},
+		{SourceName: "vBad", PGName: "v_bad", SQL: "CREATE VIEW v_bad AS SELECT f()", CreateError: "function f() does not exist"},
+	}
+	got := failedViewWarnings(views)
+	if len(got) != 1 || got[0] != "view vBad → v_bad: function f() does not exist" {
+		t.Fatalf("failedViewWarnings() = %v", got)
+	}
+}